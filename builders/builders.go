@@ -6,6 +6,7 @@ package builders
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -95,3 +96,14 @@ func Image(f fn.Function, builder string, defaults map[string]string) (string, e
 	return "", ErrNoDefaultImage{Builder: builder, Runtime: f.Runtime}
 
 }
+
+// Runtimes returns the sorted names of runtimes for which a default
+// builder image exists in the provided defaults map.
+func Runtimes(defaults map[string]string) []string {
+	runtimes := make([]string, 0, len(defaults))
+	for runtime := range defaults {
+		runtimes = append(runtimes, runtime)
+	}
+	sort.Strings(runtimes)
+	return runtimes
+}
@@ -0,0 +1,114 @@
+package builders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+// PluginPrefix is the name prefix of builder plugin executables, discovered
+// on PATH.  A function whose builder is set to "foo" is built by the
+// executable "func-builder-foo", mirroring the kubectl/git plugin model.
+const PluginPrefix = "func-builder-"
+
+// PluginName returns the executable name of the builder plugin for the
+// given builder short name.
+func PluginName(name string) string {
+	return PluginPrefix + name
+}
+
+// PluginInstalled returns whether a builder plugin executable for the given
+// builder short name is available on PATH.
+func PluginInstalled(name string) bool {
+	_, err := exec.LookPath(PluginName(name))
+	return err == nil
+}
+
+// BuildRequest is the JSON payload written to a builder plugin's stdin.
+type BuildRequest struct {
+	Name     string `json:"name"`     // name of the function
+	Root     string `json:"root"`     // path to the function's source
+	Image    string `json:"image"`    // image tag the plugin is to build
+	Runtime  string `json:"runtime"`  // language runtime of the function
+	Registry string `json:"registry"` // registry from which the image tag was derived
+}
+
+// BuildResponse is the JSON payload expected on a builder plugin's stdout.
+type BuildResponse struct {
+	// Image optionally echoes the image built.  When provided it must match
+	// the image requested; a mismatch is a protocol error.
+	Image string `json:"image,omitempty"`
+
+	// Error describes why the build did not succeed.  Empty on success.
+	Error string `json:"error,omitempty"`
+}
+
+// ExecBuilder is a Builder implemented as a separate executable discovered
+// on PATH (see PluginPrefix), allowing third-party builders to be registered
+// without recompiling.  The plugin receives a BuildRequest as JSON on stdin
+// and reports a BuildResponse as JSON on stdout; a nonzero exit indicates
+// the build failed, with stderr included in the returned error.
+type ExecBuilder struct {
+	name    string
+	verbose bool
+}
+
+// NewExecBuilder creates an instance of a builder backed by the plugin of
+// the given builder short name.
+func NewExecBuilder(name string, verbose bool) *ExecBuilder {
+	return &ExecBuilder{name: name, verbose: verbose}
+}
+
+// Build the function by delegating to the builder plugin.
+func (b *ExecBuilder) Build(ctx context.Context, f fn.Function) error {
+	path, err := exec.LookPath(PluginName(b.name))
+	if err != nil {
+		return fmt.Errorf("builder plugin '%v' not found in PATH.  Install an executable of that name to use the '%v' builder", PluginName(b.name), b.name)
+	}
+
+	request, err := json.Marshal(BuildRequest{
+		Name:     f.Name,
+		Root:     f.Root,
+		Image:    f.Image,
+		Runtime:  f.Runtime,
+		Registry: f.Registry,
+	})
+	if err != nil {
+		return err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if b.verbose {
+		cmd.Stderr = os.Stderr
+	}
+
+	if err = cmd.Run(); err != nil {
+		detail := strings.TrimSpace(stderr.String())
+		if detail != "" {
+			return fmt.Errorf("builder plugin '%v' failed: %v\n%v", PluginName(b.name), err, detail)
+		}
+		return fmt.Errorf("builder plugin '%v' failed: %v", PluginName(b.name), err)
+	}
+
+	var response BuildResponse
+	if err = json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return fmt.Errorf("builder plugin '%v' returned an invalid response: %v", PluginName(b.name), err)
+	}
+	if response.Error != "" {
+		return fmt.Errorf("builder plugin '%v' reported an error: %v", PluginName(b.name), response.Error)
+	}
+	if response.Image != "" && response.Image != f.Image {
+		return fmt.Errorf("builder plugin '%v' built image '%v' but '%v' was requested", PluginName(b.name), response.Image, f.Image)
+	}
+	return nil
+}
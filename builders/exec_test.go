@@ -0,0 +1,83 @@
+package builders_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/builders"
+)
+
+// installPlugin writes an executable builder plugin of the given name to a
+// temporary directory which is prepended to PATH for the duration of the
+// test.
+func installPlugin(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test scripts are POSIX shell")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, builders.PluginName(name))
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// TestExecBuilder_Build ensures that a plugin which reports success yields
+// a nil error, and that the request is delivered on its stdin as JSON.
+func TestExecBuilder_Build(t *testing.T) {
+	// The plugin asserts the request contains the image tag before
+	// responding with an empty (successful) response.
+	installPlugin(t, "testbuilder", `
+read request
+case "$request" in
+  *example.com/alice/testfunc:latest*) echo '{}' ;;
+  *) echo '{"error": "unexpected request"}' ;;
+esac
+`)
+	b := builders.NewExecBuilder("testbuilder", false)
+	f := fn.Function{Name: "testfunc", Runtime: "go", Image: "example.com/alice/testfunc:latest"}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestExecBuilder_Errors ensures that a missing plugin, a reported build
+// error and a crashed plugin each surface as descriptive errors.
+func TestExecBuilder_Errors(t *testing.T) {
+	// Missing
+	b := builders.NewExecBuilder("nonexistent", false)
+	err := b.Build(context.Background(), fn.Function{})
+	if err == nil || !strings.Contains(err.Error(), builders.PluginName("nonexistent")) {
+		t.Fatalf("expected a missing plugin error, got %v", err)
+	}
+
+	// Reported error
+	installPlugin(t, "erroring", `echo '{"error": "no space left"}'`)
+	b = builders.NewExecBuilder("erroring", false)
+	err = b.Build(context.Background(), fn.Function{})
+	if err == nil || !strings.Contains(err.Error(), "no space left") {
+		t.Fatalf("expected the reported error, got %v", err)
+	}
+
+	// Crash (nonzero exit), with stderr included in the error
+	installPlugin(t, "crashing", `echo "boom" >&2; exit 1`)
+	b = builders.NewExecBuilder("crashing", false)
+	err = b.Build(context.Background(), fn.Function{})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected the crash detail, got %v", err)
+	}
+
+	// Invalid response
+	installPlugin(t, "garbled", `echo 'not json'`)
+	b = builders.NewExecBuilder("garbled", false)
+	err = b.Build(context.Background(), fn.Function{})
+	if err == nil || !strings.Contains(err.Error(), "invalid response") {
+		t.Fatalf("expected an invalid response error, got %v", err)
+	}
+}
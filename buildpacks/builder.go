@@ -3,6 +3,7 @@ package buildpacks
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"runtime"
@@ -108,6 +109,7 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 		Image:          f.Image,
 		LifecycleImage: "quay.io/boson/lifecycle:0.13.2",
 		Builder:        image,
+		RunImage:       f.Build.RunImage, // empty uses the builder's default
 		Buildpacks:     f.Build.Buildpacks,
 		ContainerConfig: struct {
 			Network string
@@ -117,6 +119,12 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 	if opts.Env, err = fn.Interpolate(f.Build.BuildEnvs); err != nil {
 		return err
 	}
+	// Build args are build-tool directives rather than container env.  For
+	// pack they are conveyed to buildpacks as build-time env, applied after
+	// BuildEnvs such that explicit args take precedence.
+	for k, v := range f.Build.BuildArgs {
+		opts.Env[k] = v
+	}
 	if runtime.GOOS == "linux" {
 		opts.ContainerConfig.Network = "host"
 	}
@@ -186,6 +194,23 @@ func BuilderImage(f fn.Function, builderName string) (string, error) {
 	return builders.Image(f, builderName, DefaultBuilderImages)
 }
 
+// Validate that this builder is an appropriate choice for the function:
+// the function must not declare a different builder, and a builder image
+// must be resolvable for its runtime.
+func (b *Builder) Validate(f fn.Function) error {
+	if f.Build.Builder != "" && f.Build.Builder != builders.Pack {
+		return fmt.Errorf("function is configured to build with the %q builder, but the %q builder was selected", f.Build.Builder, builders.Pack)
+	}
+	if _, err := BuilderImage(f, builders.Pack); err != nil {
+		var errNoDefault builders.ErrNoDefaultImage
+		if errors.As(err, &errNoDefault) {
+			return fmt.Errorf("%v. Supported runtimes are: %v", err, strings.Join(builders.Runtimes(DefaultBuilderImages), ", "))
+		}
+		return err
+	}
+	return nil
+}
+
 // podmanPreV330 returns if the daemon is podman pre v330 or errors trying.
 func podmanPreV330(ctx context.Context, cli client.CommonAPIClient) (b bool, err error) {
 	version, err := cli.ServerVersion(ctx)
@@ -94,6 +94,34 @@ func Test_BuildEnvs(t *testing.T) {
 	}
 }
 
+// Test_BuildArgs ensures that build args are passed through to the build
+// options as build-time env, taking precedence over build envs of the same
+// name.
+func Test_BuildArgs(t *testing.T) {
+	var (
+		envName  = "BP_GO_BUILD_FLAGS"
+		envValue = "from-env"
+		f        = fn.Function{
+			Runtime: "go",
+			Build: fn.BuildSpec{
+				BuildEnvs: []fn.Env{{Name: &envName, Value: &envValue}},
+				BuildArgs: map[string]string{"BP_GO_BUILD_FLAGS": "-tags=prod"},
+			},
+		}
+		i = &mockImpl{}
+		b = NewBuilder(WithImpl(i))
+	)
+	i.BuildFn = func(ctx context.Context, opts pack.BuildOptions) error {
+		if opts.Env["BP_GO_BUILD_FLAGS"] != "-tags=prod" {
+			t.Fatalf("expected build arg to take precedence, got '%v'", opts.Env["BP_GO_BUILD_FLAGS"])
+		}
+		return nil
+	}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+}
+
 type mockImpl struct {
 	BuildFn func(context.Context, pack.BuildOptions) error
 }
@@ -101,3 +129,30 @@ type mockImpl struct {
 func (i mockImpl) Build(ctx context.Context, opts pack.BuildOptions) error {
 	return i.BuildFn(ctx, opts)
 }
+
+// Test_BuilderRunImage ensures that a run image configured on the function
+// is passed through to the pack build options, with the builder's default
+// (empty) used otherwise.
+func Test_BuilderRunImage(t *testing.T) {
+	var (
+		i = &mockImpl{}
+		b = NewBuilder(WithName(builders.Pack), WithImpl(i))
+		f = fn.Function{
+			Runtime: "node",
+			Build: fn.BuildSpec{
+				RunImage: "example.com/user/approved-base",
+			},
+		}
+	)
+
+	i.BuildFn = func(ctx context.Context, opts pack.BuildOptions) error {
+		if opts.RunImage != "example.com/user/approved-base" {
+			t.Fatalf("expected run image 'example.com/user/approved-base', got '%v'", opts.RunImage)
+		}
+		return nil
+	}
+
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,18 +3,33 @@ package function
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/coreos/go-semver/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+
+	"knative.dev/kn-plugin-func/utils"
 )
 
 const (
@@ -40,39 +55,139 @@ const (
 	// existence indicates the function has been built, and whose content is
 	// a fingerprint of the filesystem at the time of the build.
 	buildstamp = "built"
+
+	// buildfiles is the name of the file within the run data directory
+	// listing the files (with modification timestamps) which contributed to
+	// the buildstamp's fingerprint, enabling explanation of exactly which
+	// file rendered a function stale.
+	buildfiles = "built.files"
+
+	// DefaultBroker to which functions are subscribed when none is
+	// specified on the subscription.
+	DefaultBroker = "default"
+
+	// deploystamp is the name of the file within the run data directory
+	// recording the last successful deployment (URL, namespace, time).
+	// Being transient cluster state rather than source, it is kept out of
+	// func.yaml to avoid dirtying the source tree on every deploy.
+	deploystamp = "deployed"
 )
 
 // Client for managing function instances.
 type Client struct {
-	repositoriesPath  string            // path to repositories
-	repositoriesURI   string            // repo URI (overrides repositories path)
-	verbose           bool              // print verbose logs
-	builder           Builder           // Builds a runnable image source
-	pusher            Pusher            // Pushes function image to a remote
-	deployer          Deployer          // Deploys or Updates a function
-	runner            Runner            // Runs the function locally
-	remover           Remover           // Removes remote services
-	lister            Lister            // Lists remote services
-	describer         Describer         // Describes function instances
-	dnsProvider       DNSProvider       // Provider of DNS services
-	registry          string            // default registry for OCI image tags
-	progressListener  ProgressListener  // progress listener
-	repositories      *Repositories     // Repositories management
-	templates         *Templates        // Templates management
-	instances         *Instances        // Function Instances management
-	transport         http.RoundTripper // Customizable internal transport
-	pipelinesProvider PipelinesProvider // CI/CD pipelines management
+	repositoriesPath  string              // path to repositories
+	repositoriesURI   string              // repo URI (overrides repositories path)
+	repositoryTTL     time.Duration       // TTL for cached remote template repos
+	repositoryRefresh bool                // bypass the remote template repo cache
+	verbose           bool                // print verbose logs
+	builder           Builder             // Builds a runnable image source
+	pusher            Pusher              // Pushes function image to a remote
+	deployer          Deployer            // Deploys or Updates a function
+	rollbacker        Rollbacker          // Rolls back to a previous revision
+	promoter          Promoter            // Promotes between namespaces
+	subscriber        Subscriber          // Subscribes to event sources
+	runner            Runner              // Runs the function locally
+	remover           Remover             // Removes remote services
+	lister            Lister              // Lists remote services
+	describer         Describer           // Describes function instances
+	dnsProvider       DNSProvider         // Provider of DNS services
+	registry          string              // default registry for OCI image tags
+	image             string              // overrides the function image on deploy
+	progressListener  ProgressListener    // progress listener
+	repositories      *Repositories       // Repositories management
+	templates         *Templates          // Templates management
+	instances         *Instances          // Function Instances management
+	transport         http.RoundTripper   // Customizable internal transport
+	tlsConfig         *tls.Config         // TLS settings applied to the transport
+	caCertFile        string              // path to additional trusted CA certs
+	pipelinesProvider PipelinesProvider   // CI/CD pipelines management
+	followSymlinks    bool                // follow symlinks when fingerprinting
+	ingressResolver   IngressResolver     // Resolver of the ingress address
+	reportExternal    bool                // report ingress address on deploy
+	brokerResolver    BrokerResolver      // Resolver of broker ingress URLs
+	defaultTemplate   string              // template when none is specified
+	pushRetries       int                 // retries of transient push errors
+	pushRetryBackoff  time.Duration       // initial backoff between retries
+	removeBackupDir   string              // backup functions here before removal
+	revisionLimit     int                 // revisions retained after deploy
+	buildTimeout      time.Duration       // maximum duration of builds
+	deployWait        time.Duration       // wait for deployed function health
+	templatesPath     string              // path overriding embedded templates
+	eventListener     func(Event)         // listener of lifecycle events
+	mirrorRegistries  []string            // additional push target registries
+	mirrorsRequired   bool                // failed mirror pushes are fatal
+	rollbackOnFailure bool                // undo completed steps when New fails
+	linters           map[string]Linter   // source checks keyed by runtime
+	verifiers         map[string]Verifier // compile checks keyed by runtime
+	runPostCreate     bool                // run template post-create hooks
+	deployByDigest    bool                // pin deploys to the pushed digest
+	runDataDir        string              // location of transient runtime data
+	noGitignore       bool                // skip .gitignore creation on create
+	deployOptions     DeployOptions       // per-deploy settings (traffic etc)
+	deployOptionsSet  bool                // deploy options were provided
+
+	// builderImageDefaults are the default builder images per runtime,
+	// keyed by builder implementation short name (e.g. "pack", "s2i").
+	builderImageDefaults map[string]map[string]string
 }
 
 // ErrNotBuilt indicates the function has not yet been built.
 var ErrNotBuilt = errors.New("not built")
 
+// DeployError is returned when a deployment fails, decorating the underlying
+// cause with diagnostics gathered from the cluster such that the reason (an
+// unresolvable image, a crashing container, etc.) is visible without further
+// spelunking.  Populated by deployer implementations on a best-effort basis;
+// either member may be empty when the cluster could not be queried.
+type DeployError struct {
+	// Err is the underlying cause of the failure.
+	Err error
+	// Conditions are the not-ready conditions of the deployed service,
+	// formatted one per entry as "Type=Status Reason: Message".
+	Conditions []string
+	// Events are recent warning events from the cluster involving the
+	// function, formatted one per entry.
+	Events []string
+}
+
+func (e DeployError) Error() string {
+	b := strings.Builder{}
+	b.WriteString(e.Err.Error())
+	if len(e.Conditions) > 0 {
+		b.WriteString("\nService conditions:")
+		for _, c := range e.Conditions {
+			b.WriteString("\n  " + c)
+		}
+	}
+	if len(e.Events) > 0 {
+		b.WriteString("\nRecent events:")
+		for _, v := range e.Events {
+			b.WriteString("\n  " + v)
+		}
+	}
+	return b.String()
+}
+
+func (e DeployError) Unwrap() error { return e.Err }
+
+// ErrBuildTimeout indicates the build exceeded the configured timeout
+// (see WithBuildTimeout).
+var ErrBuildTimeout = errors.New("build timed out")
+
 // Builder of function source to runnable image.
 type Builder interface {
 	// Build a function project with source located at path.
 	Build(context.Context, Function) error
 }
 
+// ValidatingBuilder is a Builder which can additionally validate that it is
+// an appropriate choice for a given function before the build commences,
+// surfacing configuration errors (a mismatched builder selection or an
+// unsupported runtime) early rather than mid-build.
+type ValidatingBuilder interface {
+	Validate(f Function) error
+}
+
 // Pusher of function image to a registry.
 type Pusher interface {
 	// Push the image of the function.
@@ -86,10 +201,112 @@ type Deployer interface {
 	Deploy(context.Context, Function) (DeploymentResult, error)
 }
 
+// DeployOptions are per-deploy settings which affect how the deployment is
+// performed rather than the function itself (and are thus not persisted in
+// func.yaml).  See WithDeployOptions.
+type DeployOptions struct {
+	// TrafficPercent of traffic to route to the newly created revision,
+	// 0-100, with the remainder pinned to the previously ready revision
+	// (a canary rollout).  100, the default, routes all traffic to the
+	// new revision as usual.
+	TrafficPercent int
+	// Tag to apply to the newly created revision, giving it a dedicated
+	// addressable URL.  Optional.
+	Tag string
+}
+
+// TrafficDeployer is an optional interface which, when implemented by a
+// Deployer, enables deploying with a traffic split between the new revision
+// and the previously ready one (see WithDeployOptions).
+type TrafficDeployer interface {
+	// DeployWithTraffic deploys as Deploy does, additionally configuring
+	// the traffic distribution per the given options.
+	DeployWithTraffic(ctx context.Context, f Function, opts DeployOptions) (DeploymentResult, error)
+}
+
+// DeployStamp records the last known successful deployment of a function
+// as observed at deploy time, persisted in the run data directory such
+// that the URL can be read back without querying the cluster.
+type DeployStamp struct {
+	URL       string    `yaml:"url"`
+	Namespace string    `yaml:"namespace"`
+	Deployed  time.Time `yaml:"deployed"`
+}
+
+// Subscriber binds a function to an event source, creating whatever
+// platform resources (e.g. a Knative Eventing Trigger) are necessary for
+// events matching the subscription to be delivered to the function.
+type Subscriber interface {
+	// Subscribe the function to events matching the subscription.
+	// Implementations are expected to be idempotent.
+	Subscribe(ctx context.Context, f Function, sub Subscription) error
+}
+
+// Promoter copies a deployed function between namespaces without a rebuild.
+type Promoter interface {
+	// Promote the named function from one namespace to another, preserving
+	// the exact image digest currently serving traffic in the source.
+	Promote(ctx context.Context, name, fromNamespace, toNamespace string) error
+}
+
+// Rollbacker reverts a deployed function to a previous revision.
+type Rollbacker interface {
+	// Rollback the named function to the given revision, or to the
+	// previous Ready revision when none is specified.
+	Rollback(ctx context.Context, name, revision string) error
+}
+
+// ManifestGenerator is an optional interface which, when implemented by a
+// Deployer, enables rendering the deployment manifest which would be applied
+// for a function without contacting the cluster (see Client.Manifest).
+type ManifestGenerator interface {
+	// Manifest returns the serialized deployment manifest for the function.
+	Manifest(ctx context.Context, f Function) ([]byte, error)
+}
+
+// RevisionPruner is an optional interface which, when implemented by a
+// Deployer, enables garbage collection of a function's old revisions
+// following a successful deploy (see WithRevisionHistoryLimit).  Revisions
+// currently receiving traffic are never pruned.
+type RevisionPruner interface {
+	// PruneRevisions of the given function, retaining the given number.
+	PruneRevisions(ctx context.Context, f Function, keep int) error
+}
+
+// RuntimeCapabilityLister is an optional interface which, when implemented
+// by a Deployer or PipelinesProvider, reports the runtimes the target
+// cluster is able to build and run (for example based on the builders and
+// templates installed on-cluster).  Used by Client.SupportedRuntimes to
+// narrow the locally-available runtimes to those the target supports.
+type RuntimeCapabilityLister interface {
+	// SupportedRuntimes the target cluster reports being able to run.
+	SupportedRuntimes(ctx context.Context) ([]string, error)
+}
+
 type DeploymentResult struct {
 	Status    Status
 	URL       string
 	Namespace string
+	// RevisionName is the name of the revision created or updated by the
+	// deploy, when reported by the platform (e.g. the Knative revision),
+	// enabling callers to pin traffic or roll back deterministically.
+	RevisionName string
+	// Generation of the deployed service to which the revision belongs.
+	Generation int64
+	// Traffic is the resulting distribution of traffic across revisions,
+	// populated when deploying with a traffic split (see DeployOptions).
+	Traffic []TrafficTarget
+}
+
+// TrafficTarget describes the share of traffic routed to one revision of a
+// deployed function.
+type TrafficTarget struct {
+	// RevisionName receiving the traffic.  Empty for the latest revision.
+	RevisionName string
+	// Tag of the target, when it has a dedicated addressable URL.
+	Tag string
+	// Percent of traffic routed to the target, 0-100.
+	Percent int64
 }
 
 // Status of the function from the DeploymentResult
@@ -117,8 +334,17 @@ type Remover interface {
 
 // Lister of deployed functions.
 type Lister interface {
-	// List the functions currently deployed.
-	List(ctx context.Context) ([]ListItem, error)
+	// List the functions currently deployed, narrowed by the given options.
+	List(ctx context.Context, opts ListOptions) ([]ListItem, error)
+}
+
+// ListOptions narrow the results of List.  The zero value returns
+// everything the lister can see.  Filters are applied server-side where
+// the implementation supports it, such that listing a namespace the user
+// can not access yields a permission error rather than an empty list.
+type ListOptions struct {
+	Namespace string // limit results to the given namespace
+	Runtime   string // limit results to functions of the given runtime
 }
 
 type ListItem struct {
@@ -147,12 +373,52 @@ type ProgressListener interface {
 	Done()
 }
 
+// Event is a structured notification of a lifecycle phase transition,
+// emitted to the listener registered with WithEventListener.  Events
+// complement the human-oriented ProgressListener with machine-readable
+// state suitable for rendering phase-level status (e.g. in a web UI)
+// without scraping message strings.
+type Event struct {
+	// Phase of the lifecycle: create, build, push, deploy or route.
+	Phase string
+	// Status of the phase: started, succeeded or failed.
+	Status string
+	// Detail optionally elaborates; the error text for failed phases.
+	Detail string
+}
+
+// Lifecycle phases reported via Event.
+const (
+	PhaseCreate = "create"
+	PhaseBuild  = "build"
+	PhasePush   = "push"
+	PhaseDeploy = "deploy"
+	PhaseRoute  = "route"
+)
+
+// Phase statuses reported via Event.
+const (
+	EventStarted   = "started"
+	EventSucceeded = "succeeded"
+	EventFailed    = "failed"
+)
+
 // Describer of function instances
 type Describer interface {
 	// Describe the named function in the remote environment.
 	Describe(ctx context.Context, name string) (Instance, error)
 }
 
+// RevisionDescriber is an optional interface a Describer may implement to
+// resolve the URL of a specific revision (or traffic tag) of a function,
+// enabling a canary revision to be invoked directly before traffic is
+// shifted to it.
+type RevisionDescriber interface {
+	// RevisionURL returns the URL at which the named revision or traffic
+	// tag of the function can be reached.
+	RevisionURL(ctx context.Context, funcName, revision string) (string, error)
+}
+
 // Instance data about the runtime state of a function in a given environment.
 //
 // A function instance is a logical running function space, which share
@@ -172,6 +438,18 @@ type Instance struct {
 	Image         string         `json:"image" yaml:"image"`
 	Namespace     string         `json:"namespace" yaml:"namespace"`
 	Subscriptions []Subscription `json:"subscriptions" yaml:"subscriptions"`
+
+	// Envs are the instance's literal environment variables (references to
+	// secrets and config maps are elided), when reported by the
+	// implementation.  Enables comparison with local config (see Diff).
+	Envs map[string]string `json:"envs,omitempty" yaml:"envs,omitempty"`
+	// Labels of the deployed instance, when reported.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Scaling annotations (e.g. autoscaling min/max scale) of the deployed
+	// instance, when reported.
+	Scaling map[string]string `json:"scaling,omitempty" yaml:"scaling,omitempty"`
+	// VolumeMounts are the instance's volume mount paths, when reported.
+	VolumeMounts []string `json:"volumeMounts,omitempty" yaml:"volumeMounts,omitempty"`
 }
 
 // Subscriptions currently active to event sources
@@ -187,6 +465,23 @@ type DNSProvider interface {
 	Provide(Function) error
 }
 
+// BrokerResolver resolves the addressable ingress URL of a named CloudEvents
+// broker in the target environment, for use when publishing test events to
+// the broker rather than contacting a function directly.
+type BrokerResolver interface {
+	// BrokerURL of the named broker.
+	BrokerURL(ctx context.Context, name string) (string, error)
+}
+
+// IngressResolver resolves the external address (IP or hostname) assigned to
+// the cluster ingress through which deployed functions are exposed.
+// Implementations are expected to poll until the address has been assigned,
+// returning early on context cancellation.
+type IngressResolver interface {
+	// ExternalAddress of the cluster ingress, once assigned.
+	ExternalAddress(ctx context.Context) (string, error)
+}
+
 // PipelinesProvider manages lifecyle of CI/CD pipelines used by a function
 type PipelinesProvider interface {
 	Run(context.Context, Function) error
@@ -200,6 +495,9 @@ func New(options ...Option) *Client {
 		builder:           &noopBuilder{output: os.Stdout},
 		pusher:            &noopPusher{output: os.Stdout},
 		deployer:          &noopDeployer{output: os.Stdout},
+		rollbacker:        &noopRollbacker{output: os.Stdout},
+		promoter:          &noopPromoter{output: os.Stdout},
+		subscriber:        &noopSubscriber{output: os.Stdout},
 		runner:            &noopRunner{output: os.Stdout},
 		remover:           &noopRemover{output: os.Stdout},
 		lister:            &noopLister{output: os.Stdout},
@@ -207,13 +505,31 @@ func New(options ...Option) *Client {
 		dnsProvider:       &noopDNSProvider{output: os.Stdout},
 		progressListener:  &NoopProgressListener{},
 		pipelinesProvider: &noopPipelinesProvider{},
+		ingressResolver:   &noopIngressResolver{},
+		brokerResolver:    &noopBrokerResolver{},
 		repositoriesPath:  filepath.Join(ConfigPath(), "repositories"),
+		repositoryTTL:     DefaultRepositoryCacheTTL,
 		transport:         http.DefaultTransport,
+		deployByDigest:    true,
+		runDataDir:        RunDataDir,
+		linters: map[string]Linter{
+			"go":   goLinter{},
+			"node": nodeLinter{},
+		},
+		verifiers: map[string]Verifier{
+			"go":         goVerifier{},
+			"typescript": tsVerifier{},
+		},
+		builderImageDefaults: map[string]map[string]string{},
 	}
 	for _, o := range options {
 		o(c)
 	}
 
+	// TLS settings are applied after all options have been processed such
+	// that they compose with a transport provided via WithTransport.
+	c.applyTLSSettings()
+
 	// Initialize sub-managers using now-fully-initialized client.
 	c.repositories = newRepositories(c)
 	c.templates = newTemplates(c)
@@ -302,6 +618,30 @@ func WithDeployer(d Deployer) Option {
 	}
 }
 
+// WithRollbacker provides the concrete implementation of the rollback
+// function.
+func WithRollbacker(r Rollbacker) Option {
+	return func(c *Client) {
+		c.rollbacker = r
+	}
+}
+
+// WithPromoter provides the concrete implementation of the promotion
+// function.
+func WithPromoter(p Promoter) Option {
+	return func(c *Client) {
+		c.promoter = p
+	}
+}
+
+// WithSubscriber provides the concrete implementation of the subscription
+// function.
+func WithSubscriber(s Subscriber) Option {
+	return func(c *Client) {
+		c.subscriber = s
+	}
+}
+
 // WithRunner provides the concrete implementation of a deployer.
 func WithRunner(r Runner) Option {
 	return func(c *Client) {
@@ -347,6 +687,48 @@ func WithDNSProvider(provider DNSProvider) Option {
 	}
 }
 
+// WithAdditionalRegistries sets registries to which the function image is
+// additionally pushed (retagged for each) after the primary push succeeds,
+// for example an internal pull-through cache mirroring a public registry.
+// Failures pushing to an additional registry are reported but non-fatal
+// unless required (see WithAdditionalRegistriesRequired).
+func WithAdditionalRegistries(registries []string) Option {
+	return func(c *Client) {
+		c.mirrorRegistries = registries
+	}
+}
+
+// WithAdditionalRegistriesRequired causes a failure to push to any of the
+// additional registries (see WithAdditionalRegistries) to fail the push,
+// rather than the default of reporting a warning and continuing.
+func WithAdditionalRegistriesRequired(required bool) Option {
+	return func(c *Client) {
+		c.mirrorsRequired = required
+	}
+}
+
+// WithEventListener registers a listener of structured lifecycle events,
+// invoked on each phase transition (started, succeeded, failed) of the
+// create, build, push, deploy and route phases.  Complements, rather than
+// replaces, the progress listener.
+func WithEventListener(listener func(Event)) Option {
+	return func(c *Client) {
+		c.eventListener = listener
+	}
+}
+
+// WithTemplatesPath sets a path on local disk from which to read the
+// default templates in place of those embedded in the binary.  The path
+// must follow the same runtime/template layout as the embedded templates
+// and contain at least one runtime.  Useful for air-gapped environments
+// in which templates are extracted to a known directory.  Repositories
+// from the extensible repositories path are unaffected.
+func WithTemplatesPath(path string) Option {
+	return func(c *Client) {
+		c.templatesPath = path
+	}
+}
+
 // WithRepositoriesPath sets the location on disk to use for extensible template
 // repositories.  Extensible template repositories are additional templates
 // that exist on disk and are not built into the binary.
@@ -365,6 +747,23 @@ func WithRepository(uri string) Option {
 	}
 }
 
+// WithRepositoryCacheTTL sets the duration for which a cached clone of the
+// remote template repository (see WithRepository) is reused before being
+// re-fetched.  Zero disables caching.
+func WithRepositoryCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.repositoryTTL = ttl
+	}
+}
+
+// WithRepositoryRefresh forces a re-fetch of the remote template repository
+// (see WithRepository), bypassing any unexpired cached clone.
+func WithRepositoryRefresh(refresh bool) Option {
+	return func(c *Client) {
+		c.repositoryRefresh = refresh
+	}
+}
+
 // WithRegistry sets the default registry which is consulted when an image
 // name is not explicitly provided.  Can be fully qualified, including the
 // registry and namespace (ex: 'quay.io/myname') or simply the namespace
@@ -375,6 +774,212 @@ func WithRegistry(registry string) Option {
 	}
 }
 
+// WithLinter registers (or overrides) the linter used for functions of the
+// given runtime.  Runtimes without a registered linter are skipped by Lint.
+func WithLinter(runtime string, l Linter) Option {
+	return func(c *Client) {
+		c.linters[runtime] = l
+	}
+}
+
+// WithVerifier registers (or overrides) the verifier used to compile-check
+// functions of the given runtime.  Runtimes without a registered verifier
+// trivially verify.
+func WithVerifier(runtime string, v Verifier) Option {
+	return func(c *Client) {
+		c.verifiers[runtime] = v
+	}
+}
+
+// WithBuilderImageDefaults registers the default builder images, keyed by
+// runtime, for the builder implementation with the given short name
+// (e.g. "pack", "s2i").  Consulted by BuilderImages when reporting which
+// image a build would use.
+func WithBuilderImageDefaults(builder string, images map[string]string) Option {
+	return func(c *Client) {
+		c.builderImageDefaults[builder] = images
+	}
+}
+
+// WithRollbackOnFailure configures New to undo steps which had completed
+// when a subsequent step fails: a function deployed but not yet routed is
+// removed from the cluster, and files created on disk are deleted.  Both
+// the original error and any errors encountered during the rollback itself
+// are reported.  Disabled by default, preserving partial state for
+// inspection and retry.
+func WithRollbackOnFailure(enabled bool) Option {
+	return func(c *Client) {
+		c.rollbackOnFailure = enabled
+	}
+}
+
+// WithImage sets an externally built image to deploy, overriding any image
+// recorded on the function by a previous build.  The reference may include
+// a pinned digest (ex: 'quay.io/myname/myfunc@sha256:...'), in which case
+// the deploy does not require a local build or push; useful when images are
+// built by an external CI pipeline.  The reference is validated on deploy.
+func WithImage(ref string) Option {
+	return func(c *Client) {
+		c.image = ref
+	}
+}
+
+// WithPushRetries configures Push to retry transient registry errors
+// (network errors, HTTP 429 and 5xx) up to n additional times, with
+// exponentially increasing backoff starting at the given duration.
+// Non-retryable errors, such as failed authentication, abort immediately.
+// The default of zero retries preserves current single-attempt behavior.
+func WithPushRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.pushRetries = n
+		c.pushRetryBackoff = backoff
+	}
+}
+
+// WithBuildTimeout sets a maximum duration for builds, after which the
+// build is canceled and Build returns ErrBuildTimeout.  The default of
+// zero imposes no timeout.
+func WithBuildTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.buildTimeout = d
+	}
+}
+
+// WithRevisionHistoryLimit sets the number of revisions retained after a
+// successful deploy.  Older revisions beyond the limit are pruned by
+// deployers supporting revision pruning (see RevisionPruner), excepting
+// revisions currently receiving traffic, which are never pruned.  The
+// default of zero retains all revisions.
+func WithRevisionHistoryLimit(n int) Option {
+	return func(c *Client) {
+		c.revisionLimit = n
+	}
+}
+
+// WithDeployWait configures Deploy to wait, up to the given timeout, for
+// the deployed function's readiness endpoint to return 200 before
+// returning.  On timeout an error including the last observed status is
+// returned.  The default of zero returns immediately upon deployment.
+func WithDeployWait(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.deployWait = timeout
+	}
+}
+
+// WithRemoveBackup configures Remove to first write a backup of the
+// function's live spec (as reported by the describer) and its local
+// func.yaml, if available, to a timestamped file in the given directory,
+// enabling later recreation.  A failure to back up aborts the removal.
+func WithRemoveBackup(dir string) Option {
+	return func(c *Client) {
+		c.removeBackupDir = dir
+	}
+}
+
+// WithDefaultTemplate sets the template used when creating functions for
+// which no template was explicitly specified, overriding the static default
+// (DefaultTemplate).  The value is validated when the template is written,
+// so a name which does not exist for the chosen runtime errors at create.
+func WithDefaultTemplate(name string) Option {
+	return func(c *Client) {
+		c.defaultTemplate = name
+	}
+}
+
+// WithDeployOptions sets per-deploy settings such as a traffic split
+// between the new revision and the previously ready one.  Requires a
+// deployer which supports them (see TrafficDeployer); the default routes
+// all traffic to the new revision.
+func WithDeployOptions(o DeployOptions) Option {
+	return func(c *Client) {
+		c.deployOptions = o
+		c.deployOptionsSet = true
+	}
+}
+
+// WithRunDataDir sets the directory in which the transient runtime metadata
+// of functions (build and deploy stamps, etc.) is kept, overriding the
+// default of RunDataDir (".func") within each function's root.  A relative
+// path is resolved against the function's root; an absolute path is used
+// as-is, supporting workspaces which are read-only except for a scratch
+// directory.  A relocated directory is not registered in .gitignore and, as
+// with the default, is excluded from the staleness fingerprint.
+func WithRunDataDir(path string) Option {
+	return func(c *Client) {
+		c.runDataDir = path
+	}
+}
+
+// WithNoGitignore sets whether creation of the .gitignore entry excluding
+// the transient runtime data directory (".func") is skipped.  By default the
+// entry is written on create: appended to an existing .gitignore when not
+// already present, or the file is created.  Useful for projects which manage
+// their ignore rules externally.
+func WithNoGitignore(enabled bool) Option {
+	return func(c *Client) {
+		c.noGitignore = enabled
+	}
+}
+
+// WithDeployByDigest sets whether deploys are pinned to the exact image
+// digest recorded at push (the default), guaranteeing the cluster runs
+// precisely what was pushed, or reference the mutable tag.  Images without
+// a recorded digest always deploy by tag.
+func WithDeployByDigest(enabled bool) Option {
+	return func(c *Client) {
+		c.deployByDigest = enabled
+	}
+}
+
+// WithRunPostCreate enables running the optional post-create hook a
+// template may declare in its manifest (for example an installation of
+// dependencies) in the new project directory after Create writes its files.
+// Disabled by default: callers in noninteractive contexts such as CI should
+// leave it off and run any installation step explicitly.
+func WithRunPostCreate(enabled bool) Option {
+	return func(c *Client) {
+		c.runPostCreate = enabled
+	}
+}
+
+// WithBrokerResolver provides a concrete implementation of a resolver of
+// broker ingress URLs, used when invoking via a "broker:<name>" target.
+func WithBrokerResolver(r BrokerResolver) Option {
+	return func(c *Client) {
+		c.brokerResolver = r
+	}
+}
+
+// WithIngressResolver provides a concrete implementation of a resolver of
+// the ingress external address.
+func WithIngressResolver(r IngressResolver) Option {
+	return func(c *Client) {
+		c.ingressResolver = r
+	}
+}
+
+// WithReportExternalAddress instructs the client to additionally resolve and
+// report the external IP or hostname assigned to the cluster ingress after a
+// successful deploy.  This is the address to which DNS records (CNAME etc.)
+// for the function should be directed.
+func WithReportExternalAddress(report bool) Option {
+	return func(c *Client) {
+		c.reportExternal = report
+	}
+}
+
+// WithFollowSymlinks instructs the client to follow symbolic links when
+// calculating the fingerprint of a function's filesystem, such that changes
+// to the targets of links within the function's root are detected and result
+// in a rebuild.  By default only the link itself (its target path) is
+// considered, so retargeting a link is noticed but changes to the linked
+// files are not.
+func WithFollowSymlinks(follow bool) Option {
+	return func(c *Client) {
+		c.followSymlinks = follow
+	}
+}
+
 // WithTransport sets a custom transport to use internally.
 func WithTransport(t http.RoundTripper) Option {
 	return func(c *Client) {
@@ -382,6 +987,87 @@ func WithTransport(t http.RoundTripper) Option {
 	}
 }
 
+// WithTLSConfig sets the base TLS client configuration applied to the
+// transport.  Composes with WithTransport: the settings are applied to
+// whichever transport is in effect once all options have been processed.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithCACert adds the PEM-encoded certificate(s) at the given path to the
+// pool of root CAs trusted by the transport, in addition to the system
+// roots.  For use when the image registry or cluster uses an internal
+// certificate authority.  Composes with WithTransport and WithTLSConfig.
+func WithCACert(path string) Option {
+	return func(c *Client) {
+		c.caCertFile = path
+	}
+}
+
+// tlsConfigurer is an optional interface a transport may implement to
+// accept the TLS client settings provided via WithTLSConfig and WithCACert.
+type tlsConfigurer interface {
+	SetTLSClientConfig(*tls.Config)
+}
+
+// applyTLSSettings applies any TLS settings provided via WithTLSConfig or
+// WithCACert to the transport.  Errors encountered while loading
+// certificates are deferred to the transport's first use, where they have
+// a natural error path.
+func (c *Client) applyTLSSettings() {
+	if c.tlsConfig == nil && c.caCertFile == "" {
+		return
+	}
+	cfg := &tls.Config{}
+	if c.tlsConfig != nil {
+		cfg = c.tlsConfig.Clone()
+	}
+	if c.caCertFile != "" {
+		pool, err := caCertPool(c.caCertFile)
+		if err != nil {
+			c.transport = errTransport{fmt.Errorf("failed to load CA certificate: %w", err)}
+			return
+		}
+		cfg.RootCAs = pool
+	}
+	switch t := c.transport.(type) {
+	case tlsConfigurer:
+		t.SetTLSClientConfig(cfg)
+	case *http.Transport:
+		t = t.Clone()
+		t.TLSClientConfig = cfg
+		c.transport = t
+	default:
+		c.transport = errTransport{errors.New("the configured transport does not accept TLS settings; configure it directly before passing it to WithTransport")}
+	}
+}
+
+// caCertPool returns the system certificate pool augmented with the
+// PEM-encoded certificate(s) at the given path.
+func caCertPool(path string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %v", path)
+	}
+	return pool, nil
+}
+
+// errTransport reports a deferred transport configuration error on use.
+type errTransport struct{ err error }
+
+func (t errTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
 // WithPipelinesProvider sets implementation of provider responsible for CI/CD pipelines
 func WithPipelinesProvider(pp PipelinesProvider) Option {
 	return func(c *Client) {
@@ -437,6 +1123,62 @@ func (c *Client) Runtimes() ([]string, error) {
 	return runtimes.Items(), nil
 }
 
+// SupportedRuntimes returns the locally-available runtimes (see Runtimes)
+// narrowed to those the target cluster reports being able to run, when the
+// deployer or pipelines provider exposes that capability information (see
+// RuntimeCapabilityLister).  When neither does, the local list is returned
+// unchanged.
+func (c *Client) SupportedRuntimes(ctx context.Context) ([]string, error) {
+	local, err := c.Runtimes()
+	if err != nil {
+		return nil, err
+	}
+
+	lister, ok := c.deployer.(RuntimeCapabilityLister)
+	if !ok {
+		if lister, ok = c.pipelinesProvider.(RuntimeCapabilityLister); !ok {
+			return local, nil // no cluster capability information available
+		}
+	}
+
+	remote, err := lister.SupportedRuntimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	supported := make(map[string]bool, len(remote))
+	for _, r := range remote {
+		supported[r] = true
+	}
+
+	// Intersect, preserving the local list's order (sorted).
+	intersection := []string{}
+	for _, r := range local {
+		if supported[r] {
+			intersection = append(intersection, r)
+		}
+	}
+	return intersection, nil
+}
+
+// BuilderImages returns the default builder image which would be used to
+// build the given runtime, keyed by builder implementation short name
+// (e.g. "pack", "s2i").  Defaults are registered via
+// WithBuilderImageDefaults; a function may override them via its
+// .Build.BuilderImages member.  An error is returned for a runtime unknown
+// to all registered builders.
+func (c *Client) BuilderImages(runtime string) (map[string]string, error) {
+	images := map[string]string{}
+	for builder, defaults := range c.builderImageDefaults {
+		if image, ok := defaults[runtime]; ok {
+			images[builder] = image
+		}
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no builder images known for runtime %q", runtime)
+	}
+	return images, nil
+}
+
 // LIFECYCLE METHODS
 // -----------------
 
@@ -454,10 +1196,37 @@ func (c *Client) New(ctx context.Context, cfg Function) (err error) {
 		c.progressListener.Stopping()
 	}()
 
+	// When so configured, undo completed steps should a subsequent step
+	// fail, rather than leaving partial state (see WithRollbackOnFailure).
+	// The original error is retained, with any errors encountered during
+	// the rollback itself appended.
+	var (
+		created     bool            // this invocation created files on disk
+		deployed    bool            // this invocation deployed the function
+		preexisting map[string]bool // root dir entries prior to creation
+	)
+	if cfg.Root == "" {
+		cfg.Root = "." // Create defaults to the current working directory
+	}
+	if c.rollbackOnFailure {
+		if preexisting, err = dirEntries(cfg.Root); err != nil {
+			return
+		}
+		defer func() {
+			if err == nil {
+				return
+			}
+			if rbErr := c.rollbackNew(ctx, cfg.Root, preexisting, created, deployed); rbErr != nil {
+				err = fmt.Errorf("%w (rollback: %v)", err, rbErr)
+			}
+		}()
+	}
+
 	// Create function at path indidcated by Config
 	if err = c.Create(cfg); err != nil {
 		return
 	}
+	created = true
 
 	// Load the now-initialized function.
 	f, err := NewFunction(cfg.Root)
@@ -480,9 +1249,10 @@ func (c *Client) New(ctx context.Context, cfg Function) (err error) {
 	// Deploy the initialized function, returning its publicly
 	// addressible name for possible registration.
 	c.progressListener.Increment("Deploying function to cluster")
-	if err = c.Deploy(ctx, f.Root); err != nil {
+	if _, err = c.Deploy(ctx, f.Root); err != nil {
 		return
 	}
+	deployed = true
 
 	// Create an external route to the function
 	c.progressListener.Increment("Creating route to function")
@@ -501,12 +1271,90 @@ func (c *Client) New(ctx context.Context, cfg Function) (err error) {
 	return
 }
 
+// rollbackNew undoes the steps of New which completed before a failure:
+// a deployed (but unrouted) service is removed from the cluster, and disk
+// entries created within root (those not preexisting) are deleted.  The
+// root directory itself is removed only if it too was created.
+func (c *Client) rollbackNew(ctx context.Context, root string, preexisting map[string]bool, created, deployed bool) error {
+	errs := []string{}
+	if deployed {
+		f, err := NewFunction(root)
+		if err != nil {
+			errs = append(errs, err.Error())
+		} else if err = c.remover.Remove(ctx, f.Name); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if created {
+		ee, err := os.ReadDir(root)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		for _, e := range ee {
+			if preexisting[e.Name()] {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(root, e.Name())); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if preexisting == nil { // nil indicates the directory did not exist
+			if err := os.Remove(root); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// dirEntries returns the names of all entries in the directory at path, or
+// nil (without error) should the directory not exist.
+func dirEntries(path string) (map[string]bool, error) {
+	ee, err := os.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	names := make(map[string]bool, len(ee))
+	for _, e := range ee {
+		names[e.Name()] = true
+	}
+	return names, nil
+}
+
+// emit a lifecycle event to the registered listener, if any.
+// See WithEventListener.
+func (c *Client) emit(phase, status, detail string) {
+	if c.eventListener != nil {
+		c.eventListener(Event{Phase: phase, Status: status, Detail: detail})
+	}
+}
+
+// emitResult emits a succeeded or failed event for the phase based on the
+// given error.  Intended for use in a defer evaluating the phase's named
+// error return.
+func (c *Client) emitResult(phase string, err error) {
+	if err != nil {
+		c.emit(phase, EventFailed, err.Error())
+		return
+	}
+	c.emit(phase, EventSucceeded, "")
+}
+
 // Create a new function from the given defaults.
 // <path> will default to the absolute path of the current working directory.
 // <name> will default to the current working directory.
 // When <name> is provided but <path> is not, a directory <name> is created
 // in the current working directory and used for <path>.
 func (c *Client) Create(cfg Function) (err error) {
+	c.emit(PhaseCreate, EventStarted, "")
+	defer func() { c.emitResult(PhaseCreate, err) }()
+
 	// convert Root path to absolute
 	cfg.Root, err = filepath.Abs(cfg.Root)
 	cfg.SpecVersion = LastSpecVersion()
@@ -546,11 +1394,17 @@ func (c *Client) Create(cfg Function) (err error) {
 		return err
 	}
 
-	// Create a new function (in memory)
+	// Template is defaulted to the client's configured default, if any
+	// (the static default applies otherwise; see NewFunctionWith).
+	if cfg.Template == "" {
+		cfg.Template = c.defaultTemplate
+	}
+
+	// Create a new function (in memory)
 	f := NewFunctionWith(cfg)
 
 	// Create a .func diretory which is also added to a .gitignore
-	if err = ensureRuntimeDir(f); err != nil {
+	if err = ensureRuntimeDir(f, c.runDataPath(f.Root), c.noGitignore); err != nil {
 		return
 	}
 
@@ -565,53 +1419,219 @@ func (c *Client) Create(cfg Function) (err error) {
 
 	// Mark the function as having been created
 	f.Created = time.Now()
-	err = f.Write()
+	if err = f.Write(); err != nil {
+		return
+	}
+
+	// Run the template's post-create hook, if any, when enabled
+	// (see WithRunPostCreate)
+	if c.runPostCreate {
+		err = c.runPostCreateHook(f)
+	}
 	return
 }
 
+// runPostCreateHook runs the post-create command, if any, declared by the
+// manifest of the function's template.  Part of Create when enabled via
+// WithRunPostCreate.  The command is run via the shell in the new project
+// directory; output is printed directly when verbose, otherwise captured
+// and only shown on failure.  A non-zero exit is surfaced as an error.
+func (c *Client) runPostCreateHook(f Function) error {
+	t, err := c.Templates().Get(f.Runtime, f.Template)
+	if err != nil {
+		return err
+	}
+	hook := t.PostCreate()
+	if hook == "" {
+		return nil
+	}
+
+	c.progressListener.Increment(fmt.Sprintf("Running post-create hook: %v", hook))
+
+	cmd := exec.Command("sh", "-c", hook)
+	cmd.Dir = f.Root
+	if c.verbose {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err = cmd.Run()
+	} else {
+		var out []byte
+		if out, err = cmd.CombinedOutput(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s", out)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("post-create hook of template %q failed: %w", f.Template, err)
+	}
+	return nil
+}
+
+// Clone creates a new function at destPath by copying the source tree of the
+// initialized function at srcPath, renaming it to newName and clearing state
+// which is specific to the source (image, digest and creation timestamp).
+// Local runtime data (.func) and git history are not copied.  The destination
+// must be empty enough to hold a new function (see assertEmptyRoot).
+func (c *Client) Clone(srcPath, destPath, newName string) (err error) {
+	// Source must be an initialized function
+	src, err := NewFunction(srcPath)
+	if err != nil {
+		return
+	}
+	if !src.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", srcPath)
+	}
+
+	// convert dest path to absolute
+	if destPath, err = filepath.Abs(destPath); err != nil {
+		return
+	}
+
+	// Name is defaulted to the directory of the destination path.
+	if newName == "" {
+		newName = nameFromPath(destPath)
+	}
+	if err = utils.ValidateFunctionName(newName); err != nil {
+		return
+	}
+
+	// Create the destination directory, if it doesn't already exist, and
+	// ensure it contains no contentious files.
+	if err = os.MkdirAll(destPath, 0755); err != nil {
+		return
+	}
+	if err = assertEmptyRoot(destPath); err != nil {
+		return
+	}
+
+	// Copy the source tree, excluding local runtime data and git history,
+	// neither of which apply to the new function.
+	accessor := maskingFS{
+		masked: func(p string) bool { return p == RunDataDir || p == ".git" },
+		fs:     osFilesystem{root: src.Root},
+	}
+	if err = copyFromFS(".", destPath, accessor); err != nil {
+		return
+	}
+
+	// Load the copy and reset state specific to the source function.
+	f, err := NewFunction(destPath)
+	if err != nil {
+		return
+	}
+	f.Name = newName
+	f.Image = ""
+	f.ImageDigest = ""
+	f.Created = time.Now()
+	return f.Write()
+}
+
+// runDataPath returns the directory holding the transient runtime metadata
+// of the function rooted at root: by default RunDataDir (".func") within the
+// root itself, possibly relocated via WithRunDataDir (relative values
+// resolving against the root).
+func (c *Client) runDataPath(root string) string {
+	dir := c.runDataDir
+	if dir == "" {
+		dir = RunDataDir
+	}
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(root, dir)
+}
+
 // Tag the function as having been built
 // This is locally-scoped data, only indicating there presumably exists
 // a container image in the cache of the the configured builder, thus this info
 // is placed in a .func (non-source controlled) local metadata directory, which
 // is not stritly required to exist, so it is created if needed.
-func updateBuildStamp(f Function) (err error) {
-	if err = ensureRuntimeDir(f); err != nil {
+func updateBuildStamp(f Function, runDataPath string, followSymlinks, noGitignore bool) (err error) {
+	if err = ensureRuntimeDir(f, runDataPath, noGitignore); err != nil {
 		return err
 	}
-	hash, err := fingerprint(f)
+	hash, files, err := fingerprintDetail(f, runDataPath, followSymlinks)
 	if err != nil {
 		return err
 	}
-	if err = os.WriteFile(filepath.Join(f.Root, RunDataDir, buildstamp), []byte(hash), os.ModePerm); err != nil {
+	if err = os.WriteFile(filepath.Join(runDataPath, buildstamp), []byte(hash), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Alongside the stamp, record the contributing files such that a later
+	// fingerprint mismatch can be explained by comparison.
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	b := strings.Builder{}
+	for _, path := range paths {
+		fmt.Fprintf(&b, "%v\t%v\n", files[path].UnixNano(), path)
+	}
+	if err = os.WriteFile(filepath.Join(runDataPath, buildfiles), []byte(b.String()), os.ModePerm); err != nil {
 		return err
 	}
 	return
 }
 
-// ensureRuntimeDir creates a .func directory in the root of the given
-// function which is also registered as ignored in .gitignore
-// TODO: Mutate extant .gitignore file if it exists rather than failing
-// if present (see contentious files in function.go), such that a user
-// can `git init` a directory prior to `func init` in the same directory).
-func ensureRuntimeDir(f Function) error {
-	if err := os.MkdirAll(filepath.Join(f.Root, RunDataDir), os.ModePerm); err != nil {
+// ensureRuntimeDir creates the function's run data directory (by default
+// .func in the root) which, when in the default location, is also registered
+// as ignored in .gitignore.  A relocated directory (see WithRunDataDir) is
+// simply created; it is outside the source tree's concern.  An extant
+// .gitignore is mutated (the entry appended when missing) rather than
+// overwritten, and creation can be skipped entirely via WithNoGitignore.
+func ensureRuntimeDir(f Function, runDataPath string, noGitignore bool) error {
+	if err := os.MkdirAll(runDataPath, os.ModePerm); err != nil {
 		return err
 	}
 
-	gitignore := `
+	// Only the default in-project location warrants a source control ignore,
+	// and creation can be skipped entirely (see WithNoGitignore).
+	if noGitignore || runDataPath != filepath.Join(f.Root, RunDataDir) {
+		return nil
+	}
+
+	entry := `
 # Functions use the .func directory for local runtime data which should
 # generally not be tracked in source control:
 /.func
 `
-	return os.WriteFile(filepath.Join(f.Root, ".gitignore"), []byte(gitignore), os.ModePerm)
+	path := filepath.Join(f.Root, ".gitignore")
+	existing, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.WriteFile(path, []byte(entry), os.ModePerm)
+	} else if err != nil {
+		return err
+	}
 
+	// Leave an existing .gitignore untouched when it already has the entry.
+	for _, line := range strings.Split(string(existing), "\n") {
+		switch strings.TrimSpace(line) {
+		case "/.func", ".func", "/.func/", ".func/":
+			return nil
+		}
+	}
+
+	// Append the entry, preserving existing content and ensuring it remains
+	// separated from that content by a linebreak.
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		entry = "\n" + entry
+	}
+	return os.WriteFile(path, append(existing, []byte(entry)...), os.ModePerm)
 }
 
 // Build the function at path. Errors if the function is either unloadable or does
 // not contain a populated Image.
 func (c *Client) Build(ctx context.Context, path string) (err error) {
+	c.emit(PhaseBuild, EventStarted, "")
+	defer func() { c.emitResult(PhaseBuild, err) }()
 	c.progressListener.Increment("Building function image")
-	ctx, cancel := context.WithCancel(ctx)
+	var cancel context.CancelFunc
+	if c.buildTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.buildTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
 	defer cancel()
 	// If not logging verbosely, the ongoing progress of the build will not
 	// be streaming to stdout, and the lack of activity has been seen to cause
@@ -640,7 +1660,20 @@ func (c *Client) Build(ctx context.Context, path string) (err error) {
 		}
 	}
 
+	// Builders which support it validate their suitability for the function
+	// (builder selection and runtime support) before the build commences.
+	if validator, ok := c.builder.(ValidatingBuilder); ok {
+		if err = validator.Validate(f); err != nil {
+			return
+		}
+	}
+
 	if err = c.builder.Build(ctx, f); err != nil {
+		// A build canceled by exceeding the configured timeout is reported
+		// as such rather than as a generic context error.
+		if c.buildTimeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = ErrBuildTimeout
+		}
 		return
 	}
 
@@ -651,7 +1684,7 @@ func (c *Client) Build(ctx context.Context, path string) (err error) {
 	}
 
 	// Tag the function as having been built
-	if err = updateBuildStamp(f); err != nil {
+	if err = updateBuildStamp(f, c.runDataPath(f.Root), c.followSymlinks, c.noGitignore); err != nil {
 		return
 	}
 
@@ -665,6 +1698,98 @@ func (c *Client) Build(ctx context.Context, path string) (err error) {
 	return
 }
 
+// BuildIntoTar builds the function at path and exports the resulting image
+// from the container runtime to outfile as a docker-compatible tarball,
+// suitable for promotion into disconnected environments via 'docker load' or
+// a subsequent push from another host.  The image reference is recorded in
+// func.yaml as with Build; no registry push is performed.  The tarball is
+// written deterministically for a given image, so reproducibility is that of
+// the configured builder.
+func (c *Client) BuildIntoTar(ctx context.Context, path, outfile string) (err error) {
+	if err = c.Build(ctx, path); err != nil {
+		return
+	}
+
+	f, err := NewFunction(path)
+	if err != nil {
+		return
+	}
+
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		return fmt.Errorf("cannot parse image reference %v: %w", f.Image, err)
+	}
+
+	c.progressListener.Increment(fmt.Sprintf("Exporting image to %v", outfile))
+	img, err := daemon.Image(ref, daemon.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("cannot read built image from the container runtime: %w", err)
+	}
+	return tarball.WriteToFile(outfile, ref, img)
+}
+
+// BuildAll builds the functions rooted at each of the given paths, running
+// up to concurrency builds simultaneously.  Returns the error (or nil) for
+// each root.  Progress messages are prefixed with the root to which they
+// pertain.  Builds not yet started when the context is canceled are
+// recorded as having failed with the context's error.
+func (c *Client) BuildAll(ctx context.Context, roots []string, concurrency int) map[string]error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+		results = make(map[string]error, len(roots))
+	)
+	for _, root := range roots {
+		root := root
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				mu.Lock()
+				results[root] = ctx.Err()
+				mu.Unlock()
+				return
+			}
+			defer func() { <-sem }()
+
+			// A shallow copy of the client whose progress messages are
+			// prefixed such that interleaved updates can be disambiguated.
+			cc := *c
+			cc.progressListener = &prefixProgressListener{prefix: root, next: c.progressListener}
+			err := cc.Build(ctx, root)
+
+			mu.Lock()
+			results[root] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// prefixProgressListener decorates a progress listener, prefixing messages
+// with the function root on whose behalf they are emitted.
+type prefixProgressListener struct {
+	prefix string
+	next   ProgressListener
+}
+
+func (p *prefixProgressListener) SetTotal(i int) { p.next.SetTotal(i) }
+func (p *prefixProgressListener) Increment(m string) {
+	p.next.Increment(p.prefix + ": " + m)
+}
+func (p *prefixProgressListener) Complete(m string) {
+	p.next.Complete(p.prefix + ": " + m)
+}
+func (p *prefixProgressListener) Stopping() { p.next.Stopping() }
+func (p *prefixProgressListener) Done()     { p.next.Done() }
+
 func (c *Client) printBuildActivity(ctx context.Context) {
 	m := []string{
 		"Still building",
@@ -692,9 +1817,93 @@ func (c *Client) printBuildActivity(ctx context.Context) {
 	}()
 }
 
-// Deploy the function at path. Errors if the function has not been
-// initialized with an image tag.
-func (c *Client) Deploy(ctx context.Context, path string) (err error) {
+// Manifest returns the deployment manifest which would be applied when
+// deploying the function at path, serialized as YAML, without contacting
+// the cluster.  Useful for GitOps workflows in which the manifest is
+// committed to a repository rather than applied directly.  Errors if the
+// function has not been built or the configured deployer does not support
+// manifest generation.
+func (c *Client) Manifest(ctx context.Context, path string) ([]byte, error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return nil, err
+	}
+	if c.image != "" {
+		if f.Image, f.ImageDigest, err = ParseImage(c.image); err != nil {
+			return nil, err
+		}
+	}
+	if !f.HasImage() {
+		return nil, ErrNotBuilt
+	}
+	generator, ok := c.deployer.(ManifestGenerator)
+	if !ok {
+		return nil, errors.New("the configured deployer does not support manifest generation")
+	}
+	return generator.Manifest(ctx, f)
+}
+
+// EffectiveConfig returns the function at path as commands interpret it:
+// fully migrated with defaults applied, plus notes explaining where
+// non-obvious values originate (migration or default rather than the file
+// itself).  Migrations are applied in memory only; the file at path is
+// never modified as a side effect.
+func (c *Client) EffectiveConfig(path string) (Function, []string, error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return Function{}, nil, err
+	}
+	notes := []string{}
+
+	// Migrations applied in-memory on load.  The raw on-disk spec version is
+	// consulted since the loaded function is already migrated.
+	bb, err := os.ReadFile(filepath.Join(f.Root, FunctionFile))
+	if err != nil {
+		return Function{}, nil, err
+	}
+	onDisk := struct {
+		SpecVersion string `yaml:"specVersion"`
+	}{}
+	if err = yaml.Unmarshal(bb, &onDisk); err != nil {
+		return Function{}, nil, err
+	}
+	plan, err := Function{SpecVersion: onDisk.SpecVersion}.MigrationPlan()
+	if err != nil {
+		return Function{}, nil, err
+	}
+	for _, step := range plan {
+		notes = append(notes, fmt.Sprintf("specVersion %v: %v (migrated in memory; run 'func migrate' to persist)", step.Version, step.Description))
+	}
+
+	// Values which, when not defined in the file, default at deploy time.
+	if f.Deploy.Namespace == "" {
+		notes = append(notes, "namespace: the currently active namespace (default)")
+	}
+	if f.Deploy.HealthEndpoints.Liveness == "" {
+		notes = append(notes, fmt.Sprintf("healthEndpoints.liveness: %v (default)", DefaultLivenessEndpoint))
+	}
+	if f.Deploy.HealthEndpoints.Readiness == "" {
+		notes = append(notes, fmt.Sprintf("healthEndpoints.readiness: %v (default)", DefaultReadinessEndpoint))
+	}
+	if f.Invocation.Format == "" {
+		notes = append(notes, fmt.Sprintf("invocation.format: %v (default)", DefaultInvocationFormat))
+	}
+	if f.Run.ImagePullPolicy == "" {
+		notes = append(notes, "run.imagePullPolicy: the platform's default")
+	}
+	if f.Run.Resources == nil && f.Deploy.Options.Resources == nil {
+		notes = append(notes, "run.resources: the platform's defaults")
+	}
+
+	return f, notes, nil
+}
+
+// Deploy the function at path, returning the deployment result (URL,
+// namespace and, when reported by the platform, the created revision).
+// Errors if the function has not been initialized with an image tag.
+func (c *Client) Deploy(ctx context.Context, path string) (result DeploymentResult, err error) {
+	c.emit(PhaseDeploy, EventStarted, "")
+	defer func() { c.emitResult(PhaseDeploy, err) }()
 	go func() {
 		<-ctx.Done()
 		c.progressListener.Stopping()
@@ -705,15 +1914,65 @@ func (c *Client) Deploy(ctx context.Context, path string) (err error) {
 		return
 	}
 
+	// An externally built image provided via WithImage overrides any image
+	// recorded by a previous build.  A stale digest from an earlier build is
+	// cleared unless the reference itself pins one.
+	if c.image != "" {
+		if f.Image, f.ImageDigest, err = ParseImage(c.image); err != nil {
+			return
+		}
+	}
+
 	// Functions must be built (have an associated image) before being deployed.
 	// Note that externally built images may be specified in the func.yaml
 	if !f.HasImage() {
-		return ErrNotBuilt
+		return result, ErrNotBuilt
+	}
+
+	// Deploy by the exact digest recorded at push when enabled (the
+	// default; see WithDeployByDigest), falling back to the mutable tag
+	// with a warning when no digest was recorded (e.g. an externally built
+	// image which was never pushed by the client).
+	if !c.deployByDigest {
+		f.ImageDigest = "" // deployer receives the tag reference only
+	} else if f.ImageDigest == "" {
+		fmt.Fprintf(os.Stderr, "Warning: no digest recorded for image %v; deploying by tag\n", f.Image)
 	}
 
-	// Deploy a new or Update the previously-deployed function
+	// Deploy a new or Update the previously-deployed function, splitting
+	// traffic with the previously ready revision when requested (see
+	// WithDeployOptions).
 	c.progressListener.Increment("⬆️  Deploying function to the cluster")
-	result, err := c.deployer.Deploy(ctx, f)
+	if c.deployOptionsSet {
+		if c.deployOptions.TrafficPercent < 0 || c.deployOptions.TrafficPercent > 100 {
+			return result, fmt.Errorf("traffic percent must be between 0 and 100, got %v", c.deployOptions.TrafficPercent)
+		}
+		td, ok := c.deployer.(TrafficDeployer)
+		if !ok {
+			return result, errors.New("the configured deployer does not support traffic splitting")
+		}
+		result, err = td.DeployWithTraffic(ctx, f, c.deployOptions)
+	} else {
+		result, err = c.deployer.Deploy(ctx, f)
+	}
+
+	// Record the deployment locally such that the last-known URL can be
+	// read back (see DeployStamp) without querying the cluster.
+	if err == nil && result.URL != "" {
+		if err = updateDeployStamp(f, c.runDataPath(f.Root), result); err != nil {
+			return result, err
+		}
+	}
+
+	// Reapply any subscriptions recorded on the function, such that its
+	// event bindings survive in freshly-created environments.
+	if err == nil {
+		for _, sub := range f.Deploy.Subscriptions {
+			if err = c.subscriber.Subscribe(ctx, f, sub); err != nil {
+				return result, fmt.Errorf("failed to reapply subscription to %q events: %w", sub.Type, err)
+			}
+		}
+	}
 
 	if result.Status == Deployed {
 		c.progressListener.Increment(fmt.Sprintf("✅ Function deployed in namespace %q and exposed at URL: \n   %v", result.Namespace, result.URL))
@@ -721,7 +1980,85 @@ func (c *Client) Deploy(ctx context.Context, path string) (err error) {
 		c.progressListener.Increment(fmt.Sprintf("✅ Function updated in namespace %q and exposed at URL: \n   %v", result.Namespace, result.URL))
 	}
 
-	return err
+	// Optionally garbage-collect revisions beyond the configured history
+	// limit, when supported by the deployer.
+	if err == nil && c.revisionLimit > 0 {
+		if pruner, ok := c.deployer.(RevisionPruner); ok {
+			if err = pruner.PruneRevisions(ctx, f, c.revisionLimit); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	// Optionally wait for the deployed function to report healthy before
+	// returning, making deploys reliable to chain with smoke tests.
+	if err == nil && c.deployWait > 0 && result.URL != "" {
+		if err = c.waitForHealth(ctx, result.URL, f); err != nil {
+			return result, err
+		}
+	}
+
+	// Optionally resolve and report the ingress external address, useful
+	// when configuring DNS for functions exposed via a load balancer.
+	if err == nil && c.reportExternal {
+		var address string
+		if address, err = c.ingressResolver.ExternalAddress(ctx); err != nil {
+			return result, err
+		}
+		c.progressListener.Increment(fmt.Sprintf("Ingress external address: %v", address))
+	}
+
+	return result, err
+}
+
+// waitForHealth polls the function's readiness endpoint at the given base
+// URL until it returns 200, the configured wait timeout elapses, or the
+// context is canceled.  On timeout the returned error includes the last
+// status observed, aiding diagnosis of functions which deployed but never
+// became ready.
+func (c *Client) waitForHealth(ctx context.Context, url string, f Function) error {
+	endpoint := f.Deploy.HealthEndpoints.Readiness
+	if endpoint == "" {
+		endpoint = DefaultReadinessEndpoint
+	}
+	target := strings.TrimSuffix(url, "/") + endpoint
+
+	c.progressListener.Increment(fmt.Sprintf("Waiting for function to become ready at %v", target))
+
+	ctx, cancel := context.WithTimeout(ctx, c.deployWait)
+	defer cancel()
+
+	last := "no response"
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err == nil {
+			last = res.Status
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				c.progressListener.Increment("Function is ready")
+				return nil
+			}
+		} else if ctx.Err() == nil {
+			last = err.Error()
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("function failed to become ready within %v (last response: %v)", c.deployWait, last)
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExternalAddress returns the external IP or hostname assigned to the
+// cluster ingress, polling until assigned (or context cancellation).
+func (c *Client) ExternalAddress(ctx context.Context) (string, error) {
+	return c.ingressResolver.ExternalAddress(ctx)
 }
 
 // RunPipeline runs a Pipeline to build and deploy the function.
@@ -762,6 +2099,8 @@ func (c *Client) Route(path string) (err error) {
 	// but DNS subdomain CNAME to the Kourier Load Balancer is
 	// still manual, and the initial cluster config to suppot the TLD
 	// is still manual.
+	c.emit(PhaseRoute, EventStarted, "")
+	defer func() { c.emitResult(PhaseRoute, err) }()
 	f, err := NewFunction(path)
 	if err != nil {
 		return
@@ -800,6 +2139,35 @@ func (c *Client) Run(ctx context.Context, root string) (job *Job, err error) {
 	return job, nil
 }
 
+// Stop a running local instance of the function whose source is at root,
+// possibly one started by a separate process (enabling, for example, IDE
+// stop buttons and CI cleanup).  The run is located via the record written
+// to the function's run data directory on start, and its owning process is
+// signaled to shut down gracefully (the Job.Stop path).  Stale records
+// whose process no longer exists are removed.  Returns ErrNotRunning when
+// no run is active.
+func (c *Client) Stop(ctx context.Context, root string) error {
+	f, err := NewFunction(root)
+	if err != nil {
+		return err
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", root)
+	}
+
+	record, err := readRunRecord(f)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotRunning
+		}
+		return err
+	}
+
+	// Liveness detection and graceful termination are signal-based and thus
+	// platform-specific; see stop_unix.go and stop_windows.go.
+	return stopRun(f, record)
+}
+
 // Info for a function.  Name takes precedence.  If no name is provided,
 // the function defined at root is used.
 func (c *Client) Info(ctx context.Context, name, root string) (d Instance, err error) {
@@ -824,9 +2192,9 @@ func (c *Client) Info(ctx context.Context, name, root string) (d Instance, err e
 }
 
 // List currently deployed functions.
-func (c *Client) List(ctx context.Context) ([]ListItem, error) {
+func (c *Client) List(ctx context.Context, opts ListOptions) ([]ListItem, error) {
 	// delegate to concrete implementation of lister entirely.
-	return c.lister.List(ctx)
+	return c.lister.List(ctx, opts)
 }
 
 // Remove a function.  Name takes precedence.  If no name is provided,
@@ -851,6 +2219,14 @@ func (c *Client) Remove(ctx context.Context, cfg Function, deleteAll bool) error
 		cfg = f
 	}
 
+	// Back up the function before removal when so configured, aborting
+	// the removal should the backup fail.
+	if c.removeBackupDir != "" {
+		if err := c.backup(ctx, functionName, cfg); err != nil {
+			return fmt.Errorf("unable to back up function before removal: %w", err)
+		}
+	}
+
 	// Delete Knative Service and dependent resources in parallel
 	c.progressListener.Increment(fmt.Sprintf("Removing Knative Service: %v", functionName))
 	errChan := make(chan error)
@@ -874,6 +2250,212 @@ func (c *Client) Remove(ctx context.Context, cfg Function, deleteAll bool) error
 	return errService
 }
 
+// Rollback a deployed function of the given name to the previous Ready
+// revision, or to a specific revision when provided, moving 100% of
+// traffic to it.  A function with no prior revision cannot be rolled back
+// and is an error.
+func (c *Client) Rollback(ctx context.Context, name, revision string) error {
+	go func() {
+		<-ctx.Done()
+		c.progressListener.Stopping()
+	}()
+	if name == "" {
+		return errors.New("name is required to roll back")
+	}
+	return c.rollbacker.Rollback(ctx, name, revision)
+}
+
+// Subscribe the function at path to events matching the subscription,
+// creating the necessary platform resources and persisting the
+// subscription to func.yaml such that it is reapplied on subsequent
+// deploys.  Subscribing twice with the same values is a no-op.
+func (c *Client) Subscribe(ctx context.Context, path string, sub Subscription) error {
+	f, err := NewFunction(path)
+	if err != nil {
+		return err
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", path)
+	}
+	if sub.Type == "" {
+		return errors.New("an event type is required to subscribe")
+	}
+	if sub.Broker == "" {
+		sub.Broker = DefaultBroker
+	}
+
+	if err = c.subscriber.Subscribe(ctx, f, sub); err != nil {
+		return err
+	}
+
+	// Persist such that the subscription is reapplied on deploy, unless
+	// an identical subscription is already recorded.
+	for _, existing := range f.Deploy.Subscriptions {
+		if existing == sub {
+			return nil
+		}
+	}
+	f.Deploy.Subscriptions = append(f.Deploy.Subscriptions, sub)
+	return f.Write()
+}
+
+// Promote the deployed function of the given name from one namespace to
+// another, preserving the exact image digest currently serving traffic in
+// the source such that no rebuild occurs.  A function of the same name
+// already deployed in the target namespace is updated, mirroring the
+// create-or-update semantics of Deploy.
+func (c *Client) Promote(ctx context.Context, name, fromNamespace, toNamespace string) error {
+	go func() {
+		<-ctx.Done()
+		c.progressListener.Stopping()
+	}()
+	if name == "" {
+		return errors.New("name is required to promote")
+	}
+	if fromNamespace == "" || toNamespace == "" {
+		return errors.New("both source and target namespaces are required to promote")
+	}
+	if fromNamespace == toNamespace {
+		return errors.New("source and target namespaces must differ")
+	}
+	return c.promoter.Promote(ctx, name, fromNamespace, toNamespace)
+}
+
+// backup writes the function's live spec (as reported by the describer)
+// and, when available, its local func.yaml to a timestamped file in the
+// configured backup directory (see WithRemoveBackup).
+func (c *Client) backup(ctx context.Context, name string, f Function) (err error) {
+	instance, err := c.describer.Describe(ctx, name)
+	if err != nil {
+		return
+	}
+	bb, err := yaml.Marshal(instance)
+	if err != nil {
+		return
+	}
+
+	// Append the local func.yaml, if any, as a second yaml document.
+	if f.Root != "" {
+		if local, e := os.ReadFile(filepath.Join(f.Root, FunctionFile)); e == nil {
+			bb = append(bb, []byte("---\n")...)
+			bb = append(bb, local...)
+		}
+	}
+
+	if err = os.MkdirAll(c.removeBackupDir, 0755); err != nil {
+		return
+	}
+	filename := fmt.Sprintf("%v-%v.yaml", name, time.Now().Format("20060102T150405"))
+	return os.WriteFile(filepath.Join(c.removeBackupDir, filename), bb, 0644)
+}
+
+// Diff returns a human-readable comparison of the local function's
+// configuration at path against its currently deployed instance,
+// highlighting the changes a subsequent deploy would apply.  The comparison
+// covers the attributes reported by the describer: image, environment
+// variables, labels, scaling settings and volume mounts.  A function not
+// yet deployed reports that the full configuration would be created.
+func (c *Client) Diff(ctx context.Context, path string) (string, error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return "", err
+	}
+	if !f.Initialized() {
+		return "", fmt.Errorf("the given path '%v' does not contain an initialized function", path)
+	}
+
+	instance, err := c.describer.Describe(ctx, f.Name)
+	if err != nil {
+		return "not deployed: the full configuration would be created on deploy\n", nil
+	}
+
+	b := strings.Builder{}
+	changed := func(attribute, deployed, local string) {
+		if deployed != local {
+			fmt.Fprintf(&b, "  ~ %v: %q -> %q\n", attribute, deployed, local)
+		}
+	}
+
+	// Image (compared by name; the deployed reference typically pins a digest)
+	changed("image", instance.Image, f.ImageWithDigest())
+
+	// Environment variables (literal values only; references are elided by
+	// the describer and likewise skipped locally)
+	local := map[string]string{}
+	for _, env := range f.Run.Envs {
+		if env.Name != nil && env.Value != nil && !strings.Contains(*env.Value, "{{") {
+			local[*env.Name] = *env.Value
+		}
+	}
+	diffMaps(&b, "env", instance.Envs, local)
+
+	// Labels
+	localLabels, err := f.LabelsMap()
+	if err != nil {
+		return "", err
+	}
+	diffMaps(&b, "label", instance.Labels, localLabels)
+
+	// Scaling
+	localScaling := map[string]string{}
+	if f.Deploy.Options.Scale != nil {
+		if f.Deploy.Options.Scale.Min != nil {
+			localScaling["autoscaling.knative.dev/min-scale"] = fmt.Sprintf("%d", *f.Deploy.Options.Scale.Min)
+		}
+		if f.Deploy.Options.Scale.Max != nil {
+			localScaling["autoscaling.knative.dev/max-scale"] = fmt.Sprintf("%d", *f.Deploy.Options.Scale.Max)
+		}
+	}
+	diffMaps(&b, "scaling", instance.Scaling, localScaling)
+
+	// Volume mounts (by path)
+	deployedMounts := map[string]string{}
+	for _, path := range instance.VolumeMounts {
+		deployedMounts[path] = ""
+	}
+	localMounts := map[string]string{}
+	for _, volume := range f.Run.Volumes {
+		if volume.Path != nil {
+			localMounts[*volume.Path] = ""
+		}
+	}
+	diffMaps(&b, "volume mount", deployedMounts, localMounts)
+
+	if b.Len() == 0 {
+		return "no changes: the deployed function matches the local configuration\n", nil
+	}
+	return "changes which would be applied on deploy:\n" + b.String(), nil
+}
+
+// diffMaps writes additions, removals and changes of the local map
+// relative to the deployed map as lines prefixed +, - and ~ respectively.
+func diffMaps(b *strings.Builder, attribute string, deployed, local map[string]string) {
+	keys := map[string]bool{}
+	for k := range deployed {
+		keys[k] = true
+	}
+	for k := range local {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		deployedValue, inDeployed := deployed[k]
+		localValue, inLocal := local[k]
+		switch {
+		case !inDeployed:
+			fmt.Fprintf(b, "  + %v %v=%q\n", attribute, k, localValue)
+		case !inLocal:
+			fmt.Fprintf(b, "  - %v %v (deployed as %q)\n", attribute, k, deployedValue)
+		case deployedValue != localValue:
+			fmt.Fprintf(b, "  ~ %v %v: %q -> %q\n", attribute, k, deployedValue, localValue)
+		}
+	}
+}
+
 // Invoke is a convenience method for triggering the execution of a function
 // for testing and development.  Returned is a map of metadata and a stringified
 // version of the content.
@@ -905,8 +2487,89 @@ func (c *Client) Invoke(ctx context.Context, root string, target string, m Invok
 	return invoke(ctx, c, f, target, m, c.verbose)
 }
 
+// InvokeStream opens a bidirectional streaming connection to the function
+// at root by requesting a protocol upgrade (websocket unless overridden via
+// the message's headers), returning the raw connection for the caller to
+// read, write and ultimately close.  Target environment semantics match
+// Invoke.  Use Invoke for the common one-shot request/response case.
+func (c *Client) InvokeStream(ctx context.Context, root string, target string, m InvokeMessage) (io.ReadWriteCloser, error) {
+	f, err := NewFunction(root)
+	if err != nil {
+		return nil, err
+	}
+
+	// See invoke.go for implementation details
+	return invokeStream(ctx, c, f, target, m, c.verbose)
+}
+
+// SetInvocation updates the invocation format hint of the function at path,
+// allowing, for example, a function created from an 'http' template to be
+// subsequently developed as a CloudEvents handler without recreating the
+// project.  Invoke and event wiring then treat the function accordingly.
+// The format must be supported by at least one template available for the
+// function's runtime.
+func (c *Client) SetInvocation(path, format string) error {
+	f, err := NewFunction(path)
+	if err != nil {
+		return err
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("function at '%v' is not initialized", path)
+	}
+
+	supported, err := c.invocationFormats(f.Runtime)
+	if err != nil {
+		return err
+	}
+	valid := false
+	for _, s := range supported {
+		if s == format {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invocation format %q is not supported by any template for the %q runtime. Supported formats are: %v",
+			format, f.Runtime, strings.Join(supported, ", "))
+	}
+
+	f.Invocation.Format = format
+	return f.Write()
+}
+
+// invocationFormats returns the distinct invocation formats declared by
+// templates available for the given runtime, sorted.  Templates which do not
+// declare a format are counted as supporting the default.
+func (c *Client) invocationFormats(runtime string) ([]string, error) {
+	rr, err := c.Repositories().All()
+	if err != nil {
+		return nil, err
+	}
+	formats := newSortedSet()
+	for _, r := range rr {
+		tt, err := r.Templates(runtime)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tt {
+			impl, ok := t.(template)
+			if !ok {
+				continue
+			}
+			format := impl.config.Invocation.Format
+			if format == "" {
+				format = DefaultInvocationFormat
+			}
+			formats.Add(format)
+		}
+	}
+	return formats.Items(), nil
+}
+
 // Push the image for the named service to the configured registry
 func (c *Client) Push(ctx context.Context, path string) (err error) {
+	c.emit(PhasePush, EventStarted, "")
+	defer func() { c.emitResult(PhasePush, err) }()
 	f, err := NewFunction(path)
 	if err != nil {
 		return
@@ -916,16 +2579,374 @@ func (c *Client) Push(ctx context.Context, path string) (err error) {
 		return ErrNotBuilt
 	}
 
-	imageDigest, err := c.pusher.Push(ctx, f)
+	imageDigest, err := c.pushWithRetry(ctx, f)
 	if err != nil {
 		return
 	}
 
-	// Record the Image Digest pushed.
+	// Push the image, retagged, to any additional registries (mirrors).
+	// Failures are reported but non-fatal unless mirrors are required.
+	for _, registry := range c.mirrorRegistries {
+		mirror := f
+		mirror.Image = substituteRegistry(f.Image, f.Registry, registry)
+		var digest string
+		if digest, err = c.pushWithRetry(ctx, mirror); err != nil {
+			if c.mirrorsRequired {
+				return fmt.Errorf("failed to push to additional registry %q: %w", registry, err)
+			}
+			c.progressListener.Increment(fmt.Sprintf("Warning: failed to push to additional registry %q: %v", registry, err))
+			err = nil
+			continue
+		}
+		c.progressListener.Increment(fmt.Sprintf("Pushed %v (digest %v)", mirror.Image, digest))
+	}
+
+	// Record the Image Digest pushed (that of the primary registry).
 	f.ImageDigest = imageDigest
 	return f.Write()
 }
 
+// substituteRegistry returns the image name with its registry (including
+// any intervening namespace) replaced by the given replacement.  When the
+// image is not prefixed by the expected registry, the image's final path
+// element (name and tag) is appended to the replacement.
+func substituteRegistry(image, registry, replacement string) string {
+	if registry != "" && strings.HasPrefix(image, registry+"/") {
+		return replacement + strings.TrimPrefix(image, registry)
+	}
+	if i := strings.LastIndex(image, "/"); i >= 0 {
+		return replacement + image[i:]
+	}
+	return replacement + "/" + image
+}
+
+// Validate a function prior to build or deploy, returning all problems
+// found rather than failing on the first.  This is a deeper check than the
+// syntactic validation performed when loading (see Function.Validate),
+// additionally confirming the name is a valid DNS label, the runtime is
+// available from the currently configured repositories, volume paths are
+// absolute, env names are valid identifiers and health endpoints are
+// well-formed paths.  An empty return indicates the function is valid.
+func (c *Client) Validate(f Function) (errs []error) {
+	// Name must be a valid DNS label (it becomes the service name)
+	if err := utils.ValidateFunctionName(f.Name); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Runtime must be among those currently available
+	runtimes, err := c.Runtimes()
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		found := false
+		for _, runtime := range runtimes {
+			if f.Runtime == runtime {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("runtime %q is not among those available: %v", f.Runtime, runtimes))
+		}
+	}
+
+	// Volumes must reference exactly one source and an absolute path
+	for _, msg := range validateVolumes(f.Run.Volumes) {
+		errs = append(errs, errors.New(msg))
+	}
+	for _, v := range f.Run.Volumes {
+		if v.Path != nil && !filepath.IsAbs(*v.Path) {
+			errs = append(errs, fmt.Errorf("volume path %q is not absolute", *v.Path))
+		}
+	}
+
+	// Env names must be valid identifiers
+	for _, msg := range ValidateEnvs(f.Run.Envs) {
+		errs = append(errs, errors.New(msg))
+	}
+	for _, e := range f.Run.Envs {
+		if e.Name == nil {
+			continue
+		}
+		if err := utils.ValidateEnvVarName(*e.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Health endpoints, when defined, must be absolute paths
+	for _, endpoint := range []string{
+		f.Deploy.HealthEndpoints.Liveness,
+		f.Deploy.HealthEndpoints.Readiness} {
+		if endpoint != "" && !strings.HasPrefix(endpoint, "/") {
+			errs = append(errs, fmt.Errorf("health endpoint %q must be an absolute path", endpoint))
+		}
+	}
+
+	// Probe timings, when defined, must be positive
+	he := f.Deploy.HealthEndpoints
+	for _, timing := range []struct {
+		name  string
+		value *int32
+	}{
+		{"initialDelaySeconds", he.InitialDelaySeconds},
+		{"periodSeconds", he.PeriodSeconds},
+		{"failureThreshold", he.FailureThreshold},
+	} {
+		if timing.value != nil && *timing.value <= 0 {
+			errs = append(errs, fmt.Errorf("health endpoint %v must be a positive value", timing.name))
+		}
+	}
+
+	// Image pull policy, when defined, must be a recognized value
+	switch f.Run.ImagePullPolicy {
+	case "", "Always", "IfNotPresent", "Never":
+		// valid
+	default:
+		errs = append(errs, fmt.Errorf("image pull policy %q is not valid; must be one of Always, IfNotPresent or Never", f.Run.ImagePullPolicy))
+	}
+
+	// Resources, when defined, must parse as quantities with limits of at
+	// least their corresponding requests
+	for _, msg := range validateResources(f.Run.Resources, "run") {
+		errs = append(errs, errors.New(msg))
+	}
+
+	return
+}
+
+// pushWithRetry invokes the pusher, retrying transient errors with
+// exponential backoff up to the configured number of retries (see
+// WithPushRetries), respecting context cancellation between attempts.
+func (c *Client) pushWithRetry(ctx context.Context, f Function) (digest string, err error) {
+	backoff := c.pushRetryBackoff
+	for attempt := 0; ; attempt++ {
+		digest, err = c.pusher.Push(ctx, f)
+		if err == nil || attempt >= c.pushRetries || !isTransient(err) {
+			return
+		}
+		c.progressListener.Increment(fmt.Sprintf("Push failed, retrying in %v: %v", backoff, err))
+		select {
+		case <-ctx.Done():
+			return digest, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// isTransient returns whether an error is likely to resolve on retry:
+// network errors and registry responses of 429 or 5xx.  Authentication
+// failures and other client errors are not considered transient.
+func isTransient(err error) bool {
+	var terr *transport.Error
+	if errors.As(err, &terr) {
+		return terr.StatusCode == http.StatusTooManyRequests || terr.StatusCode >= 500
+	}
+	var nerr net.Error
+	return errors.As(err, &nerr)
+}
+
+// updateDeployStamp records a successful deployment in the run data
+// directory.  As with the buildstamp this is locally-scoped transient
+// data and thus excluded from both source control and the fingerprint.
+func updateDeployStamp(f Function, runDataPath string, result DeploymentResult) (err error) {
+	// NOTE: ensureRuntimeDir is not used here because it also rewrites
+	// .gitignore, which would alter the fingerprint and thus incorrectly
+	// mark the function stale on every deploy.
+	if err = os.MkdirAll(runDataPath, os.ModePerm); err != nil {
+		return
+	}
+	bb, err := yaml.Marshal(DeployStamp{
+		URL:       result.URL,
+		Namespace: result.Namespace,
+		Deployed:  time.Now(),
+	})
+	if err != nil {
+		return
+	}
+	return os.WriteFile(filepath.Join(runDataPath, deploystamp), bb, os.ModePerm)
+}
+
+// DeployStamp returns the record of the last successful deployment of the
+// function at path, as persisted at deploy time.  Errors if the function
+// has not been deployed from this copy of its source.
+func (c *Client) DeployStamp(path string) (stamp DeployStamp, err error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return
+	}
+	bb, err := os.ReadFile(filepath.Join(c.runDataPath(f.Root), deploystamp))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = errors.New("no deployment recorded: the function has not been deployed from this directory")
+		}
+		return
+	}
+	err = yaml.Unmarshal(bb, &stamp)
+	return
+}
+
+// Touch marks the function at path as stale by discarding the record of
+// its previous build, such that the next build or deploy rebuilds it even
+// though no source files have changed (e.g. to pick up a base image fix).
+// Touching a function which has never been built is a no-op.
+func (c *Client) Touch(path string) error {
+	f, err := NewFunction(path)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(filepath.Join(c.runDataPath(f.Root), buildstamp)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// Also remove the stamp's accompanying file detail, which without the
+	// stamp itself is meaningless.
+	if err = os.Remove(filepath.Join(c.runDataPath(f.Root), buildfiles)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Repair attempts to reconstruct a usable Function from the possibly
+// corrupted func.yaml of the function at path.  The file is parsed as
+// leniently as possible (a truncated file is salvaged by discarding
+// unparseable trailing lines, and per-field type errors are tolerated),
+// missing required fields are defaulted, migrations applied, and any
+// members which remain invalid are reset to their zero value.  Returned is
+// the repaired function along with a description of each repair performed.
+// Nothing is written; persist the result with WriteRepaired (or
+// Function.Write, which drops fields unknown to this version).
+func (c *Client) Repair(path string) (f Function, repaired []string, err error) {
+	if path, err = filepath.Abs(path); err != nil {
+		return
+	}
+	bb, err := os.ReadFile(filepath.Join(path, FunctionFile))
+	if err != nil {
+		return
+	}
+
+	bb, discarded := salvageYAML(bb)
+	if discarded > 0 {
+		repaired = append(repaired, fmt.Sprintf("discarded %v unparseable trailing line(s)", discarded))
+	}
+
+	f.Root = path
+	f.Build.BuilderImages = make(map[string]string)
+	f.Deploy.Annotations = make(map[string]string)
+	if unmarshalErr := yaml.Unmarshal(bb, &f); unmarshalErr != nil {
+		// Type errors affect only the fields in question, which are left at
+		// their zero value; anything else should have been caught by the
+		// salvage pass above.
+		var typeErr *yaml.TypeError
+		if !errors.As(unmarshalErr, &typeErr) {
+			return Function{}, nil, fmt.Errorf("unable to repair: %w", unmarshalErr)
+		}
+		repaired = append(repaired, typeErr.Errors...)
+	}
+
+	// Required fields which can be defaulted
+	if f.Name == "" {
+		f.Name = nameFromPath(path)
+		repaired = append(repaired, "name (defaulted from directory)")
+	}
+	if _, semverErr := semver.NewVersion(f.SpecVersion); f.SpecVersion != "" && semverErr != nil {
+		f.SpecVersion = "" // all migrations apply
+		repaired = append(repaired, "specVersion (reset for remigration)")
+	}
+	if f.Runtime == "" {
+		repaired = append(repaired, "runtime (unset; set manually)")
+	}
+
+	// Bring up to the current spec
+	if f, err = f.Migrate(); err != nil {
+		return Function{}, repaired, err
+	}
+
+	// Reset members which remain invalid, recording each.
+	if ee := validateVolumes(f.Run.Volumes); len(ee) > 0 {
+		f.Run.Volumes = nil
+		repaired = append(repaired, "volumes")
+	}
+	if ee := ValidateBuildEnvs(f.Build.BuildEnvs); len(ee) > 0 {
+		f.Build.BuildEnvs = nil
+		repaired = append(repaired, "buildEnvs")
+	}
+	if ee := validatePlatformBuildEnvs(f.Build.PlatformBuildEnvs); len(ee) > 0 {
+		f.Build.PlatformBuildEnvs = nil
+		repaired = append(repaired, "platformBuildEnvs")
+	}
+	if ee := ValidateEnvs(f.Run.Envs); len(ee) > 0 {
+		f.Run.Envs = nil
+		repaired = append(repaired, "envs")
+	}
+	if ee := validateOptions(f.Deploy.Options); len(ee) > 0 {
+		f.Deploy.Options = Options{}
+		repaired = append(repaired, "options")
+	}
+	if ee := ValidateLabels(f.Deploy.Labels); len(ee) > 0 {
+		f.Deploy.Labels = nil
+		repaired = append(repaired, "labels")
+	}
+	if ee := validateGit(f.Build.Git); len(ee) > 0 {
+		f.Build.Git = Git{}
+		repaired = append(repaired, "git")
+	}
+	return
+}
+
+// WriteRepaired persists a function produced by Repair to its func.yaml,
+// preserving any unknown top-level fields present in the salvageable
+// portion of the original file, which Function.Write would drop.
+func (c *Client) WriteRepaired(f Function) error {
+	path := filepath.Join(f.Root, FunctionFile)
+
+	bb, err := yaml.Marshal(&f)
+	if err != nil {
+		return err
+	}
+	known := yaml.MapSlice{}
+	if err = yaml.Unmarshal(bb, &known); err != nil {
+		return err
+	}
+	keys := map[interface{}]bool{}
+	for _, item := range known {
+		keys[item.Key] = true
+	}
+
+	// Retain top-level fields from the original which are unknown to this
+	// version of the schema (e.g. from a newer client).
+	if orig, readErr := os.ReadFile(path); readErr == nil {
+		salvaged, _ := salvageYAML(orig)
+		original := yaml.MapSlice{}
+		if yaml.Unmarshal(salvaged, &original) == nil {
+			for _, item := range original {
+				if !keys[item.Key] {
+					known = append(known, item)
+				}
+			}
+		}
+	}
+
+	if bb, err = yaml.Marshal(known); err != nil {
+		return err
+	}
+	return os.WriteFile(path, bb, 0644)
+}
+
+// salvageYAML returns the largest parseable prefix of the given document,
+// along with the number of trailing lines discarded to achieve it, such
+// that a truncated or trailing-garbage write can be partially recovered.
+func salvageYAML(bb []byte) ([]byte, int) {
+	lines := strings.Split(string(bb), "\n")
+	for discarded := 0; discarded < len(lines); discarded++ {
+		candidate := []byte(strings.Join(lines[:len(lines)-discarded], "\n"))
+		probe := yaml.MapSlice{}
+		if yaml.Unmarshal(candidate, &probe) == nil {
+			return candidate, discarded
+		}
+	}
+	return nil, len(lines)
+}
+
 // Built returns true if the given path contains a function which has been
 // built without any filesystem modifications since (is not stale).
 func (c *Client) Built(path string) bool {
@@ -949,7 +2970,7 @@ func (c *Client) Built(path string) bool {
 		return false
 	}
 
-	buildstampPath := filepath.Join(path, RunDataDir, buildstamp)
+	buildstampPath := filepath.Join(c.runDataPath(f.Root), buildstamp)
 
 	// If there is no build stamp, it is also not built.
 	// This case should be redundant with the above check for an image, but is
@@ -959,7 +2980,7 @@ func (c *Client) Built(path string) bool {
 	}
 
 	// Calculate the function's Filesystem hash and see if it has changed.
-	hash, err := fingerprint(f)
+	hash, err := fingerprint(f, c.runDataPath(f.Root), c.followSymlinks)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error calculating function's fingerprint: %v\n", err)
 		return false
@@ -983,20 +3004,71 @@ func (c *Client) Built(path string) bool {
 
 // fingerprint returns a hash of the filenames and modification timestamps of
 // the files within a function's root.
-func fingerprint(f Function) (string, error) {
+// Symlinks always contribute their target path to the hash, such that
+// retargeting a link is detected.  When followSymlinks is enabled, targets
+// of links are additionally hashed (recursively for directories) such that
+// changes to the linked files themselves are also detected.  Cyclic links
+// are visited at most once.
+func fingerprint(f Function, runDataPath string, followSymlinks bool) (string, error) {
+	hash, _, err := fingerprintDetail(f, runDataPath, followSymlinks)
+	return hash, err
+}
+
+// fingerprintDetail is the implementation of fingerprint which additionally
+// returns the per-file modification times which contributed to the hash,
+// such that staleness can be explained as well as detected.  The hash is
+// unaffected by this bookkeeping.
+func fingerprintDetail(f Function, runDataPath string, followSymlinks bool) (hash string, files map[string]time.Time, err error) {
 	h := sha256.New()
-	err := filepath.Walk(f.Root, func(path string, info fs.FileInfo, err error) error {
+	files = make(map[string]time.Time)
+	visited := make(map[string]bool) // resolved link targets, guards cycles
+	var walk filepath.WalkFunc
+	walk = func(path string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		// Always ignore .func, .git (TODO: .funcignore)
-		if info.IsDir() && (info.Name() == RunDataDir || info.Name() == ".git") {
+		// Always ignore .func, .git and any relocated run data directory
+		// (TODO: .funcignore)
+		if info.IsDir() && (info.Name() == RunDataDir || info.Name() == ".git" || path == runDataPath) {
 			return filepath.SkipDir
 		}
-		fmt.Fprintf(h, "%v:%v:", path, info.ModTime().UnixNano())
+		if info.Mode()&fs.ModeSymlink == 0 {
+			fmt.Fprintf(h, "%v:%v:", path, info.ModTime().UnixNano())
+			files[path] = info.ModTime()
+			return nil
+		}
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%v:%v:", path, target)
+		files[path] = info.ModTime()
+		if !followSymlinks || visited[target] {
+			return nil
+		}
+		visited[target] = true
+		tinfo, err := os.Stat(target)
+		if err != nil {
+			return err
+		}
+		if tinfo.IsDir() {
+			return filepath.Walk(target, walk)
+		}
+		fmt.Fprintf(h, "%v:%v:", target, tinfo.ModTime().UnixNano())
+		files[target] = tinfo.ModTime()
 		return nil
-	})
-	return fmt.Sprintf("%x", h.Sum(nil)), err
+	}
+	err = filepath.Walk(f.Root, walk)
+	hash = fmt.Sprintf("%x", h.Sum(nil))
+	return
+}
+
+// Fingerprint returns the hash used by the client for staleness detection
+// (see Built), along with the modification times of each file which
+// contributed, keyed by path.  Useful for determining exactly which file
+// caused a function to be considered modified since its last build.
+func (f Function) Fingerprint() (hash string, files map[string]time.Time, err error) {
+	return fingerprintDetail(f, filepath.Join(f.Root, RunDataDir), false)
 }
 
 // DEFAULTS
@@ -1038,6 +3110,27 @@ func (n *noopRunner) Run(context.Context, Function) (job *Job, err error) {
 	return
 }
 
+// Rollbacker
+type noopRollbacker struct{ output io.Writer }
+
+func (n *noopRollbacker) Rollback(context.Context, string, string) error {
+	return errors.New("no rollbacker provided")
+}
+
+// Promoter
+type noopPromoter struct{ output io.Writer }
+
+func (n *noopPromoter) Promote(context.Context, string, string, string) error {
+	return errors.New("no promoter provided")
+}
+
+// Subscriber
+type noopSubscriber struct{ output io.Writer }
+
+func (n *noopSubscriber) Subscribe(context.Context, Function, Subscription) error {
+	return errors.New("no subscriber provided")
+}
+
 // Remover
 type noopRemover struct{ output io.Writer }
 
@@ -1046,7 +3139,9 @@ func (n *noopRemover) Remove(context.Context, string) error { return nil }
 // Lister
 type noopLister struct{ output io.Writer }
 
-func (n *noopLister) List(context.Context) ([]ListItem, error) { return []ListItem{}, nil }
+func (n *noopLister) List(context.Context, ListOptions) ([]ListItem, error) {
+	return []ListItem{}, nil
+}
 
 // Describer
 type noopDescriber struct{ output io.Writer }
@@ -1061,6 +3156,20 @@ type noopPipelinesProvider struct{}
 func (n *noopPipelinesProvider) Run(ctx context.Context, _ Function) error    { return nil }
 func (n *noopPipelinesProvider) Remove(ctx context.Context, _ Function) error { return nil }
 
+// BrokerResolver
+type noopBrokerResolver struct{}
+
+func (n *noopBrokerResolver) BrokerURL(context.Context, string) (string, error) {
+	return "", errors.New("no broker resolver provided")
+}
+
+// IngressResolver
+type noopIngressResolver struct{}
+
+func (n *noopIngressResolver) ExternalAddress(context.Context) (string, error) {
+	return "", errors.New("no ingress resolver provided")
+}
+
 // DNSProvider
 type noopDNSProvider struct{ output io.Writer }
 
@@ -66,7 +66,7 @@ func TestList(t *testing.T) {
 		fn.WithVerbose(verbose))
 
 	// Act
-	names, err := client.List(context.Background())
+	names, err := client.List(context.Background(), fn.ListOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -91,7 +91,7 @@ func TestNew(t *testing.T) {
 	defer del(t, client, "testnew")
 
 	// Assert
-	items, err := client.List(context.Background())
+	items, err := client.List(context.Background(), fn.ListOptions{})
 	names := []string{}
 	for _, item := range items {
 		names = append(names, item.Name)
@@ -116,7 +116,7 @@ func TestDeploy(t *testing.T) {
 	}
 	defer del(t, client, "deploy")
 
-	if err := client.Deploy(context.Background(), "."); err != nil {
+	if _, err := client.Deploy(context.Background(), "."); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -137,7 +137,7 @@ func TestRemove(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	names, err := client.List(context.Background())
+	names, err := client.List(context.Background(), fn.ListOptions{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -247,7 +247,7 @@ func waitFor(t *testing.T, c *fn.Client, name string) {
 	var pollInterval = 2 * time.Second
 
 	for { // ever (i.e. defer to global test timeout)
-		nn, err := c.List(context.Background())
+		nn, err := c.List(context.Background(), fn.ListOptions{})
 		if err != nil {
 			t.Fatal(err)
 		}
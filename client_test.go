@@ -12,14 +12,18 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	fn "knative.dev/kn-plugin-func"
 	"knative.dev/kn-plugin-func/builders"
 	"knative.dev/kn-plugin-func/mock"
@@ -81,6 +85,54 @@ func TestClient_InstantiationCreatesRepositoriesPath(t *testing.T) {
 	}
 }
 
+// TestClient_New_DefaultTemplateConfigurable ensures that the template used
+// when none is specified can be overridden on the client.
+func TestClient_New_DefaultTemplateConfigurable(t *testing.T) {
+	root := "testdata/example.com/testDefaultTemplateConfigurable"
+	defer Using(t, root)()
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDefaultTemplate("cloudevents"))
+
+	// Create without specifying a template
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The configured default, rather than the static default "http", should
+	// have been used, as evidenced by the resultant invocation format.
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Invocation.Format != "cloudevent" {
+		t.Fatalf("expected the 'cloudevents' template (invocation format 'cloudevent'), got format '%v'", f.Invocation.Format)
+	}
+}
+
+// TestClient_Validate ensures that validating a function returns all
+// problems found rather than only the first.
+func TestClient_Validate(t *testing.T) {
+	client := fn.New(fn.WithRegistry(TestRegistry))
+
+	// A fully valid function yields no errors
+	f := fn.Function{Name: "valid", Runtime: TestRuntime, Root: "."}
+	if errs := client.Validate(f); len(errs) != 0 {
+		t.Fatalf("valid function returned errors: %v", errs)
+	}
+
+	// An invalid name, unavailable runtime, relative health endpoint and
+	// nonpositive probe timing should all be reported together.
+	var invalidDelay int32 = -1
+	f = fn.Function{Name: "Invalid Name", Runtime: "nosuchruntime"}
+	f.Deploy.HealthEndpoints.Liveness = "health/liveness" // not absolute
+	f.Deploy.HealthEndpoints.InitialDelaySeconds = &invalidDelay
+	if errs := client.Validate(f); len(errs) != 4 {
+		t.Fatalf("expected 4 validation errors, got %v: %v", len(errs), errs)
+	}
+}
+
 // TestClient_New_RuntimeRequired ensures that the the runtime is an expected value.
 func TestClient_New_RuntimeRequired(t *testing.T) {
 	// Create a root for the new function
@@ -148,6 +200,89 @@ func TestClient_New_WritesTemplate(t *testing.T) {
 	}
 }
 
+// TestClient_BuilderImages ensures that the default builder images
+// registered per builder implementation are reported for a given runtime,
+// and that unknown runtimes error.
+func TestClient_BuilderImages(t *testing.T) {
+	client := fn.New(
+		fn.WithBuilderImageDefaults("pack", map[string]string{"go": "example.com/pack/go-builder"}),
+		fn.WithBuilderImageDefaults("s2i", map[string]string{"go": "example.com/s2i/go-builder"}))
+
+	images, err := client.BuilderImages("go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if images["pack"] != "example.com/pack/go-builder" {
+		t.Fatalf("unexpected pack builder image '%v'", images["pack"])
+	}
+	if images["s2i"] != "example.com/s2i/go-builder" {
+		t.Fatalf("unexpected s2i builder image '%v'", images["s2i"])
+	}
+
+	if _, err = client.BuilderImages("cobol"); err == nil {
+		t.Fatal("expected error for a runtime unknown to all builders")
+	}
+}
+
+// TestClient_Clone ensures that cloning an existing function copies its
+// source tree to the destination (excluding local runtime data), renames it,
+// and clears state specific to the source (image, digest, created stamp).
+func TestClient_Clone(t *testing.T) {
+	root := "testdata/example.com/testClone"
+	defer Using(t, root)()
+	dest := "testdata/example.com/test-clone-dest"
+	defer Using(t, dest)()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate the source having been built by stamping an image
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Image = "example.com/alice/testClone:latest"
+	if err := f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Clone(root, dest, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := fn.NewFunction(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Name != "test-clone-dest" { // name defaults to the dest directory
+		t.Fatalf("expected clone name 'test-clone-dest', got '%v'", clone.Name)
+	}
+	if clone.Image != "" || clone.ImageDigest != "" {
+		t.Fatal("expected the clone's image and digest to be cleared")
+	}
+	if clone.Created.IsZero() {
+		t.Fatal("expected the clone to have a fresh created stamp")
+	}
+
+	// Assert a file from the source was copied
+	if _, err := os.Stat(filepath.Join(dest, "README.md")); os.IsNotExist(err) {
+		t.Fatal("source files were not copied to the clone")
+	}
+
+	// Local runtime data should not have been copied
+	if _, err := os.Stat(filepath.Join(dest, fn.RunDataDir)); !os.IsNotExist(err) {
+		t.Fatalf("'%v' should not be copied to the clone", fn.RunDataDir)
+	}
+
+	// Cloning into a non-empty destination should abort
+	if err := client.Clone(root, dest, ""); err == nil {
+		t.Fatal("error expected cloning into a nonempty directory")
+	}
+}
+
 // TestClient_New_ExtantAborts ensures that a directory which contains an extant
 // function does not reinitialize.
 func TestClient_New_ExtantAborts(t *testing.T) {
@@ -650,7 +785,7 @@ func TestClient_Update(t *testing.T) {
 
 	// Invoke the creation, triggering the function delegates, and
 	// perform follow-up assertions that the functions were indeed invoked.
-	if err := client.Deploy(context.Background(), root); err != nil {
+	if _, err := client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
 
@@ -672,7 +807,7 @@ func TestClient_Update(t *testing.T) {
 
 	// Invoke the update, triggering the function delegates, and
 	// perform follow-up assertions that the functions were indeed invoked during the update.
-	if err := client.Deploy(context.Background(), root); err != nil {
+	if _, err := client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
 
@@ -687,6 +822,40 @@ func TestClient_Update(t *testing.T) {
 	}
 }
 
+// TestClient_Deploy_ReportExternalAddress ensures that, when enabled, a
+// successful deploy additionally resolves the external address assigned to
+// the cluster ingress via the configured resolver.
+func TestClient_Deploy_ReportExternalAddress(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	resolver := mock.NewIngressResolver()
+	resolver.ExternalAddressFn = func(context.Context) (string, error) {
+		return "203.0.113.10", nil
+	}
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithIngressResolver(resolver),
+		fn.WithReportExternalAddress(true))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if !resolver.ExternalAddressInvoked {
+		t.Fatal("ingress resolver not invoked on deploy")
+	}
+
+	// The resolved address is also directly accessible for callers which
+	// need it programmatically (e.g. for configuring DNS).
+	address, err := client.ExternalAddress(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != "203.0.113.10" {
+		t.Fatalf("expected external address '203.0.113.10', got '%v'", address)
+	}
+}
+
 // TestClient_Deploy_RegistryUpdate ensures that deploying a Function updates
 // its image member on initial deploy, and on subsequent deploys only
 // if reset to it zero value.
@@ -718,7 +887,7 @@ func TestClient_Deploy_RegistryUpdate(t *testing.T) {
 	if err := client.Build(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
-	if err := client.Deploy(context.Background(), root); err != nil {
+	if _, err := client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
 	expected := "example.com/alice/f:latest"
@@ -739,7 +908,7 @@ func TestClient_Deploy_RegistryUpdate(t *testing.T) {
 	if err := client.Build(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
-	if err := client.Deploy(context.Background(), root); err != nil {
+	if _, err := client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
 	expected = "example.com/bob/f:latest"
@@ -951,7 +1120,7 @@ func TestClient_List(t *testing.T) {
 
 	client := fn.New(fn.WithLister(lister)) // lists deployed functions.
 
-	if _, err := client.List(context.Background()); err != nil {
+	if _, err := client.List(context.Background(), fn.ListOptions{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -970,7 +1139,7 @@ func TestClient_List_OutsideRoot(t *testing.T) {
 	// Instantiate in the current working directory, with no name.
 	client := fn.New(fn.WithLister(lister))
 
-	if _, err := client.List(context.Background()); err != nil {
+	if _, err := client.List(context.Background(), fn.ListOptions{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -1012,7 +1181,7 @@ func TestClient_Deploy_Image(t *testing.T) {
 	if err = client.Build(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
-	if err = client.Deploy(context.Background(), root); err != nil {
+	if _, err = client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
 	f, err = fn.NewFunction(root)
@@ -1036,7 +1205,7 @@ func TestClient_Deploy_Image(t *testing.T) {
 	if err = client.Build(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
-	if err = client.Deploy(context.Background(), root); err != nil {
+	if _, err = client.Deploy(context.Background(), root); err != nil {
 		t.Fatal(err)
 	}
 	expected = "registry2.example.com/bob/myfunc:latest"
@@ -1148,7 +1317,7 @@ func TestClient_Deploy_UnbuiltErrors(t *testing.T) {
 	}
 
 	// Now try to deploy it.  Ie. without having run the necessary build step.
-	err := client.Deploy(context.Background(), root)
+	_, err := client.Deploy(context.Background(), root)
 	if err == nil {
 		t.Fatal("did not receive an error attempting to deploy an unbuilt function")
 	}
@@ -1413,6 +1582,74 @@ func TestClient_Invoke_HTTP(t *testing.T) {
 // default CloudEvent function.  This also uses the HTTP protocol but asserts
 // the invoker is sending the invocation message as a CloudEvent rather than
 // a standard HTTP form POST.
+// TestClient_Invoke_Broker ensures that invoking with a "broker:<name>"
+// target resolves the named broker via the configured resolver and publishes
+// the CloudEvent to the broker's ingress rather than to the function.
+func TestClient_Invoke_Broker(t *testing.T) {
+	root := "testdata/example.com/testInvokeBroker"
+	defer Using(t, root)()
+
+	var (
+		invoked bool // flag the broker received the event
+		ctx     = context.Background()
+		message = fn.NewInvokeMessage()
+	)
+
+	// A CloudEvent receiver masquerading as the broker's ingress.
+	receiver := func(ctx context.Context, event cloudevents.Event) {
+		invoked = true
+		if event.ID() != message.ID {
+			t.Fatalf("expected event ID '%v', got '%v'", message.ID, event.ID())
+		}
+	}
+	protocol, err := cloudevents.NewHTTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := cloudevents.NewHTTPReceiveHandler(ctx, protocol, receiver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp4", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := http.Server{Handler: handler}
+	go func() {
+		if err := s.Serve(l); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "error serving: %v", err)
+		}
+	}()
+	defer s.Close()
+
+	// A resolver which reports the above receiver as the broker's ingress.
+	resolver := mock.NewBrokerResolver()
+	resolver.BrokerURLFn = func(_ context.Context, name string) (string, error) {
+		if name != "default" {
+			t.Fatalf("expected broker name 'default', got '%v'", name)
+		}
+		return "http://" + l.Addr().String(), nil
+	}
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithBrokerResolver(resolver))
+
+	// Create a new default function
+	f := fn.Function{Runtime: TestRuntime, Root: root}
+	if err := client.New(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invoke targeting the broker
+	if _, _, err := client.Invoke(context.Background(), root, "broker:default", message); err != nil {
+		t.Fatal(err)
+	}
+	if !resolver.BrokerURLInvoked {
+		t.Fatal("broker resolver was not invoked")
+	}
+	if !invoked {
+		t.Fatal("event was not published to the broker's ingress")
+	}
+}
+
 func TestClient_Invoke_CloudEvent(t *testing.T) {
 	root := "testdata/example.com/testInvokeCloudEvent"
 	defer Using(t, root)()
@@ -1581,6 +1818,50 @@ func TestClient_BuiltStamps(t *testing.T) {
 	}
 }
 
+// TestClient_BuiltStamps_FollowSymlinks ensures that, when following symlinks
+// is enabled, changes to the target of a symlink within the function's root
+// invalidate the buildstamp such that the function is reported as needing a
+// rebuild.
+func TestClient_BuiltStamps_FollowSymlinks(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+	builder := mock.NewBuilder()
+	client := fn.New(
+		fn.WithBuilder(builder),
+		fn.WithRegistry(TestRegistry),
+		fn.WithFollowSymlinks(true))
+
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a file outside the function's root, linked from within
+	target := filepath.Join(t.TempDir(), "target.txt")
+	if err := os.WriteFile(target, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(root, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	// a function which was successfully built should return as being Built
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !client.Built(root) {
+		t.Fatal("freshly built function should return Built==true")
+	}
+
+	// a change to the linked target should be detected as a change to the
+	// function, invalidating the buildstamp
+	if err := os.WriteFile(target, []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if client.Built(root) {
+		t.Fatal("modified symlink target not detected when following symlinks")
+	}
+}
+
 // TestClient_CreateMigration ensures that the client includes the most recent
 // migration version when creating a new function
 func TestClient_CreateMigration(t *testing.T) {
@@ -1683,3 +1964,1822 @@ func TestClient_BuiltDetects(t *testing.T) {
 		t.Fatal("client did not detect a removed file as indicating build staleness")
 	}
 }
+
+// TestClient_Push_RetriesTransient ensures that transient registry errors
+// (such as HTTP 503) are retried with backoff up to the configured count,
+// while the push ultimately succeeds once the registry recovers.
+func TestClient_Push_RetriesTransient(t *testing.T) {
+	root := "testdata/example.com/testPushRetries"
+	defer Using(t, root)()
+
+	pusher := mock.NewPusher()
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithPusher(pusher),
+		fn.WithPushRetries(3, time.Millisecond))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts int
+	pusher.PushFn = func(fn.Function) (string, error) {
+		attempts++
+		if attempts <= 2 { // registry unavailable for the first two attempts
+			return "", &transport.Error{StatusCode: http.StatusServiceUnavailable}
+		}
+		return "", nil
+	}
+	if err := client.Push(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected push to be attempted 3 times, got %v", attempts)
+	}
+
+	// Non-retryable errors (e.g. failed auth) should abort immediately.
+	attempts = 0
+	pusher.PushFn = func(fn.Function) (string, error) {
+		attempts++
+		return "", &transport.Error{StatusCode: http.StatusUnauthorized}
+	}
+	if err := client.Push(context.Background(), root); err == nil {
+		t.Fatal("expected auth error from push")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a single push attempt on auth failure, got %v", attempts)
+	}
+}
+
+// TestClient_Remove_Backup ensures that, when configured with a backup
+// directory, a backup of the function is written before the remover is
+// invoked, and that a backup failure aborts the removal.
+func TestClient_Remove_Backup(t *testing.T) {
+	var (
+		root      = "testdata/example.com/testRemoveBackup"
+		backupDir = filepath.Join(t.TempDir(), "backups")
+		describer = mock.NewDescriber()
+		remover   = mock.NewRemover()
+	)
+
+	defer Using(t, root)()
+
+	remover.RemoveFn = func(string) error {
+		// The backup must exist by the time the remover runs.
+		ff, err := os.ReadDir(backupDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ff) != 1 {
+			t.Fatalf("expected 1 backup file before removal, got %v", len(ff))
+		}
+		return nil
+	}
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDescriber(describer),
+		fn.WithRemover(remover),
+		fn.WithRemoveBackup(backupDir))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Remove(context.Background(), fn.Function{Root: root}, false); err != nil {
+		t.Fatal(err)
+	}
+	if !remover.RemoveInvoked {
+		t.Fatal("remover was not invoked")
+	}
+
+	// A failing backup (describer error) should abort the removal.
+	describer.DescribeFn = func(string) (fn.Instance, error) {
+		return fn.Instance{}, errors.New("cluster unreachable")
+	}
+	remover.RemoveInvoked = false
+	if err := client.Remove(context.Background(), fn.Function{Root: root}, false); err == nil {
+		t.Fatal("expected error when backup fails")
+	}
+	if remover.RemoveInvoked {
+		t.Fatal("remover should not be invoked when backup fails")
+	}
+}
+
+// TestClient_Deploy_RevisionHistoryLimit ensures that, when a revision
+// history limit is configured, the deployer is asked to prune revisions
+// with that limit following a successful deploy, and not otherwise.
+func TestClient_Deploy_RevisionHistoryLimit(t *testing.T) {
+	root := "testdata/example.com/testDeployRevisionHistoryLimit"
+	defer Using(t, root)()
+
+	deployer := mock.NewDeployer()
+	deployer.PruneRevisionsFn = func(f fn.Function, keep int) error {
+		if keep != 3 {
+			t.Fatalf("expected history limit 3 to reach the deployer, got %v", keep)
+		}
+		return nil
+	}
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(deployer),
+		fn.WithRevisionHistoryLimit(3))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.PruneRevisionsInvoked {
+		t.Fatal("deployer was not asked to prune revisions")
+	}
+
+	// Without the option, no pruning is requested.
+	deployer.PruneRevisionsInvoked = false
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(deployer))
+	if _, err := client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if deployer.PruneRevisionsInvoked {
+		t.Fatal("revisions pruned without a configured history limit")
+	}
+}
+
+// TestClient_Build_Timeout ensures that builds exceeding the configured
+// timeout are canceled and reported as ErrBuildTimeout, while builds
+// completing within the timeout succeed as usual.
+func TestClient_Build_Timeout(t *testing.T) {
+	root := "testdata/example.com/testBuildTimeout"
+	defer Using(t, root)()
+
+	builder := mock.NewBuilder()
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(builder),
+		fn.WithBuildTimeout(10*time.Millisecond))
+
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A builder which blocks beyond the timeout, returning the context's
+	// error as a real builder would when canceled.
+	builder.BuildFn = func(fn.Function) error {
+		time.Sleep(50 * time.Millisecond)
+		return context.DeadlineExceeded
+	}
+	if err := client.Build(context.Background(), root); !errors.Is(err, fn.ErrBuildTimeout) {
+		t.Fatalf("expected ErrBuildTimeout, got %v", err)
+	}
+
+	// A build completing within the timeout is unaffected.
+	builder.BuildFn = func(fn.Function) error { return nil }
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestClient_Instances_List ensures that listing instances returns a map
+// keyed by environment containing only the environments in which the
+// function currently has an instance, and an empty map (not an error)
+// when it has none.
+func TestClient_Instances_List(t *testing.T) {
+	root := "testdata/example.com/testInstancesList"
+	defer Using(t, root)()
+
+	runner := mock.NewRunner()
+	runner.RunFn = func(_ context.Context, f fn.Function) (*fn.Job, error) {
+		errs := make(chan error, 10)
+		stop := func() {}
+		return fn.NewJob(f, "8080", errs, stop)
+	}
+	describer := mock.NewDescriber()
+	describer.DescribeFn = func(string) (fn.Instance, error) {
+		return fn.Instance{}, errors.New("not deployed")
+	}
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithRunner(runner),
+		fn.WithDescriber(describer))
+
+	if err := client.Create(fn.Function{Root: root, Runtime: TestRuntime}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Neither running locally nor deployed: an empty map, not an error.
+	instances, err := client.Instances().List(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 0 {
+		t.Fatalf("expected no instances, got %v", instances)
+	}
+
+	// Run the function locally and make the remote describable.
+	job, err := client.Run(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer job.Stop()
+	describer.DescribeFn = func(name string) (fn.Instance, error) {
+		return fn.Instance{Name: name}, nil
+	}
+
+	if instances, err = client.Instances().List(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := instances["local"]; !ok {
+		t.Fatal("local instance not listed for a locally running function")
+	}
+	if _, ok := instances["remote"]; !ok {
+		t.Fatal("remote instance not listed for a deployed function")
+	}
+}
+
+// TestClient_New_Events ensures that the complete lifecycle triggered by
+// New emits a structured started/succeeded event pair for each phase in
+// order, and that failures are reported with a failed event.
+func TestClient_New_Events(t *testing.T) {
+	root := "testdata/example.com/testNewEvents"
+	defer Using(t, root)()
+
+	var events []fn.Event
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithEventListener(func(e fn.Event) { events = append(events, e) }))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []fn.Event{
+		{Phase: fn.PhaseCreate, Status: fn.EventStarted},
+		{Phase: fn.PhaseCreate, Status: fn.EventSucceeded},
+		{Phase: fn.PhaseBuild, Status: fn.EventStarted},
+		{Phase: fn.PhaseBuild, Status: fn.EventSucceeded},
+		{Phase: fn.PhasePush, Status: fn.EventStarted},
+		{Phase: fn.PhasePush, Status: fn.EventSucceeded},
+		{Phase: fn.PhaseDeploy, Status: fn.EventStarted},
+		{Phase: fn.PhaseDeploy, Status: fn.EventSucceeded},
+		{Phase: fn.PhaseRoute, Status: fn.EventStarted},
+		{Phase: fn.PhaseRoute, Status: fn.EventSucceeded},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Fatalf("unexpected event sequence.\nexpected: %v\ngot:      %v", expected, events)
+	}
+
+	// A failing phase emits a failed event carrying the error detail.
+	events = nil
+	builder := mock.NewBuilder()
+	builder.BuildFn = func(fn.Function) error { return errors.New("no compiler") }
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(builder),
+		fn.WithEventListener(func(e fn.Event) { events = append(events, e) }))
+	if err := client.Build(context.Background(), root); err == nil {
+		t.Fatal("expected build error")
+	}
+	last := events[len(events)-1]
+	if last.Phase != fn.PhaseBuild || last.Status != fn.EventFailed || last.Detail != "no compiler" {
+		t.Fatalf("unexpected failure event: %v", last)
+	}
+}
+
+// TestClient_Push_AdditionalRegistries ensures that, after the primary push
+// succeeds, the image is additionally pushed retagged for each configured
+// additional registry, and that secondary failures are fatal only when
+// additional registries are required.
+func TestClient_Push_AdditionalRegistries(t *testing.T) {
+	root := "testdata/example.com/testPushAdditionalRegistries"
+	defer Using(t, root)()
+
+	var pushed []string
+	pusher := mock.NewPusher()
+	pusher.PushFn = func(f fn.Function) (string, error) {
+		pushed = append(pushed, f.Image)
+		return "", nil
+	}
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithPusher(pusher),
+		fn.WithAdditionalRegistries([]string{"mirror.example.com/team"}))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"example.com/alice/testPushAdditionalRegistries:latest",
+		"mirror.example.com/team/testPushAdditionalRegistries:latest",
+	}
+	if !reflect.DeepEqual(pushed, expected) {
+		t.Fatalf("unexpected pushes.\nexpected: %v\ngot:      %v", expected, pushed)
+	}
+
+	// A failing mirror push is non-fatal by default...
+	pusher.PushFn = func(f fn.Function) (string, error) {
+		if strings.HasPrefix(f.Image, "mirror.") {
+			return "", errors.New("mirror unavailable")
+		}
+		return "", nil
+	}
+	if err := client.Push(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+
+	// ...but fatal when additional registries are required.
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithPusher(pusher),
+		fn.WithAdditionalRegistries([]string{"mirror.example.com/team"}),
+		fn.WithAdditionalRegistriesRequired(true))
+	if err := client.Push(context.Background(), root); err == nil {
+		t.Fatal("expected error pushing to required additional registry")
+	}
+}
+
+// TestClient_Rollback ensures that rolling back a function delegates to the
+// client's rollbacker with the expected name and revision, and that a name
+// is required.
+func TestClient_Rollback(t *testing.T) {
+	rollbacker := mock.NewRollbacker()
+	rollbacker.RollbackFn = func(_ context.Context, name, revision string) error {
+		if name != "testRollback" {
+			t.Fatalf("unexpected name passed to rollbacker: %v", name)
+		}
+		if revision != "testRollback-00001" {
+			t.Fatalf("unexpected revision passed to rollbacker: %v", revision)
+		}
+		return nil
+	}
+	client := fn.New(fn.WithRollbacker(rollbacker))
+
+	if err := client.Rollback(context.Background(), "testRollback", "testRollback-00001"); err != nil {
+		t.Fatal(err)
+	}
+	if !rollbacker.RollbackInvoked {
+		t.Fatal("rollbacker not invoked")
+	}
+
+	// A name is required.
+	if err := client.Rollback(context.Background(), "", ""); err == nil {
+		t.Fatal("expected error rolling back without a name")
+	}
+}
+
+// TestClient_Deploy_Wait ensures that, when a deploy wait is configured,
+// Deploy polls the function's readiness endpoint until it returns 200, and
+// that on timeout a descriptive error including the last observed status
+// is returned.
+func TestClient_Deploy_Wait(t *testing.T) {
+	root := "testdata/example.com/testDeployWait"
+	defer Using(t, root)()
+
+	// A server which becomes ready on its third readiness check.
+	var checks int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health/readiness" {
+			t.Fatalf("unexpected path polled: %v", r.URL.Path)
+		}
+		if atomic.AddInt32(&checks, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer s.Close()
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(mock.NewDeployerWithResult(&fn.DeploymentResult{
+			Status: fn.Deployed, URL: s.URL})),
+		fn.WithDeployWait(10*time.Second))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&checks) < 3 {
+		t.Fatalf("expected at least 3 readiness checks, got %v", checks)
+	}
+
+	// A function which never becomes ready yields an error including the
+	// last status observed.
+	never := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer never.Close()
+
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(mock.NewDeployerWithResult(&fn.DeploymentResult{
+			Status: fn.Deployed, URL: never.URL})),
+		fn.WithDeployWait(2*time.Second))
+
+	_, err := client.Deploy(context.Background(), root)
+	if err == nil {
+		t.Fatal("expected error deploying a function which never becomes ready")
+	}
+	if !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected error to include the last status observed, got: %v", err)
+	}
+}
+
+// TestClient_Invoke_ContentMode ensures that CloudEvent invocations honor
+// the message's content mode:  binary (attributes as headers) by default,
+// or structured (JSON envelope) on request, with the mode used reflected
+// in the returned metadata.
+func TestClient_Invoke_ContentMode(t *testing.T) {
+	root := "testdata/example.com/testInvokeContentMode"
+	defer Using(t, root)()
+
+	// A server which records the content type and ce headers received.
+	var contentType, ceID string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		ceID = r.Header.Get("Ce-Id")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer s.Close()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Binary (the default): attributes are sent as ce- headers.
+	m := fn.NewInvokeMessage()
+	m.Format = "cloudevent"
+	metadata, _, err := client.Invoke(context.Background(), root, s.URL, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ceID != m.ID {
+		t.Fatalf("expected binary mode to send attributes as headers, got ce-id %q", ceID)
+	}
+	if len(metadata["Content-Mode"]) == 0 || metadata["Content-Mode"][0] != fn.InvokeContentModeBinary {
+		t.Fatalf("expected metadata to reflect binary content mode, got %v", metadata["Content-Mode"])
+	}
+
+	// Structured: the event is sent as a JSON envelope.
+	m.ContentMode = fn.InvokeContentModeStructured
+	metadata, _, err = client.Invoke(context.Background(), root, s.URL, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(contentType, "application/cloudevents+json") {
+		t.Fatalf("expected structured mode to send a JSON envelope, got content type %q", contentType)
+	}
+	if len(metadata["Content-Mode"]) == 0 || metadata["Content-Mode"][0] != fn.InvokeContentModeStructured {
+		t.Fatalf("expected metadata to reflect structured content mode, got %v", metadata["Content-Mode"])
+	}
+
+	// An unsupported mode errors.
+	m.ContentMode = "invalid"
+	if _, _, err = client.Invoke(context.Background(), root, s.URL, m); err == nil {
+		t.Fatal("expected error for unsupported content mode")
+	}
+}
+
+// TestClient_SetInvocation ensures that a function's invocation format can
+// be updated after creation, that the change is persisted, and that formats
+// unsupported by the runtime's templates are rejected.
+func TestClient_SetInvocation(t *testing.T) {
+	root := "testdata/example.com/testSetInvocation"
+	defer Using(t, root)()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+
+	// Create a function from the default (http) template
+	if err := client.New(context.Background(), fn.Function{Root: root, Runtime: TestRuntime}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Update to the cloudevent format, supported by the runtime's
+	// 'cloudevents' template
+	if err := client.SetInvocation(root, "cloudevent"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Invocation.Format != "cloudevent" {
+		t.Fatalf("expected invocation format 'cloudevent', got '%v'", f.Invocation.Format)
+	}
+
+	// An unsupported format is rejected and the function left unchanged
+	if err := client.SetInvocation(root, "grpc"); err == nil {
+		t.Fatal("expected error setting an unsupported invocation format, got nil")
+	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Invocation.Format != "cloudevent" {
+		t.Fatalf("function should be unchanged after rejected format, got '%v'", f.Invocation.Format)
+	}
+}
+
+// TestClient_BuildAll ensures that multiple function roots can be built
+// concurrently with a shared client, returning a per-root error map.
+func TestClient_BuildAll(t *testing.T) {
+	roots := []string{
+		"testdata/example.com/testBuildAllA",
+		"testdata/example.com/testBuildAllB",
+	}
+	for _, root := range roots {
+		defer Using(t, root)()
+	}
+
+	var (
+		builds  int32
+		builder = mock.NewBuilder()
+	)
+	builder.BuildFn = func(f fn.Function) error {
+		atomic.AddInt32(&builds, 1)
+		return nil
+	}
+
+	client := fn.New(fn.WithBuilder(builder), fn.WithRegistry(TestRegistry))
+
+	for _, root := range roots {
+		if err := client.New(context.Background(), fn.Function{Root: root, Runtime: TestRuntime}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Creation via New builds as a side-effect; count only those following.
+	atomic.StoreInt32(&builds, 0)
+
+	results := client.BuildAll(context.Background(), roots, 2)
+
+	if len(results) != len(roots) {
+		t.Fatalf("expected %v results, got %v", len(roots), len(results))
+	}
+	for root, err := range results {
+		if err != nil {
+			t.Errorf("build of '%v' failed: %v", root, err)
+		}
+	}
+	if atomic.LoadInt32(&builds) != int32(len(roots)) {
+		t.Fatalf("expected %v builds, got %v", len(roots), builds)
+	}
+}
+
+// TestClient_Invoke_HTTPCustomRequest ensures that the invocation message's
+// method, headers and query parameters are applied to HTTP invocations, and
+// that the response status code is included in the returned metadata.
+func TestClient_Invoke_HTTPCustomRequest(t *testing.T) {
+	root := "testdata/example.com/testInvokeHTTPCustomRequest"
+	defer Using(t, root)()
+
+	message := fn.NewInvokeMessage()
+	message.Method = "PUT"
+	message.Headers = map[string]string{"X-Test-Header": "testValue"}
+	message.QueryParams = map[string]string{"key": "value"}
+
+	// An HTTP handler which masquerades as a running function and verifies
+	// the custom request attributes arrived.
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		if req.Method != "PUT" {
+			t.Errorf("expected 'PUT' request, got %q", req.Method)
+		}
+		if req.Header.Get("X-Test-Header") != "testValue" {
+			t.Errorf("expected header 'X-Test-Header: testValue', got %q", req.Header.Get("X-Test-Header"))
+		}
+		if req.URL.Query().Get("key") != "value" {
+			t.Errorf("expected query param 'key=value', got %q", req.URL.RawQuery)
+		}
+		res.WriteHeader(http.StatusAccepted)
+	})
+
+	l, err := net.Listen("tcp4", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := http.Server{Handler: handler}
+	go func() {
+		if err = s.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "error serving: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	runner := mock.NewRunner()
+	runner.RunFn = func(ctx context.Context, f fn.Function) (*fn.Job, error) {
+		_, p, _ := net.SplitHostPort(l.Addr().String())
+		errs := make(chan error, 10)
+		stop := func() {}
+		return fn.NewJob(f, p, errs, stop)
+	}
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithRunner(runner))
+
+	f := fn.Function{Runtime: TestRuntime, Root: root, Template: "http"}
+	if err := client.New(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := client.Run(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(job.Stop)
+
+	h, _, err := client.Invoke(context.Background(), f.Root, "", message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The metadata includes the response status code
+	if code, ok := h["Status-Code"]; !ok || len(code) != 1 || code[0] != "202" {
+		t.Fatalf("expected metadata Status-Code '202', got %v", h["Status-Code"])
+	}
+}
+
+// TestClient_Fingerprint ensures that the public Fingerprint method returns
+// the same hash as the client's staleness detection along with a per-file
+// modtime breakdown, and that a contributing-file list is stored alongside
+// the buildstamp.
+func TestClient_Fingerprint(t *testing.T) {
+	root := "testdata/example.com/testFingerprint"
+	defer Using(t, root)()
+
+	client := fn.New(fn.WithBuilder(mock.NewBuilder()), fn.WithRegistry(TestRegistry))
+	if err := client.New(context.Background(), fn.Function{Root: root, Runtime: TestRuntime}); err != nil {
+		t.Fatal(err)
+	}
+	// Rebuild such that the buildstamp reflects the final state of the just
+	// created function (creation itself writes func.yaml after building).
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, files, err := f.Fingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == "" {
+		t.Fatal("expected a nonempty fingerprint hash")
+	}
+	if len(files) == 0 {
+		t.Fatal("expected per-file breakdown to be populated")
+	}
+
+	// The hash matches that stored by the build (formats unchanged), since
+	// no files have been modified since.
+	stamp, err := os.ReadFile(filepath.Join(root, fn.RunDataDir, "built"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stamp) != hash {
+		t.Errorf("expected fingerprint '%v' to match stored buildstamp '%v'", hash, string(stamp))
+	}
+
+	// The contributing file list is stored alongside the stamp
+	list, err := os.ReadFile(filepath.Join(root, fn.RunDataDir, "built.files"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(list), "func.yaml") {
+		t.Errorf("expected stored file list to include func.yaml, got:\n%v", string(list))
+	}
+}
+
+// TestClient_Deploy_ImageOverride ensures that an externally built image
+// provided via WithImage overrides the function's built image on deploy
+// (including a pinned digest, satisfying the built check without a build),
+// and that malformed references are rejected.
+func TestClient_Deploy_ImageOverride(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	var (
+		image    = "example.com/alice/myfunc"
+		digest   = "sha256:" + strings.Repeat("a", 64)
+		deployer = mock.NewDeployer()
+		client   = fn.New(
+			fn.WithRegistry(TestRegistry),
+			fn.WithDeployer(deployer),
+			fn.WithImage(image+"@"+digest))
+	)
+
+	// Create the function on disk without building; the override alone
+	// should satisfy the deployer's requirement for an image.
+	if err := fn.New().Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	deployer.DeployFn = func(f fn.Function) error {
+		if f.Image != image {
+			t.Fatalf("deployer expected image '%v', got '%v'", image, f.Image)
+		}
+		if f.ImageDigest != digest {
+			t.Fatalf("deployer expected digest '%v', got '%v'", digest, f.ImageDigest)
+		}
+		return nil
+	}
+
+	if _, err := client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.DeployInvoked {
+		t.Fatal("deployer was not invoked")
+	}
+
+	// A malformed reference (truncated digest) should error before the
+	// deployer is consulted.
+	deployer = mock.NewDeployer()
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(deployer),
+		fn.WithImage(image+"@sha256:abc"))
+	if _, err := client.Deploy(context.Background(), root); err == nil {
+		t.Fatal("expected malformed image reference to error")
+	}
+	if deployer.DeployInvoked {
+		t.Fatal("deployer should not be invoked for a malformed image reference")
+	}
+}
+
+// TestClient_New_RollbackOnFailure ensures that when enabled, a failure
+// partway through New undoes the steps which had completed: files created
+// on disk are removed and, if the function had been deployed, it is
+// removed from the cluster.  The original error is still reported.
+func TestClient_New_RollbackOnFailure(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	// A pusher which fails after create and build have succeeded.
+	pusher := mock.NewPusher()
+	pusher.PushFn = func(fn.Function) (string, error) {
+		return "", errors.New("push failed")
+	}
+	remover := mock.NewRemover()
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithPusher(pusher),
+		fn.WithRemover(remover),
+		fn.WithRollbackOnFailure(true))
+
+	err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root})
+	if err == nil {
+		t.Fatal("expected the push error to be returned")
+	}
+	if !strings.Contains(err.Error(), "push failed") {
+		t.Fatalf("expected the original error to be retained, got: %v", err)
+	}
+
+	// The function was never deployed, so the remover should not be consulted.
+	if remover.RemoveInvoked {
+		t.Fatal("remover should not be invoked when the function was not deployed")
+	}
+
+	// Files created on disk should have been removed (the preexisting root
+	// directory itself is retained).
+	if _, err := os.Stat(filepath.Join(root, fn.FunctionFile)); !os.IsNotExist(err) {
+		t.Fatal("created files were not removed on rollback")
+	}
+}
+
+// TestClient_New_RollbackRemovesDeployed ensures that a function which was
+// deployed, but for which a subsequent step failed, is removed from the
+// cluster during rollback.
+func TestClient_New_RollbackRemovesDeployed(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	var removed string
+	remover := mock.NewRemover()
+	remover.RemoveFn = func(name string) error {
+		removed = name
+		return nil
+	}
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithRemover(remover),
+		fn.WithDNSProvider(failingDNSProvider{}),
+		fn.WithRollbackOnFailure(true))
+
+	err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root, Name: "testrollback"})
+	if err == nil {
+		t.Fatal("expected the route error to be returned")
+	}
+	if !remover.RemoveInvoked {
+		t.Fatal("remover was not invoked for the deployed function")
+	}
+	if removed != "testrollback" {
+		t.Fatalf("expected removal of 'testrollback', got '%v'", removed)
+	}
+}
+
+// failingDNSProvider implements fn.DNSProvider, erring on every request.
+type failingDNSProvider struct{}
+
+func (failingDNSProvider) Provide(fn.Function) error { return errors.New("route failed") }
+
+// TestClient_List_Options ensures that list options are passed through to
+// the underlying lister for server-side filtering.
+func TestClient_List_Options(t *testing.T) {
+	lister := mock.NewLister()
+	lister.ListFn = func(opts fn.ListOptions) ([]fn.ListItem, error) {
+		if opts.Namespace != "alice" {
+			t.Fatalf("expected namespace filter 'alice', got '%v'", opts.Namespace)
+		}
+		if opts.Runtime != "go" {
+			t.Fatalf("expected runtime filter 'go', got '%v'", opts.Runtime)
+		}
+		return []fn.ListItem{}, nil
+	}
+
+	client := fn.New(fn.WithLister(lister))
+
+	if _, err := client.List(context.Background(), fn.ListOptions{Namespace: "alice", Runtime: "go"}); err != nil {
+		t.Fatal(err)
+	}
+	if !lister.ListInvoked {
+		t.Fatal("list did not invoke lister implementation")
+	}
+}
+
+// TestClient_EffectiveConfig ensures that the effective configuration is
+// the fully-migrated function with notes explaining non-obvious values,
+// and that migrations are not written back to disk as a side effect.
+func TestClient_EffectiveConfig(t *testing.T) {
+	root := "testdata/migrations/v0.19.0"
+	before, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := fn.New()
+	f, notes, err := client.EffectiveConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The returned function is fully migrated
+	if f.SpecVersion != fn.LastSpecVersion() {
+		t.Errorf("expected spec version %v, got %v", fn.LastSpecVersion(), f.SpecVersion)
+	}
+
+	// Migrations applied in memory are among the notes
+	if len(notes) == 0 {
+		t.Error("expected notes explaining migrated and defaulted values")
+	}
+
+	// The file is not modified
+	after, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("effective config should not write migrations back to disk")
+	}
+}
+
+// TestClient_InvokeStream ensures a bidirectional connection can be opened
+// to a function which upgrades the connection, with bytes flowing both
+// directions, while the one-shot Invoke remains unaffected.
+func TestClient_InvokeStream(t *testing.T) {
+	root := "testdata/example.com/test-invoke-stream"
+	defer Using(t, root)()
+
+	// A handler which masquerades as a streaming function by upgrading the
+	// connection and echoing a single line back to the caller.
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Upgrade") != "websocket" {
+			t.Errorf("expected upgrade request, got headers %v", req.Header)
+			return
+		}
+		hj, ok := res.(http.Hijacker)
+		if !ok {
+			t.Error("server does not support hijacking")
+			return
+		}
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+		_ = rw.Flush()
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _ = rw.WriteString("echo: " + line)
+		_ = rw.Flush()
+	})
+
+	l, err := net.Listen("tcp4", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := http.Server{Handler: handler}
+	go func() {
+		if err = s.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "error serving: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	f := fn.Function{Runtime: TestRuntime, Root: root, Template: "http"}
+	if err := client.New(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	// Open a streaming connection targeting the server's URL directly.
+	conn, err := client.InvokeStream(context.Background(), root, "http://"+l.Addr().String(), fn.NewInvokeMessage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "echo: hello\n" {
+		t.Fatalf("expected 'echo: hello', got %q", reply)
+	}
+}
+
+// TestClient_Deploy_Result ensures that a deploy surfaces the deployer's
+// result, including the revision name assigned by the platform, such that
+// callers can implement canary or rollback tooling.
+func TestClient_Deploy_Result(t *testing.T) {
+	root := "testdata/example.com/test-deploy-result"
+	defer Using(t, root)()
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(mock.NewDeployerWithResult(&fn.DeploymentResult{
+			Status:       fn.Deployed,
+			URL:          "example.com",
+			Namespace:    "test-ns",
+			RevisionName: "test-deploy-result-00001",
+			Generation:   1,
+		})))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.Deploy(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RevisionName != "test-deploy-result-00001" {
+		t.Fatalf("expected revision name from the deployer, got '%v'", result.RevisionName)
+	}
+	if result.Generation != 1 {
+		t.Fatalf("expected generation from the deployer, got %v", result.Generation)
+	}
+}
+
+// TestClient_Promote ensures that promoting a function delegates to the
+// client's promoter with the expected name and namespaces, and that the
+// name and distinct namespaces are required.
+func TestClient_Promote(t *testing.T) {
+	promoter := mock.NewPromoter()
+	promoter.PromoteFn = func(_ context.Context, name, fromNamespace, toNamespace string) error {
+		if name != "testPromote" {
+			t.Fatalf("unexpected name passed to promoter: %v", name)
+		}
+		if fromNamespace != "staging" {
+			t.Fatalf("unexpected source namespace passed to promoter: %v", fromNamespace)
+		}
+		if toNamespace != "production" {
+			t.Fatalf("unexpected target namespace passed to promoter: %v", toNamespace)
+		}
+		return nil
+	}
+	client := fn.New(fn.WithPromoter(promoter))
+
+	if err := client.Promote(context.Background(), "testPromote", "staging", "production"); err != nil {
+		t.Fatal(err)
+	}
+	if !promoter.PromoteInvoked {
+		t.Fatal("promoter not invoked")
+	}
+
+	// A name is required.
+	if err := client.Promote(context.Background(), "", "staging", "production"); err == nil {
+		t.Fatal("expected error promoting without a name")
+	}
+	// Source and target namespaces must differ.
+	if err := client.Promote(context.Background(), "testPromote", "staging", "staging"); err == nil {
+		t.Fatal("expected error promoting within a single namespace")
+	}
+}
+
+// TestClient_Touch ensures that touching a built function marks it stale
+// such that Built reports false, and that touching an unbuilt function is
+// a harmless no-op.
+func TestClient_Touch(t *testing.T) {
+	root := "testdata/example.com/test-touch"
+	defer Using(t, root)()
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(mock.NewBuilder()))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Touching before any build has occurred is a no-op.
+	if err := client.Touch(root); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !client.Built(root) {
+		t.Fatal("function should report as built")
+	}
+
+	if err := client.Touch(root); err != nil {
+		t.Fatal(err)
+	}
+	if client.Built(root) {
+		t.Fatal("function should report as stale after being touched")
+	}
+}
+
+// TestClient_DeployStamp ensures that a successful deploy records the
+// resulting URL and namespace locally, that the record is updated on each
+// subsequent deploy, and that it is excluded from the staleness fingerprint.
+func TestClient_DeployStamp(t *testing.T) {
+	root := "testdata/example.com/test-deploy-stamp"
+	defer Using(t, root)()
+
+	deployer := mock.NewDeployerWithResult(&fn.DeploymentResult{
+		Status:    fn.Deployed,
+		URL:       "https://one.example.com",
+		Namespace: "test-ns",
+	})
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithDeployer(deployer))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+
+	stamp, err := client.DeployStamp(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stamp.URL != "https://one.example.com" {
+		t.Fatalf("expected recorded URL, got '%v'", stamp.URL)
+	}
+	if stamp.Namespace != "test-ns" {
+		t.Fatalf("expected recorded namespace, got '%v'", stamp.Namespace)
+	}
+	if stamp.Deployed.IsZero() {
+		t.Fatal("expected a deployment timestamp")
+	}
+
+	// Recording the deployment must not render the function stale.
+	if !client.Built(root) {
+		t.Fatal("function should still report as built after deploy")
+	}
+
+	// The record reflects the most recent deploy.
+	deployer.DeployResult.URL = "https://two.example.com"
+	if _, err := client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if stamp, err = client.DeployStamp(root); err != nil {
+		t.Fatal(err)
+	}
+	if stamp.URL != "https://two.example.com" {
+		t.Fatalf("expected updated URL, got '%v'", stamp.URL)
+	}
+}
+
+// TestClient_Subscribe ensures that subscribing a function delegates to the
+// subscriber, persists the subscription for reapplication on deploy, and is
+// idempotent with respect to identical subscriptions.
+func TestClient_Subscribe(t *testing.T) {
+	root := "testdata/example.com/test-subscribe"
+	defer Using(t, root)()
+
+	subscriber := mock.NewSubscriber()
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithDeployer(mock.NewDeployer()),
+		fn.WithSubscriber(subscriber))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := fn.Subscription{Type: "com.example.order"}
+	if err := client.Subscribe(context.Background(), root, sub); err != nil {
+		t.Fatal(err)
+	}
+	if !subscriber.SubscribeInvoked {
+		t.Fatal("subscriber not invoked")
+	}
+
+	// The subscription is persisted, defaulted to the default broker.
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Deploy.Subscriptions) != 1 {
+		t.Fatalf("expected 1 persisted subscription, got %v", len(f.Deploy.Subscriptions))
+	}
+	if f.Deploy.Subscriptions[0].Broker != fn.DefaultBroker {
+		t.Fatalf("expected broker defaulted to %q, got %q", fn.DefaultBroker, f.Deploy.Subscriptions[0].Broker)
+	}
+
+	// Subscribing again with the same values does not duplicate the record.
+	sub.Broker = fn.DefaultBroker
+	if err := client.Subscribe(context.Background(), root, sub); err != nil {
+		t.Fatal(err)
+	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Deploy.Subscriptions) != 1 {
+		t.Fatalf("expected 1 persisted subscription after resubscribe, got %v", len(f.Deploy.Subscriptions))
+	}
+
+	// An event type is required.
+	if err := client.Subscribe(context.Background(), root, fn.Subscription{}); err == nil {
+		t.Fatal("expected error subscribing without an event type")
+	}
+
+	// Recorded subscriptions are reapplied on deploy.
+	subscriber.SubscribeInvoked = false
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !subscriber.SubscribeInvoked {
+		t.Fatal("subscriber not invoked on deploy")
+	}
+}
+
+// TestClient_Diff ensures that the local configuration is compared against
+// the deployed instance as reported by the describer, and that a function
+// not yet deployed is reported as such rather than erroring.
+func TestClient_Diff(t *testing.T) {
+	root := "testdata/example.com/test-diff"
+	defer Using(t, root)()
+
+	describer := mock.NewDescriber()
+	describer.DescribeFn = func(string) (fn.Instance, error) {
+		return fn.Instance{
+			Image:  "example.com/alice/test-diff:latest",
+			Envs:   map[string]string{"A": "1", "B": "2"},
+			Labels: map[string]string{"team": "alpha"},
+		}, nil
+	}
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDescriber(describer))
+
+	if err := client.New(context.Background(), fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Locally: A changed, B removed, C added and a differing label value.
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, av, c, cv := "A", "changed", "C", "3"
+	f.Run.Envs = []fn.Env{{Name: &a, Value: &av}, {Name: &c, Value: &cv}}
+	lk, lv := "team", "beta"
+	f.Deploy.Labels = []fn.Label{{Key: &lk, Value: &lv}}
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := client.Diff(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`~ env A: "1" -> "changed"`,
+		`- env B`,
+		`+ env C="3"`,
+		`~ label team: "alpha" -> "beta"`,
+	} {
+		if !strings.Contains(diff, expected) {
+			t.Fatalf("expected diff to contain %q, got:\n%v", expected, diff)
+		}
+	}
+
+	// A function which is not deployed reports as such.
+	describer.DescribeFn = func(string) (fn.Instance, error) {
+		return fn.Instance{}, errors.New("service not found")
+	}
+	if diff, err = client.Diff(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(diff, "not deployed") {
+		t.Fatalf("expected not-deployed report, got: %v", diff)
+	}
+}
+
+// TestClient_CreatePostCreateHook ensures that a post-create hook declared
+// in a template's manifest is run in the new project directory when enabled
+// via WithRunPostCreate, skipped by default, and that a non-zero exit is
+// reported as an error.
+func TestClient_CreatePostCreateHook(t *testing.T) {
+	root := "testdata/example.com/testCreatePostCreateHook"
+	defer Using(t, root)()
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithRepositoriesPath("testdata/repositories"),
+		fn.WithRunPostCreate(true))
+
+	// Create from a template whose manifest declares a post-create hook
+	// which writes a marker file.
+	err := client.Create(fn.Function{
+		Root:     root,
+		Runtime:  "manifestedRuntime",
+		Template: "customLanguagePackRepo/manifestedTemplate",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "post-create.txt")); err != nil {
+		t.Fatalf("post-create hook did not run: %v", err)
+	}
+
+	// By default (without WithRunPostCreate) the hook is not run.
+	root2 := "testdata/example.com/testCreatePostCreateHookDisabled"
+	defer Using(t, root2)()
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithRepositoriesPath("testdata/repositories"))
+	err = client.Create(fn.Function{
+		Root:     root2,
+		Runtime:  "manifestedRuntime",
+		Template: "customLanguagePackRepo/manifestedTemplate",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root2, "post-create.txt")); err == nil {
+		t.Fatal("post-create hook ran despite not being enabled")
+	}
+
+	// A hook which exits non-zero is reported as an error.
+	root3 := "testdata/example.com/testCreatePostCreateHookFails"
+	defer Using(t, root3)()
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithRepositoriesPath("testdata/repositories"),
+		fn.WithRunPostCreate(true))
+	err = client.Create(fn.Function{
+		Root:     root3,
+		Runtime:  "manifestedRuntime",
+		Template: "customLanguagePackRepo/hookFailTemplate",
+	})
+	if err == nil {
+		t.Fatal("expected error from failing post-create hook")
+	}
+}
+
+// TestDeployError ensures that a DeployError renders its underlying cause
+// along with any gathered cluster diagnostics, and unwraps to the cause.
+func TestDeployError(t *testing.T) {
+	cause := errors.New("knative deployer failed to deploy the Knative Service")
+	err := fn.DeployError{
+		Err:        cause,
+		Conditions: []string{`Ready=False RevisionFailed: Revision "f-00001" failed`},
+		Events:     []string{"Warning Failed pod/f-00001-deployment: Back-off pulling image"},
+	}
+
+	if !errors.Is(err, cause) {
+		t.Fatal("DeployError should unwrap to its underlying cause")
+	}
+
+	msg := err.Error()
+	for _, expected := range []string{
+		cause.Error(),
+		"Service conditions:",
+		"RevisionFailed",
+		"Recent events:",
+		"Back-off pulling image",
+	} {
+		if !strings.Contains(msg, expected) {
+			t.Fatalf("expected error message to contain %q, got:\n%v", expected, msg)
+		}
+	}
+
+	// Without diagnostics the message is simply that of the cause.
+	if (fn.DeployError{Err: cause}).Error() != cause.Error() {
+		t.Fatal("expected bare DeployError to render only its cause")
+	}
+}
+
+// TestClient_Deploy_ByDigest ensures that deploys are pinned to the image
+// digest recorded at push by default (the deployer receives a reference of
+// the form image@sha256:...), and that WithDeployByDigest(false) reverts to
+// the mutable tag.
+func TestClient_Deploy_ByDigest(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	var (
+		image    = "example.com/alice/testdeploybydigest:latest"
+		digest   = "sha256:" + strings.Repeat("0", 64)
+		deployer = mock.NewDeployer()
+		client   = fn.New(
+			fn.WithRegistry(TestRegistry),
+			fn.WithDeployer(deployer),
+			fn.WithImage(image+"@"+digest))
+	)
+
+	if err := fn.New().Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// By default the deployer receives the digest-pinned reference.
+	deployer.DeployFn = func(f fn.Function) error {
+		expected := "example.com/alice/testdeploybydigest@" + digest
+		if f.ImageWithDigest() != expected {
+			t.Fatalf("deployer expected reference '%v', got '%v'", expected, f.ImageWithDigest())
+		}
+		return nil
+	}
+	if _, err := client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.DeployInvoked {
+		t.Fatal("deployer was not invoked")
+	}
+
+	// With digest pinning disabled the deployer receives only the tag.
+	deployer = mock.NewDeployer()
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(deployer),
+		fn.WithImage(image+"@"+digest),
+		fn.WithDeployByDigest(false))
+	deployer.DeployFn = func(f fn.Function) error {
+		if f.ImageDigest != "" {
+			t.Fatalf("deployer expected no digest, got '%v'", f.ImageDigest)
+		}
+		if f.ImageWithDigest() != image {
+			t.Fatalf("deployer expected reference '%v', got '%v'", image, f.ImageWithDigest())
+		}
+		return nil
+	}
+	if _, err := client.Deploy(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.DeployInvoked {
+		t.Fatal("deployer was not invoked")
+	}
+}
+
+// capabilityDeployer is a mock deployer which additionally reports the
+// runtimes its target cluster supports (see fn.RuntimeCapabilityLister).
+type capabilityDeployer struct {
+	*mock.Deployer
+	runtimes []string
+	err      error
+}
+
+func (d *capabilityDeployer) SupportedRuntimes(context.Context) ([]string, error) {
+	return d.runtimes, d.err
+}
+
+// TestClient_SupportedRuntimes ensures that the locally-available runtimes
+// are narrowed to those the target cluster supports when the deployer
+// reports capability information, and returned unchanged when it does not.
+func TestClient_SupportedRuntimes(t *testing.T) {
+	// A deployer without capability information yields the local list.
+	client := fn.New(fn.WithDeployer(mock.NewDeployer()))
+	local, err := client.Runtimes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	supported, err := client.SupportedRuntimes(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(local, supported) {
+		t.Fatalf("expected local runtimes %v unchanged, got %v", local, supported)
+	}
+
+	// A deployer reporting capabilities narrows the list to the
+	// intersection (here the cluster supports 'go' plus a runtime which
+	// is not available locally).
+	client = fn.New(fn.WithDeployer(&capabilityDeployer{
+		Deployer: mock.NewDeployer(),
+		runtimes: []string{"go", "cobol"}}))
+	if supported, err = client.SupportedRuntimes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"go"}, supported) {
+		t.Fatalf("expected intersection [go], got %v", supported)
+	}
+
+	// Errors querying the cluster are returned.
+	client = fn.New(fn.WithDeployer(&capabilityDeployer{
+		Deployer: mock.NewDeployer(),
+		err:      errors.New("cluster unreachable")}))
+	if _, err = client.SupportedRuntimes(context.Background()); err == nil {
+		t.Fatal("expected error querying cluster capabilities")
+	}
+}
+
+// TestClient_RunDataDirOverride ensures that WithRunDataDir relocates the
+// transient runtime metadata (build stamp etc.) such that nothing is
+// written to .func in the function's root, while Built and Touch continue
+// to work against the relocated directory.
+func TestClient_RunDataDirOverride(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+	scratch := t.TempDir()
+
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithRunDataDir(scratch))
+
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+
+	// The build stamp is written to the scratch directory, not the root.
+	if _, err := os.Stat(filepath.Join(scratch, "built")); err != nil {
+		t.Fatalf("expected build stamp in the relocated run data dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, fn.RunDataDir, "built")); err == nil {
+		t.Fatal("build stamp should not have been written to .func in the root")
+	}
+
+	// Staleness detection honors the relocated stamp.
+	if !client.Built(root) {
+		t.Fatal("function should report as built")
+	}
+	if err := client.Touch(root); err != nil {
+		t.Fatal(err)
+	}
+	if client.Built(root) {
+		t.Fatal("function should report as stale after being touched")
+	}
+}
+
+// TestClient_Repair ensures that a corrupted func.yaml can be repaired:
+// a truncated write is salvaged, missing required values defaulted, and
+// invalid members reset, with each repair reported and nothing written
+// unless explicitly requested.
+func TestClient_Repair(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+	client := fn.New(fn.WithRegistry(TestRegistry))
+
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A healthy function requires no repairs.
+	_, repaired, err := client.Repair(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repaired) != 0 {
+		t.Fatalf("expected no repairs for a healthy function, got %v", repaired)
+	}
+
+	// Corrupt the file: remove the name, retain an unknown field, and
+	// truncate mid-value as a partial write would.
+	corrupted := "specVersion: 0.35.0\nruntime: " + TestRuntime + "\nfutureFeature: enabled\nlabels:\n  - key: \"inva!id\"\nimage: \"unterminated\n"
+	if err := os.WriteFile(filepath.Join(root, fn.FunctionFile), []byte(corrupted), 0644); err != nil {
+		t.Fatal(err)
+	}
+	original, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, repaired, err := client.Repair(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repaired) == 0 {
+		t.Fatal("expected repairs to be reported")
+	}
+	if f.Name == "" {
+		t.Fatal("expected the name to have been defaulted")
+	}
+	if len(f.Deploy.Labels) != 0 {
+		t.Fatal("expected the invalid labels to have been reset")
+	}
+
+	// Nothing was written by Repair itself.
+	current, err := os.ReadFile(filepath.Join(root, fn.FunctionFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(current) != string(original) {
+		t.Fatal("Repair should not write")
+	}
+
+	// Writing the repaired function yields a loadable func.yaml which
+	// retains the unknown field.
+	if err := client.WriteRepaired(f); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fn.NewFunction(root); err != nil {
+		t.Fatalf("repaired function failed to load: %v", err)
+	}
+	if bb, _ := os.ReadFile(filepath.Join(root, fn.FunctionFile)); !strings.Contains(string(bb), "futureFeature") {
+		t.Fatal("expected unknown fields to be preserved on write")
+	}
+}
+
+// trafficDeployer is a mock deployer which additionally supports deploying
+// with a traffic split (see fn.TrafficDeployer).
+type trafficDeployer struct {
+	*mock.Deployer
+	opts fn.DeployOptions
+}
+
+func (d *trafficDeployer) DeployWithTraffic(ctx context.Context, f fn.Function, opts fn.DeployOptions) (fn.DeploymentResult, error) {
+	d.opts = opts
+	return fn.DeploymentResult{
+		Status: fn.Deployed,
+		Traffic: []fn.TrafficTarget{
+			{RevisionName: "f-00002", Percent: int64(opts.TrafficPercent)},
+			{RevisionName: "f-00001", Percent: int64(100 - opts.TrafficPercent)},
+		},
+	}, nil
+}
+
+// TestClient_Deploy_TrafficSplit ensures that deploy options requesting a
+// traffic split are validated and passed through to a supporting deployer,
+// with the resulting distribution surfaced, and that a deployer without
+// support is rejected.
+func TestClient_Deploy_TrafficSplit(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	deployer := &trafficDeployer{Deployer: mock.NewDeployer()}
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithDeployer(deployer),
+		fn.WithDeployOptions(fn.DeployOptions{TrafficPercent: 10}))
+
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Build(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.Deploy(context.Background(), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deployer.opts.TrafficPercent != 10 {
+		t.Fatalf("expected the deployer to receive a 10%% split, got %v", deployer.opts.TrafficPercent)
+	}
+	if len(result.Traffic) != 2 || result.Traffic[0].Percent != 10 || result.Traffic[1].Percent != 90 {
+		t.Fatalf("unexpected traffic distribution: %v", result.Traffic)
+	}
+
+	// Out-of-range percentages are rejected.
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(deployer),
+		fn.WithDeployOptions(fn.DeployOptions{TrafficPercent: 101}))
+	if _, err = client.Deploy(context.Background(), root); err == nil {
+		t.Fatal("expected out-of-range traffic percent to error")
+	}
+
+	// A deployer without traffic support is rejected when a split is
+	// requested.
+	client = fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithDeployer(mock.NewDeployer()),
+		fn.WithDeployOptions(fn.DeployOptions{TrafficPercent: 10}))
+	if _, err = client.Deploy(context.Background(), root); err == nil {
+		t.Fatal("expected a deployer without traffic support to be rejected")
+	}
+}
+
+// revisionDescriber is a mock describer which additionally implements the
+// optional RevisionDescriber interface.
+type revisionDescriber struct {
+	*mock.Describer
+	RevisionURLInvoked bool
+	RevisionURLFn      func(funcName, revision string) (string, error)
+}
+
+func (d *revisionDescriber) RevisionURL(_ context.Context, funcName, revision string) (string, error) {
+	d.RevisionURLInvoked = true
+	return d.RevisionURLFn(funcName, revision)
+}
+
+// TestClient_Invoke_Revision ensures that a 'revision:' target is resolved
+// to a concrete URL via the describer, that resolution errors (such as a
+// nonexistent revision) are propagated, and that describers without revision
+// support are reported as such.
+func TestClient_Invoke_Revision(t *testing.T) {
+	root := "testdata/example.com/testInvokeRevision"
+	defer Using(t, root)()
+
+	var invoked int32
+
+	// An HTTP handler masquerading as the canary revision's dedicated route.
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		atomic.StoreInt32(&invoked, 1)
+	})
+	l, err := net.Listen("tcp4", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := http.Server{Handler: handler}
+	go func() {
+		if err := s.Serve(l); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "error serving: %v", err)
+		}
+	}()
+	defer s.Close()
+
+	// A describer which reports the above server as the revision's URL.
+	describer := &revisionDescriber{Describer: mock.NewDescriber()}
+	describer.RevisionURLFn = func(funcName, revision string) (string, error) {
+		if revision != "testInvokeRevision-00002" {
+			return "", fmt.Errorf("no revision or traffic tag '%v' found for function '%v'", revision, funcName)
+		}
+		return "http://" + l.Addr().String(), nil
+	}
+	client := fn.New(fn.WithRegistry(TestRegistry), fn.WithDescriber(describer))
+
+	f := fn.Function{Runtime: TestRuntime, Root: root}
+	if err := client.New(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invoke targeting the canary revision.
+	if _, _, err := client.Invoke(context.Background(), root, "revision:testInvokeRevision-00002", fn.NewInvokeMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if !describer.RevisionURLInvoked {
+		t.Fatal("describer was not asked to resolve the revision URL")
+	}
+	if atomic.LoadInt32(&invoked) != 1 {
+		t.Fatal("revision was not invoked")
+	}
+
+	// A nonexistent revision errors.
+	if _, _, err := client.Invoke(context.Background(), root, "revision:no-such-revision", fn.NewInvokeMessage()); err == nil {
+		t.Fatal("expected error for a nonexistent revision")
+	}
+
+	// A describer without revision support errors clearly.
+	client = fn.New(fn.WithRegistry(TestRegistry), fn.WithDescriber(mock.NewDescriber()))
+	if _, _, err := client.Invoke(context.Background(), root, "revision:testInvokeRevision-00002", fn.NewInvokeMessage()); err == nil {
+		t.Fatal("expected a describer without revision support to be rejected")
+	}
+}
+
+// TestClient_Create_GitignoreAppend ensures that an existing .gitignore is
+// appended to (rather than overwritten) when it lacks the /.func entry, is
+// left untouched when the entry is already present, and that creation can be
+// skipped entirely via WithNoGitignore.
+func TestClient_Create_GitignoreAppend(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	// A pre-existing .gitignore without the entry, lacking a trailing newline.
+	existing := "node_modules\n*.log"
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(bb), existing+"\n") {
+		t.Fatalf("existing .gitignore content was not preserved: %q", bb)
+	}
+	if !strings.Contains(string(bb), "/.func") {
+		t.Fatalf("expected /.func entry to be appended, got %q", bb)
+	}
+
+	// A .gitignore which already has the entry is left untouched.
+	root2, rm2 := Mktemp(t)
+	defer rm2()
+	withEntry := "# mine\n/.func\nnode_modules\n"
+	if err := os.WriteFile(filepath.Join(root2, ".gitignore"), []byte(withEntry), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root2}); err != nil {
+		t.Fatal(err)
+	}
+	if bb, err = os.ReadFile(filepath.Join(root2, ".gitignore")); err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) != withEntry {
+		t.Fatalf(".gitignore with existing entry was modified: %q", bb)
+	}
+
+	// WithNoGitignore skips creation entirely.
+	root3, rm3 := Mktemp(t)
+	defer rm3()
+	client = fn.New(fn.WithRegistry(TestRegistry), fn.WithNoGitignore(true))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root3}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root3, ".gitignore")); !os.IsNotExist(err) {
+		t.Fatal("expected no .gitignore to be created")
+	}
+}
+
+// TestClient_Stop ensures that stopping reports ErrNotRunning when no local
+// instance is running, and that a stale run record (whose process no longer
+// exists) is cleaned up.
+func TestClient_Stop(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// No run record: not running
+	if err := client.Stop(context.Background(), root); !errors.Is(err, fn.ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+
+	// A record referring to a process which has since exited is stale and
+	// should be removed, with the run again reported as not running.
+	cmd := exec.Command("go", "version")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	record := fmt.Sprintf(`{"pid": %d, "port": "8080"}`, cmd.Process.Pid)
+	recordPath := filepath.Join(root, fn.RunDataDir, "run.json")
+	if err := os.WriteFile(recordPath, []byte(record), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Stop(context.Background(), root); !errors.Is(err, fn.ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning for a stale record, got %v", err)
+	}
+	if _, err := os.Stat(recordPath); !os.IsNotExist(err) {
+		t.Fatal("expected the stale run record to be removed")
+	}
+}
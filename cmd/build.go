@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
@@ -47,7 +48,7 @@ and the image name is stored in the configuration file.
 {{.Name}} build --builder=pack --builder-image cnbs/sample-builder:bionic
 `,
 		SuggestFor: []string{"biuld", "buidl", "built"},
-		PreRunE:    bindEnv("image", "path", "builder", "registry", "confirm", "push", "builder-image", "platform"),
+		PreRunE:    bindEnv("image", "path", "builder", "registry", "confirm", "push", "builder-image", "platform", "invalidate"),
 	}
 
 	cmd.Flags().StringP("builder", "b", builders.Default, fmt.Sprintf("build strategy to use when creating the underlying image. Currently supported build strategies are %s.", KnownBuilders()))
@@ -58,6 +59,9 @@ and the image name is stored in the configuration file.
 	cmd.Flags().BoolP("push", "u", false, "Attempt to push the function image after being successfully built")
 	cmd.Flags().Lookup("push").NoOptDefVal = "true" // --push == --push=true
 	cmd.Flags().StringP("platform", "", "", "Target platform to build (e.g. linux/amd64).")
+	cmd.Flags().BoolP("invalidate", "", false, "Discard the record of any previous build such that subsequent builds and deploys rebuild the function even when no source files have changed (Env: $FUNC_INVALIDATE)")
+	cmd.Flags().StringArrayP("build-arg", "", []string{}, "Build argument to pass to the build tool in the form KEY=VALUE. "+
+		"You may provide this flag multiple times.  Unlike --build-env these are build-tool directives, not container environment variables.")
 	setPathFlag(cmd)
 
 	if err := cmd.RegisterFlagCompletionFunc("builder", CompleteBuilderList); err != nil {
@@ -108,8 +112,9 @@ func runBuild(cmd *cobra.Command, _ []string, newClient ClientFactory) (err erro
 	if !f.Initialized() {
 		return fmt.Errorf("'%v' does not contain an initialized function", config.Path)
 	}
-	if f.Registry == "" || cmd.Flags().Changed("registry") {
-		// Sets default AND accepts any user-provided overrides
+	if f.Registry == "" || cmd.Flags().Changed("registry") || os.Getenv("FUNC_REGISTRY") != "" {
+		// Sets default AND accepts any user-provided overrides, with the
+		// flag and environment taking precedence over the persisted registry
 		f.Registry = config.Registry
 	}
 	if f.Build.Builder == "" || cmd.Flags().Changed("builder") {
@@ -125,6 +130,18 @@ func runBuild(cmd *cobra.Command, _ []string, newClient ClientFactory) (err erro
 	if config.BuilderImage != "" {
 		f.Build.BuilderImages[config.Builder] = config.BuilderImage
 	}
+	buildArgs, err := buildArgsFromCmd(cmd)
+	if err != nil {
+		return
+	}
+	if len(buildArgs) > 0 {
+		if f.Build.BuildArgs == nil {
+			f.Build.BuildArgs = make(map[string]string, len(buildArgs))
+		}
+		for k, v := range buildArgs {
+			f.Build.BuildArgs[k] = v
+		}
+	}
 
 	// Validate that a builder short-name was obtained, whether that be from
 	// the function's prior state, or the value of flags/environment.
@@ -144,8 +161,10 @@ func runBuild(cmd *cobra.Command, _ []string, newClient ClientFactory) (err erro
 			s2i.WithPlatform(config.Platform),
 			s2i.WithVerbose(config.Verbose))
 	} else {
-		err = fmt.Errorf("builder '%v' is not recognized", f.Build.Builder)
-		return
+		// Not a built-in builder; delegate to the third-party builder plugin
+		// of that name (a func-builder-<name> executable on PATH), whose
+		// presence was confirmed by ValidateBuilder above.
+		builder = builders.NewExecBuilder(f.Build.Builder, config.Verbose)
 	}
 
 	client, done := newClient(ClientConfig{Verbose: config.Verbose},
@@ -185,6 +204,12 @@ func runBuild(cmd *cobra.Command, _ []string, newClient ClientFactory) (err erro
 		return
 	}
 
+	if config.Invalidate {
+		if err = client.Touch(config.Path); err != nil {
+			return
+		}
+	}
+
 	if err = client.Build(cmd.Context(), config.Path); err != nil {
 		return
 	}
@@ -228,6 +253,10 @@ type buildConfig struct {
 	BuilderImage string
 
 	Platform string
+
+	// Invalidate the record of any previous build before building, forcing
+	// a rebuild even when no source files have changed.
+	Invalidate bool
 }
 
 func newBuildConfig() buildConfig {
@@ -241,6 +270,7 @@ func newBuildConfig() buildConfig {
 		BuilderImage: viper.GetString("builder-image"),
 		Push:         viper.GetBool("push"),
 		Platform:     viper.GetString("platform"),
+		Invalidate:   viper.GetBool("invalidate"),
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/builders"
 	"knative.dev/kn-plugin-func/buildpacks"
 	"knative.dev/kn-plugin-func/docker"
 	"knative.dev/kn-plugin-func/docker/creds"
@@ -14,6 +15,7 @@ import (
 	"knative.dev/kn-plugin-func/openshift"
 	"knative.dev/kn-plugin-func/pipelines/tekton"
 	"knative.dev/kn-plugin-func/progress"
+	"knative.dev/kn-plugin-func/s2i"
 )
 
 // ClientConfig settings for use with NewClient
@@ -75,11 +77,16 @@ func NewClient(cfg ClientConfig, options ...fn.Option) (*fn.Client, func()) {
 			fn.WithProgressListener(p),
 			fn.WithTransport(t),
 			fn.WithBuilder(buildpacks.NewBuilder(buildpacks.WithVerbose(cfg.Verbose))),
+			fn.WithBuilderImageDefaults(builders.Pack, buildpacks.DefaultBuilderImages),
+			fn.WithBuilderImageDefaults(builders.S2I, s2i.DefaultBuilderImages),
 			fn.WithRemover(knative.NewRemover(cfg.Namespace, cfg.Verbose)),
 			fn.WithDescriber(knative.NewDescriber(cfg.Namespace, cfg.Verbose)),
 			fn.WithLister(knative.NewLister(cfg.Namespace, cfg.Verbose)),
-			fn.WithRunner(docker.NewRunner(cfg.Verbose)),
+			fn.WithRunner(docker.NewRunner(cfg.Verbose, docker.WithVolumeMounts(true))),
 			fn.WithDeployer(d),
+			fn.WithRollbacker(knative.NewRollbacker(cfg.Namespace, cfg.Verbose)),
+			fn.WithPromoter(knative.NewPromoter(cfg.Verbose)),
+			fn.WithSubscriber(knative.NewSubscriber(cfg.Namespace, cfg.Verbose)),
 			fn.WithPipelinesProvider(pp),
 			fn.WithPusher(docker.NewPusher(
 				docker.WithCredentialsProvider(c),
@@ -89,6 +96,13 @@ func NewClient(cfg ClientConfig, options ...fn.Option) (*fn.Client, func()) {
 		}
 	)
 
+	// Allow relocating the transient run data directory (.func) via the
+	// environment, for workspaces which are read-only except for a scratch
+	// directory.
+	if dir := os.Getenv("FUNC_RUN_DATA_DIR"); dir != "" {
+		o = append(o, fn.WithRunDataDir(dir))
+	}
+
 	// Client is constructed with standard options plus any additional options
 	// which either augment or override the defaults.
 	client := fn.New(append(o, options...)...)
@@ -107,13 +121,21 @@ func NewClient(cfg ClientConfig, options ...fn.Option) (*fn.Client, func()) {
 // newTransport returns a transport with cluster-flavor-specific variations
 // which take advantage of additional features offered by cluster variants.
 func newTransport(insecureSkipVerify bool) fnhttp.RoundTripCloser {
+	opts := []fnhttp.Option{fnhttp.WithInsecureSkipVerify(insecureSkipVerify)}
+
+	// An additional CA certificate, such as that of an internal registry,
+	// may be trusted via the environment.
+	if ca := os.Getenv("FUNC_CA_CERT"); ca != "" {
+		opts = append(opts, fnhttp.WithCACert(ca))
+	}
+
 	if openshift.IsOpenShift() {
-		return fnhttp.NewRoundTripper(fnhttp.WithInsecureSkipVerify(insecureSkipVerify), openshift.WithOpenShiftServiceCA())
+		opts = append(opts, openshift.WithOpenShiftServiceCA())
 	}
 
 	// Other cluster variants ...
 
-	return fnhttp.NewRoundTripper(fnhttp.WithInsecureSkipVerify(insecureSkipVerify)) // Default (vanilla k8s)
+	return fnhttp.NewRoundTripper(opts...)
 }
 
 // newCredentialsProvider returns a credentials provider which possibly
@@ -17,7 +17,7 @@ import (
 func CompleteFunctionList(cmd *cobra.Command, args []string, toComplete string) (strings []string, directive cobra.ShellCompDirective) {
 	lister := knative.NewLister("", false)
 
-	list, err := lister.List(cmd.Context())
+	list, err := lister.List(cmd.Context(), fn.ListOptions{})
 	if err != nil {
 		directive = cobra.ShellCompDirectiveError
 		return
@@ -65,6 +65,15 @@ or from the directory specified with --path.
 	cmd.AddCommand(NewConfigLabelsCmd(loadSaver))
 	cmd.AddCommand(NewConfigEnvsCmd(loadSaver))
 	cmd.AddCommand(NewConfigVolumesCmd())
+	cmd.AddCommand(NewConfigInitContainersCmd(loadSaver))
+	cmd.AddCommand(NewConfigInvocationCmd(loadSaver))
+	cmd.AddCommand(NewConfigRegistryCmd(loadSaver))
+	cmd.AddCommand(NewConfigRunImageCmd(loadSaver))
+	cmd.AddCommand(NewConfigServiceAccountCmd(loadSaver))
+	cmd.AddCommand(NewConfigHealthCmd())
+	cmd.AddCommand(NewConfigBuilderImagesCmd(loadSaver))
+	cmd.AddCommand(NewConfigResourcesCmd())
+	cmd.AddCommand(NewConfigShowCmd())
 
 	return cmd
 }
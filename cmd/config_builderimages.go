@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewConfigBuilderImagesCmd(loadSaver functionLoaderSaver) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "builder-images",
+		Short: "List builder images which would be used to build the function",
+		Long: `List builder images which would be used to build the function
+
+Prints the builder image per builder implementation (pack, s2i) for the
+language runtime of the function project present in the current directory
+or from the directory specified with --path.  Overrides configured in
+func.yaml take precedence over the built-in defaults.
+`,
+		SuggestFor: []string{"builderimages", "builder-image"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(loadSaver)
+			if err != nil {
+				return
+			}
+
+			client, done := NewClient(ClientConfig{Verbose: viper.GetBool("verbose")})
+			defer done()
+
+			return listBuilderImages(cmd, function, client)
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	setPathFlag(cmd)
+
+	return cmd
+}
+
+func listBuilderImages(cmd *cobra.Command, f fn.Function, client *fn.Client) error {
+	images, err := client.BuilderImages(f.Runtime)
+	if err != nil {
+		return err
+	}
+
+	// Overrides configured on the function take precedence over defaults.
+	overridden := map[string]bool{}
+	for builder, image := range f.Build.BuilderImages {
+		images[builder] = image
+		overridden[builder] = true
+	}
+
+	builders := make([]string, 0, len(images))
+	for builder := range images {
+		builders = append(builders, builder)
+	}
+	sort.Strings(builders)
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Builder images for runtime '%v':\n", f.Runtime)
+	for _, builder := range builders {
+		suffix := ""
+		if overridden[builder] {
+			suffix = " (from func.yaml)"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), " - %v: %v%v\n", builder, images[builder], suffix)
+	}
+	return nil
+}
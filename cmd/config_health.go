@@ -0,0 +1,421 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewConfigHealthCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "List and manage health probe configuration for a function",
+		Long: `List and manage health probe configuration for a function
+
+Prints the configured liveness and readiness endpoints, along with probe
+timings, for a function project present in the current directory or from
+the directory specified with --path.
+`,
+		SuggestFor: []string{"helth", "probes", "probe"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(defaultLoaderSaver)
+			if err != nil {
+				return
+			}
+
+			listHealth(function)
+
+			return
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	configHealthSetCmd := NewConfigHealthSetCmd()
+	configHealthAddCmd := NewConfigHealthAddCmd()
+	configHealthRemoveCmd := NewConfigHealthRemoveCmd()
+
+	setPathFlag(cmd)
+	setPathFlag(configHealthSetCmd)
+	setPathFlag(configHealthAddCmd)
+	setPathFlag(configHealthRemoveCmd)
+
+	cmd.AddCommand(configHealthSetCmd)
+	cmd.AddCommand(configHealthAddCmd)
+	cmd.AddCommand(configHealthRemoveCmd)
+
+	return cmd
+}
+
+func NewConfigHealthSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set health probe configuration for the function",
+		Long: `Set health probe configuration for the function
+
+Interactive prompt to set the liveness and readiness endpoints plus probe
+timings (initial delay, period and failure threshold) of the function
+project in the current directory or from the directory specified with
+--path.  Empty values defer to the platform's defaults.
+`,
+		SuggestFor: []string{"st", "update"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(defaultLoaderSaver)
+			if err != nil {
+				return
+			}
+
+			return runSetHealthPrompt(function)
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+	return cmd
+}
+
+func listHealth(f fn.Function) {
+	he := f.Deploy.HealthEndpoints
+	fmt.Println("Configured health probes:")
+	fmt.Println(" - liveness: ", orDefault(he.Liveness))
+	fmt.Println(" - readiness:", orDefault(he.Readiness))
+	if he.InitialDelaySeconds != nil {
+		fmt.Println(" - initial delay seconds:", *he.InitialDelaySeconds)
+	}
+	if he.PeriodSeconds != nil {
+		fmt.Println(" - period seconds:", *he.PeriodSeconds)
+	}
+	if he.FailureThreshold != nil {
+		fmt.Println(" - failure threshold:", *he.FailureThreshold)
+	}
+	for _, e := range he.Endpoints {
+		fmt.Println(" - ", endpointString(e))
+	}
+}
+
+func endpointString(e fn.HealthEndpoint) string {
+	if e.Port != 0 {
+		return fmt.Sprintf("%v endpoint %v on port %v", e.Type, e.Path, e.Port)
+	}
+	return fmt.Sprintf("%v endpoint %v", e.Type, e.Path)
+}
+
+func orDefault(s string) string {
+	if s == "" {
+		return "(default)"
+	}
+	return s
+}
+
+func runSetHealthPrompt(f fn.Function) (err error) {
+	he := f.Deploy.HealthEndpoints
+
+	// Paths must be either empty (defer to the default) or absolute.
+	pathValidator := func(val interface{}) error {
+		if str, ok := val.(string); !ok || (str != "" && !strings.HasPrefix(str, "/")) {
+			return fmt.Errorf("The endpoint must be an absolute path.")
+		}
+		return nil
+	}
+
+	// Timings must be either empty (defer to the default) or positive.
+	timingValidator := func(val interface{}) error {
+		str, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("The value must be a positive number of seconds.")
+		}
+		if str == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(str, 10, 32)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("The value must be a positive number of seconds.")
+		}
+		return nil
+	}
+
+	qs := []*survey.Question{
+		{
+			Name: "liveness",
+			Prompt: &survey.Input{
+				Message: "Liveness endpoint:",
+				Default: he.Liveness,
+			},
+			Validate: pathValidator,
+		},
+		{
+			Name: "readiness",
+			Prompt: &survey.Input{
+				Message: "Readiness endpoint:",
+				Default: he.Readiness,
+			},
+			Validate: pathValidator,
+		},
+		{
+			Name: "initialDelay",
+			Prompt: &survey.Input{
+				Message: "Initial delay seconds (empty for default):",
+				Default: timingString(he.InitialDelaySeconds),
+			},
+			Validate: timingValidator,
+		},
+		{
+			Name: "period",
+			Prompt: &survey.Input{
+				Message: "Period seconds (empty for default):",
+				Default: timingString(he.PeriodSeconds),
+			},
+			Validate: timingValidator,
+		},
+		{
+			Name: "failureThreshold",
+			Prompt: &survey.Input{
+				Message: "Failure threshold (empty for default):",
+				Default: timingString(he.FailureThreshold),
+			},
+			Validate: timingValidator,
+		},
+	}
+
+	answers := struct {
+		Liveness         string
+		Readiness        string
+		InitialDelay     string
+		Period           string
+		FailureThreshold string
+	}{}
+
+	if err = survey.Ask(qs, &answers); err != nil {
+		if err == terminal.InterruptErr {
+			return nil
+		}
+		return
+	}
+
+	he.Liveness = answers.Liveness
+	he.Readiness = answers.Readiness
+	if he.InitialDelaySeconds, err = parseTiming(answers.InitialDelay); err != nil {
+		return
+	}
+	if he.PeriodSeconds, err = parseTiming(answers.Period); err != nil {
+		return
+	}
+	if he.FailureThreshold, err = parseTiming(answers.FailureThreshold); err != nil {
+		return
+	}
+
+	f.Deploy.HealthEndpoints = he
+
+	err = f.Write()
+	if err == nil {
+		fmt.Println("Health probe configuration was added to the function configuration")
+	}
+
+	return
+}
+
+func NewConfigHealthAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a typed health endpoint to the function configuration",
+		Long: `Add a typed health endpoint to the function configuration
+
+Interactive prompt to add a health endpoint with an explicit probe type
+(liveness, readiness or startup) to the function project in the current
+directory or from the directory specified with --path.  An existing endpoint
+of the same type is replaced.
+`,
+		SuggestFor: []string{"ad", "create", "insert", "append"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(defaultLoaderSaver)
+			if err != nil {
+				return
+			}
+
+			return runAddHealthPrompt(function)
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+	return cmd
+}
+
+func NewConfigHealthRemoveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove a typed health endpoint from the function configuration",
+		Long: `Remove a typed health endpoint from the function configuration
+
+Interactive prompt to remove a typed health endpoint from the function
+project in the current directory or from the directory specified with --path.
+`,
+		SuggestFor: []string{"del", "delete", "rmeove"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(defaultLoaderSaver)
+			if err != nil {
+				return
+			}
+
+			return runRemoveHealthPrompt(function)
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+	return cmd
+}
+
+func runAddHealthPrompt(f fn.Function) (err error) {
+	qs := []*survey.Question{
+		{
+			Name: "type",
+			Prompt: &survey.Select{
+				Message: "Probe type:",
+				Options: []string{fn.ProbeLiveness, fn.ProbeReadiness, fn.ProbeStartup},
+			},
+		},
+		{
+			Name: "path",
+			Prompt: &survey.Input{
+				Message: "Endpoint path:",
+			},
+			Validate: func(val interface{}) error {
+				if str, ok := val.(string); !ok || !strings.HasPrefix(str, "/") {
+					return fmt.Errorf("The endpoint must be an absolute path.")
+				}
+				return nil
+			},
+		},
+		{
+			Name: "port",
+			Prompt: &survey.Input{
+				Message: "Port (empty for the function's serving port):",
+			},
+			Validate: func(val interface{}) error {
+				str, ok := val.(string)
+				if !ok {
+					return fmt.Errorf("The port must be a number between 1 and 65535.")
+				}
+				if str == "" {
+					return nil
+				}
+				n, err := strconv.ParseInt(str, 10, 32)
+				if err != nil || n < 1 || n > 65535 {
+					return fmt.Errorf("The port must be a number between 1 and 65535.")
+				}
+				return nil
+			},
+		},
+	}
+
+	answers := struct {
+		Type string
+		Path string
+		Port string
+	}{}
+
+	if err = survey.Ask(qs, &answers); err != nil {
+		if err == terminal.InterruptErr {
+			return nil
+		}
+		return
+	}
+
+	endpoint := fn.HealthEndpoint{Type: answers.Type, Path: answers.Path}
+	if answers.Port != "" {
+		n, _ := strconv.ParseInt(answers.Port, 10, 32)
+		endpoint.Port = int32(n)
+	}
+
+	// Each probe type may be specified at most once; an existing endpoint of
+	// the same type is replaced.
+	replaced := false
+	for i, e := range f.Deploy.HealthEndpoints.Endpoints {
+		if e.Type == endpoint.Type {
+			f.Deploy.HealthEndpoints.Endpoints[i] = endpoint
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		f.Deploy.HealthEndpoints.Endpoints = append(f.Deploy.HealthEndpoints.Endpoints, endpoint)
+	}
+
+	err = f.Write()
+	if err == nil {
+		if replaced {
+			fmt.Printf("The existing %v endpoint was replaced in the function configuration\n", endpoint.Type)
+		} else {
+			fmt.Println("Health endpoint was added to the function configuration")
+		}
+	}
+
+	return
+}
+
+func runRemoveHealthPrompt(f fn.Function) (err error) {
+	if len(f.Deploy.HealthEndpoints.Endpoints) == 0 {
+		fmt.Println("There aren't any configured typed health endpoints")
+		return
+	}
+
+	options := []string{}
+	for _, e := range f.Deploy.HealthEndpoints.Endpoints {
+		options = append(options, endpointString(e))
+	}
+
+	selectedEndpoint := ""
+	prompt := &survey.Select{
+		Message: "Which health endpoint do you want to remove?",
+		Options: options,
+	}
+	if err = survey.AskOne(prompt, &selectedEndpoint); err != nil {
+		if err == terminal.InterruptErr {
+			return nil
+		}
+		return
+	}
+
+	var newEndpoints []fn.HealthEndpoint
+	removed := false
+	for _, e := range f.Deploy.HealthEndpoints.Endpoints {
+		if !removed && endpointString(e) == selectedEndpoint {
+			removed = true
+			continue
+		}
+		newEndpoints = append(newEndpoints, e)
+	}
+
+	if removed {
+		f.Deploy.HealthEndpoints.Endpoints = newEndpoints
+		err = f.Write()
+		if err == nil {
+			fmt.Println("Health endpoint was removed from the function configuration")
+		}
+	}
+
+	return
+}
+
+func timingString(v *int32) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*v), 10)
+}
+
+func parseTiming(s string) (*int32, error) {
+	if s == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	v := int32(n)
+	return &v, nil
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewConfigInitContainersCmd(loaderSaver functionLoaderSaver) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init-containers",
+		Short: "List and manage configured init containers for a function",
+		Long: `List and manage configured init containers for a function
+
+Prints the init containers configured for a function project present in
+the current directory or from the directory specified with --path.  Init
+containers run to completion, in order, before the function's container
+starts, and share the function's configured volumes.
+`,
+		SuggestFor: []string{"init-container", "initcontainers", "init"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(loaderSaver)
+			if err != nil {
+				return
+			}
+
+			listInitContainers(function)
+
+			return
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	configInitContainersAddCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add an init container to the function configuration",
+		Long: `Add an init container to the function configuration
+
+Interactive prompt to add an init container to the function project in the
+current directory or from the directory specified with --path.  Env
+variables for init containers may be edited in func.yaml.
+`,
+		SuggestFor: []string{"ad", "create", "insert", "append"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(loaderSaver)
+			if err != nil {
+				return
+			}
+
+			return runAddInitContainerPrompt(function, loaderSaver)
+		},
+	}
+	configInitContainersAddCmd.SetHelpFunc(defaultTemplatedHelp)
+
+	configInitContainersRemoveCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove an init container from the function configuration",
+		Long: `Remove an init container from the function configuration
+
+Interactive prompt to remove an init container from the function project in
+the current directory or from the directory specified with --path.
+`,
+		SuggestFor: []string{"del", "delete", "rmeove"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(loaderSaver)
+			if err != nil {
+				return
+			}
+
+			return runRemoveInitContainerPrompt(function, loaderSaver)
+		},
+	}
+	configInitContainersRemoveCmd.SetHelpFunc(defaultTemplatedHelp)
+
+	setPathFlag(cmd)
+	setPathFlag(configInitContainersAddCmd)
+	setPathFlag(configInitContainersRemoveCmd)
+	cmd.AddCommand(configInitContainersAddCmd)
+	cmd.AddCommand(configInitContainersRemoveCmd)
+
+	return cmd
+}
+
+func listInitContainers(f fn.Function) {
+	if len(f.Run.InitContainers) == 0 {
+		fmt.Println("There aren't any configured init containers")
+		return
+	}
+
+	fmt.Println("Configured init containers:")
+	for _, c := range f.Run.InitContainers {
+		fmt.Println(" - ", initContainerString(c))
+	}
+}
+
+func initContainerString(c fn.InitContainer) string {
+	s := c.Image
+	if c.Name != "" {
+		s = fmt.Sprintf("%v (%v)", c.Image, c.Name)
+	}
+	if len(c.Command) > 0 {
+		s = fmt.Sprintf("%v running %q", s, strings.Join(c.Command, " "))
+	}
+	return s
+}
+
+func runAddInitContainerPrompt(f fn.Function, saver functionSaver) (err error) {
+	qs := []*survey.Question{
+		{
+			Name: "image",
+			Prompt: &survey.Input{
+				Message: "Please specify the image:",
+			},
+			Validate: survey.Required,
+		},
+		{
+			Name: "name",
+			Prompt: &survey.Input{
+				Message: "Name (optional):",
+			},
+		},
+		{
+			Name: "command",
+			Prompt: &survey.Input{
+				Message: "Command, space separated (empty for the image entrypoint):",
+			},
+		},
+	}
+
+	answers := struct {
+		Image   string
+		Name    string
+		Command string
+	}{}
+
+	if err = survey.Ask(qs, &answers); err != nil {
+		if err == terminal.InterruptErr {
+			return nil
+		}
+		return
+	}
+
+	container := fn.InitContainer{Name: answers.Name, Image: answers.Image}
+	if strings.TrimSpace(answers.Command) != "" {
+		container.Command = strings.Fields(answers.Command)
+	}
+
+	f.Run.InitContainers = append(f.Run.InitContainers, container)
+
+	err = saver.Save(f)
+	if err == nil {
+		fmt.Println("Init container was added to the function configuration")
+	}
+
+	return
+}
+
+func runRemoveInitContainerPrompt(f fn.Function, saver functionSaver) (err error) {
+	if len(f.Run.InitContainers) == 0 {
+		fmt.Println("There aren't any configured init containers")
+		return
+	}
+
+	options := []string{}
+	for _, c := range f.Run.InitContainers {
+		options = append(options, initContainerString(c))
+	}
+
+	selectedContainer := ""
+	prompt := &survey.Select{
+		Message: "Which init container do you want to remove?",
+		Options: options,
+	}
+	if err = survey.AskOne(prompt, &selectedContainer); err != nil {
+		if err == terminal.InterruptErr {
+			return nil
+		}
+		return
+	}
+
+	var newContainers []fn.InitContainer
+	removed := false
+	for _, c := range f.Run.InitContainers {
+		if !removed && initContainerString(c) == selectedContainer {
+			removed = true
+			continue
+		}
+		newContainers = append(newContainers, c)
+	}
+
+	if removed {
+		f.Run.InitContainers = newContainers
+		err = saver.Save(f)
+		if err == nil {
+			fmt.Println("Init container was removed from the function configuration")
+		}
+	}
+
+	return
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewConfigInvocationCmd(loader functionLoader) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invocation [format]",
+		Short: "List or set the function invocation format",
+		Long: `List or set the function invocation format
+
+Prints the invocation format (http|cloudevent) of a function project present
+in the current directory or from the directory specified with --path.
+
+When a format is given as an argument, the function is updated to use it,
+allowing, for example, a function created from an 'http' template to be
+developed as a CloudEvents handler without recreating the project.  The
+format must be supported by a template available for the function's runtime.
+`,
+		SuggestFor: []string{"invocations", "ivocation", "incovation"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return runConfigInvocation(cmd, args, loader)
+		},
+	}
+
+	setPathFlag(cmd)
+
+	return cmd
+}
+
+func runConfigInvocation(cmd *cobra.Command, args []string, loader functionLoader) (err error) {
+	if len(args) > 1 {
+		return errors.New("unexpected extra arguments")
+	}
+
+	config := newConfigCmdConfig()
+
+	// With no arguments, print the currently effective format.
+	if len(args) == 0 {
+		function, err := loader.Load(config.Path)
+		if err != nil {
+			return err
+		}
+		format := function.Invocation.Format
+		if format == "" {
+			format = fn.DefaultInvocationFormat
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), format)
+		return nil
+	}
+
+	// Repositories are required to validate the requested format against the
+	// templates available for the function's runtime.
+	repositoriesPath := os.Getenv("FUNC_REPOSITORIES_PATH")
+	if repositoriesPath == "" { // if no env var provided
+		repositoriesPath = fn.New().RepositoriesPath() // default to ~/.config/func/repositories
+	}
+
+	client := fn.New(fn.WithRepositoriesPath(repositoriesPath))
+	return client.SetInvocation(config.Path, args[0])
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
@@ -103,6 +104,17 @@ func listLabels(f fn.Function) {
 	}
 }
 
+// validateLabelKeyPrompt validates a label key entered at the interactive
+// prompt, rejecting invalid syntax as well as prefixes reserved for
+// Kubernetes core components.
+func validateLabelKeyPrompt(val interface{}) error {
+	key := val.(string)
+	if msgs := (fn.Label{Key: &key}).Validate(); len(msgs) > 0 {
+		return fmt.Errorf("label %s", strings.Join(msgs, "; label "))
+	}
+	return nil
+}
+
 func runAddLabelsPrompt(ctx context.Context, f fn.Function, saver functionSaver) (err error) {
 
 	insertToIndex := 0
@@ -163,11 +175,9 @@ func runAddLabelsPrompt(ctx context.Context, f fn.Function, saver functionSaver)
 	case optionLabelValue:
 		qs := []*survey.Question{
 			{
-				Name:   "key",
-				Prompt: &survey.Input{Message: "Please specify the label key:"},
-				Validate: func(val interface{}) error {
-					return utils.ValidateLabelKey(val.(string))
-				},
+				Name:     "key",
+				Prompt:   &survey.Input{Message: "Please specify the label key:"},
+				Validate: validateLabelKeyPrompt,
 			},
 			{
 				Name:   "value",
@@ -196,11 +206,9 @@ func runAddLabelsPrompt(ctx context.Context, f fn.Function, saver functionSaver)
 	case optionLabelLocal:
 		qs := []*survey.Question{
 			{
-				Name:   "key",
-				Prompt: &survey.Input{Message: "Please specify the label key:"},
-				Validate: func(val interface{}) error {
-					return utils.ValidateLabelKey(val.(string))
-				},
+				Name:     "key",
+				Prompt:   &survey.Input{Message: "Please specify the label key:"},
+				Validate: validateLabelKeyPrompt,
 			},
 			{
 				Name:   "value",
@@ -232,6 +240,12 @@ func runAddLabelsPrompt(ctx context.Context, f fn.Function, saver functionSaver)
 		newPair.Value = &value
 	}
 
+	// the assembled label must satisfy the Kubernetes label constraints,
+	// including the evaluation of any referenced environment variable
+	if msgs := newPair.Validate(); len(msgs) > 0 {
+		return fmt.Errorf("the label %s", strings.Join(msgs, "; the label "))
+	}
+
 	// we have all necessary information -> let's insert the label to the selected position in the list
 	if insertToIndex == len(f.Deploy.Labels) {
 		f.Deploy.Labels = append(f.Deploy.Labels, newPair)
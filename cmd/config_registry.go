@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func NewConfigRegistryCmd(loadSaver functionLoaderSaver) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry [registry]",
+		Short: "List or set the registry function images are built to",
+		Long: `List or set the registry function images are built to
+
+Prints the registry of a function project present in the current directory
+or from the directory specified with --path.
+
+When a registry is given as an argument, it is persisted as the project's
+default, used whenever the --registry flag and $FUNC_REGISTRY are not
+provided.  Precedence is flag, then environment, then this setting, then the
+platform default.  To revert to flag/environment-only configuration, set an
+empty value (registry "").
+`,
+		SuggestFor: []string{"registries", "registyr"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return runConfigRegistry(cmd, args, loadSaver)
+		},
+	}
+
+	setPathFlag(cmd)
+
+	return cmd
+}
+
+func runConfigRegistry(cmd *cobra.Command, args []string, loadSaver functionLoaderSaver) (err error) {
+	if len(args) > 1 {
+		return errors.New("unexpected extra arguments")
+	}
+
+	function, err := loadSaver.Load(newConfigCmdConfig().Path)
+	if err != nil {
+		return
+	}
+
+	// With no arguments, print the current registry (if any).
+	if len(args) == 0 {
+		if function.Registry == "" {
+			fmt.Fprintln(cmd.OutOrStdout(), "No registry set (provide --registry or $FUNC_REGISTRY when building).")
+			return
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), function.Registry)
+		return
+	}
+
+	// An empty argument clears the setting; anything else must yield a
+	// derivable image name for the function.
+	if args[0] != "" {
+		probe := function
+		probe.Registry = args[0]
+		if _, err = probe.ImageName(); err != nil {
+			return fmt.Errorf("invalid registry '%v': %w", args[0], err)
+		}
+	}
+	function.Registry = args[0]
+	return loadSaver.Save(function)
+}
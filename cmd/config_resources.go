@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewConfigResourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resources",
+		Short: "List and manage resource requests and limits for a function",
+		Long: `List and manage resource requests and limits for a function
+
+Prints the configured CPU and memory requests and limits for a function
+project present in the current directory or from the directory specified
+with --path.
+`,
+		SuggestFor: []string{"resources", "resource", "res"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(defaultLoaderSaver)
+			if err != nil {
+				return
+			}
+
+			listResources(function)
+
+			return
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	configResourcesSetCmd := NewConfigResourcesSetCmd()
+
+	setPathFlag(cmd)
+	setPathFlag(configResourcesSetCmd)
+
+	cmd.AddCommand(configResourcesSetCmd)
+
+	return cmd
+}
+
+func NewConfigResourcesSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Set resource requests and limits for the function",
+		Long: `Set resource requests and limits for the function
+
+Interactive prompt to set the CPU and memory requests and limits of the
+function project in the current directory or from the directory specified
+with --path.  Empty values defer to the platform's defaults.
+`,
+		SuggestFor: []string{"st", "update"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			function, err := initConfigCommand(defaultLoaderSaver)
+			if err != nil {
+				return
+			}
+
+			return runSetResourcesPrompt(function)
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+	return cmd
+}
+
+func listResources(f fn.Function) {
+	r := f.Run.Resources
+	fmt.Println("Configured resources:")
+	fmt.Println(" - requests cpu:   ", orDefault(resourceString(requestsOf(r).CPU)))
+	fmt.Println(" - requests memory:", orDefault(resourceString(requestsOf(r).Memory)))
+	fmt.Println(" - limits cpu:     ", orDefault(resourceString(limitsOf(r).CPU)))
+	fmt.Println(" - limits memory:  ", orDefault(resourceString(limitsOf(r).Memory)))
+}
+
+func requestsOf(r *fn.ResourcesOptions) fn.ResourcesRequestsOptions {
+	if r == nil || r.Requests == nil {
+		return fn.ResourcesRequestsOptions{}
+	}
+	return *r.Requests
+}
+
+func limitsOf(r *fn.ResourcesOptions) fn.ResourcesLimitsOptions {
+	if r == nil || r.Limits == nil {
+		return fn.ResourcesLimitsOptions{}
+	}
+	return *r.Limits
+}
+
+func resourceString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func runSetResourcesPrompt(f fn.Function) (err error) {
+
+	// Quantities must be either empty (defer to the default) or parse as a
+	// Kubernetes resource quantity (e.g. "100m", "128Mi").
+	quantityValidator := func(val interface{}) error {
+		str, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("The value must be a Kubernetes resource quantity.")
+		}
+		if str == "" {
+			return nil
+		}
+		if _, err := resource.ParseQuantity(str); err != nil {
+			return fmt.Errorf("The value must be a Kubernetes resource quantity (e.g. \"100m\", \"128Mi\").")
+		}
+		return nil
+	}
+
+	qs := []*survey.Question{
+		{
+			Name: "requestsCPU",
+			Prompt: &survey.Input{
+				Message: "CPU request (e.g. \"100m\", empty for default):",
+				Default: resourceString(requestsOf(f.Run.Resources).CPU),
+			},
+			Validate: quantityValidator,
+		},
+		{
+			Name: "requestsMemory",
+			Prompt: &survey.Input{
+				Message: "Memory request (e.g. \"128Mi\", empty for default):",
+				Default: resourceString(requestsOf(f.Run.Resources).Memory),
+			},
+			Validate: quantityValidator,
+		},
+		{
+			Name: "limitsCPU",
+			Prompt: &survey.Input{
+				Message: "CPU limit (e.g. \"1000m\", empty for default):",
+				Default: resourceString(limitsOf(f.Run.Resources).CPU),
+			},
+			Validate: quantityValidator,
+		},
+		{
+			Name: "limitsMemory",
+			Prompt: &survey.Input{
+				Message: "Memory limit (e.g. \"256Mi\", empty for default):",
+				Default: resourceString(limitsOf(f.Run.Resources).Memory),
+			},
+			Validate: quantityValidator,
+		},
+	}
+
+	answers := struct {
+		RequestsCPU    string
+		RequestsMemory string
+		LimitsCPU      string
+		LimitsMemory   string
+	}{}
+
+	if err = survey.Ask(qs, &answers); err != nil {
+		if err == terminal.InterruptErr {
+			return nil
+		}
+		return
+	}
+
+	if f.Run.Resources, err = resourcesFromAnswers(
+		answers.RequestsCPU, answers.RequestsMemory,
+		answers.LimitsCPU, answers.LimitsMemory); err != nil {
+		return
+	}
+
+	err = f.Write()
+	if err == nil {
+		fmt.Println("Resource configuration was added to the function configuration")
+	}
+	return
+}
+
+// resourcesFromAnswers converts the given quantity strings (already
+// validated to parse, empty denoting unset) into resource options, erroring
+// when a limit is less than the corresponding request.  Returns nil when no
+// value is given.
+func resourcesFromAnswers(requestsCPU, requestsMemory, limitsCPU, limitsMemory string) (*fn.ResourcesOptions, error) {
+	for _, pair := range []struct {
+		name           string
+		request, limit string
+	}{
+		{"cpu", requestsCPU, limitsCPU},
+		{"memory", requestsMemory, limitsMemory},
+	} {
+		if pair.request == "" || pair.limit == "" {
+			continue
+		}
+		request, err := resource.ParseQuantity(pair.request)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := resource.ParseQuantity(pair.limit)
+		if err != nil {
+			return nil, err
+		}
+		if limit.Cmp(request) < 0 {
+			return nil, fmt.Errorf("%s limit %q must not be less than the request %q", pair.name, pair.limit, pair.request)
+		}
+	}
+
+	resources := &fn.ResourcesOptions{}
+	if requestsCPU != "" || requestsMemory != "" {
+		resources.Requests = &fn.ResourcesRequestsOptions{}
+		if requestsCPU != "" {
+			resources.Requests.CPU = &requestsCPU
+		}
+		if requestsMemory != "" {
+			resources.Requests.Memory = &requestsMemory
+		}
+	}
+	if limitsCPU != "" || limitsMemory != "" {
+		resources.Limits = &fn.ResourcesLimitsOptions{}
+		if limitsCPU != "" {
+			resources.Limits.CPU = &limitsCPU
+		}
+		if limitsMemory != "" {
+			resources.Limits.Memory = &limitsMemory
+		}
+	}
+	if resources.Requests == nil && resources.Limits == nil {
+		return nil, nil
+	}
+	return resources, nil
+}
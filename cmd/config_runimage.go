@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/spf13/cobra"
+)
+
+func NewConfigRunImageCmd(loadSaver functionLoaderSaver) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run-image [image]",
+		Short: "List or set the base image the built function runs on",
+		Long: `List or set the base image the built function runs on
+
+Prints the run image override of a function project present in the current
+directory or from the directory specified with --path.
+
+When an image is given as an argument, the function is updated to use it as
+the base of built images (the final stage base), independent of the builder
+image used to compile the function.  Useful for compliance-approved base
+images.  To revert to the builder's built-in run image, set an empty value
+(run-image "").
+`,
+		SuggestFor: []string{"runimage", "run-iamge"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return runConfigRunImage(cmd, args, loadSaver)
+		},
+	}
+
+	setPathFlag(cmd)
+
+	return cmd
+}
+
+func runConfigRunImage(cmd *cobra.Command, args []string, loadSaver functionLoaderSaver) (err error) {
+	if len(args) > 1 {
+		return errors.New("unexpected extra arguments")
+	}
+
+	function, err := loadSaver.Load(newConfigCmdConfig().Path)
+	if err != nil {
+		return
+	}
+
+	// With no arguments, print the current override (if any).
+	if len(args) == 0 {
+		if function.Build.RunImage == "" {
+			fmt.Fprintln(cmd.OutOrStdout(), "No run image override set (the builder's default is used).")
+			return
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), function.Build.RunImage)
+		return
+	}
+
+	// An empty argument clears the override; anything else must be a valid
+	// image reference.
+	if args[0] != "" {
+		if _, err = name.ParseReference(args[0], name.WeakValidation); err != nil {
+			return fmt.Errorf("invalid run image '%v': %w", args[0], err)
+		}
+	}
+	function.Build.RunImage = args[0]
+	return loadSaver.Save(function)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/kn-plugin-func/utils"
+)
+
+func NewConfigServiceAccountCmd(loadSaver functionLoaderSaver) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service-account [name]",
+		Short: "List or set the service account the deployed function runs as",
+		Long: `List or set the service account the deployed function runs as
+
+Prints the service account of a function project present in the current
+directory or from the directory specified with --path.
+
+When a name is given as an argument, the function is updated to run as that
+Kubernetes service account on deploy.  Useful for functions which access the
+Kubernetes API or rely on identity-based cloud credentials (Workload
+Identity, IRSA).  To revert to the namespace's default service account, set
+an empty value (service-account "").
+`,
+		SuggestFor: []string{"serviceaccount", "sa", "service-acount"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			return runConfigServiceAccount(cmd, args, loadSaver)
+		},
+	}
+
+	setPathFlag(cmd)
+
+	return cmd
+}
+
+func runConfigServiceAccount(cmd *cobra.Command, args []string, loadSaver functionLoaderSaver) (err error) {
+	if len(args) > 1 {
+		return errors.New("unexpected extra arguments")
+	}
+
+	function, err := loadSaver.Load(newConfigCmdConfig().Path)
+	if err != nil {
+		return
+	}
+
+	// With no arguments, print the current service account (if any).
+	if len(args) == 0 {
+		if function.Run.ServiceAccountName == "" {
+			fmt.Fprintln(cmd.OutOrStdout(), "No service account set (the namespace's default is used).")
+			return
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), function.Run.ServiceAccountName)
+		return
+	}
+
+	// An empty argument clears the setting; anything else must be a valid
+	// Kubernetes service account name.
+	if args[0] != "" {
+		if err = utils.ValidateServiceAccountName(args[0]); err != nil {
+			return
+		}
+	}
+	function.Run.ServiceAccountName = args[0]
+	return loadSaver.Save(function)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+func NewConfigShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the effective configuration of a function",
+		Long: `Show the effective configuration of a function
+
+Prints the configuration of the function project present in the current
+directory or from the directory specified with --path, as commands
+interpret it: fully migrated and with defaults applied.  Notes explain
+values which originate from a migration or a default rather than from
+func.yaml itself.  The file is never modified.
+`,
+		SuggestFor: []string{"shwo", "effective", "view"},
+		PreRunE:    bindEnv("path"),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			client, done := NewClient(ClientConfig{Verbose: viper.GetBool("verbose")})
+			defer done()
+
+			function, notes, err := client.EffectiveConfig(getPathFlag())
+			if err != nil {
+				return
+			}
+
+			bb, err := yaml.Marshal(function)
+			if err != nil {
+				return
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(bb))
+
+			if len(notes) > 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "\nNotes:")
+				for _, note := range notes {
+					fmt.Fprintf(cmd.OutOrStdout(), " - %v\n", note)
+				}
+			}
+			return
+		},
+	}
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	setPathFlag(cmd)
+
+	return cmd
+}
@@ -17,13 +17,38 @@ import (
 )
 
 // ErrNoRuntime indicates that the language runtime flag was not passed.
-type ErrNoRuntime error
+// Matchable with errors.Is(err, ErrNoRuntime{}); the verbose user-facing
+// message is carried in the wrapped error.
+type ErrNoRuntime struct{ Err error }
+
+func (e ErrNoRuntime) Error() string { return e.Err.Error() }
+func (e ErrNoRuntime) Unwrap() error { return e.Err }
+func (e ErrNoRuntime) Is(target error) bool {
+	_, ok := target.(ErrNoRuntime)
+	return ok
+}
 
 // ErrInvalidRuntime indicates that the passed language runtime was invalid.
-type ErrInvalidRuntime error
+// Matchable with errors.Is(err, ErrInvalidRuntime{}).
+type ErrInvalidRuntime struct{ Err error }
+
+func (e ErrInvalidRuntime) Error() string { return e.Err.Error() }
+func (e ErrInvalidRuntime) Unwrap() error { return e.Err }
+func (e ErrInvalidRuntime) Is(target error) bool {
+	_, ok := target.(ErrInvalidRuntime)
+	return ok
+}
 
 // ErrInvalidTemplate indicates that the passed template was invalid.
-type ErrInvalidTemplate error
+// Matchable with errors.Is(err, ErrInvalidTemplate{}).
+type ErrInvalidTemplate struct{ Err error }
+
+func (e ErrInvalidTemplate) Error() string { return e.Err.Error() }
+func (e ErrInvalidTemplate) Unwrap() error { return e.Err }
+func (e ErrInvalidTemplate) Is(target error) bool {
+	_, ok := target.(ErrInvalidTemplate)
+	return ok
+}
 
 // NewCreateCmd creates a create command using the given client creator.
 func NewCreateCmd(newClient ClientFactory) *cobra.Command {
@@ -71,14 +96,16 @@ EXAMPLES
 	  $ {{.Name}} create -l go -t cloudevents myfunc
 		`,
 		SuggestFor: []string{"vreate", "creaet", "craete", "new"},
-		PreRunE:    bindEnv("language", "template", "repository", "confirm"),
+		PreRunE:    bindEnv("language", "template", "repository", "confirm", "from", "refresh"),
 	}
 
 	// Flags
 	cmd.Flags().StringP("language", "l", "", "Language Runtime (see help text for list) (Env: $FUNC_LANGUAGE)")
-	cmd.Flags().StringP("template", "t", fn.DefaultTemplate, "Function template. (see help text for list) (Env: $FUNC_TEMPLATE)")
+	cmd.Flags().StringP("template", "t", "", "Function template. (see help text for list) Default is the 'defaultTemplate' global config setting, or 'http'. (Env: $FUNC_TEMPLATE)")
 	cmd.Flags().StringP("repository", "r", "", "URI to a Git repository containing the specified template (Env: $FUNC_REPOSITORY)")
 	cmd.Flags().BoolP("confirm", "c", false, "Prompt to confirm all options interactively (Env: $FUNC_CONFIRM)")
+	cmd.Flags().StringP("from", "", "", "Path to an existing function to clone as the starting point, instead of a template (Env: $FUNC_FROM)")
+	cmd.Flags().BoolP("refresh", "", false, "Re-fetch the template repository specified with --repository even when a cached clone is available (Env: $FUNC_REFRESH)")
 
 	// Help Action
 	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) { runCreateHelp(cmd, args, newClient) })
@@ -109,12 +136,27 @@ func runCreate(cmd *cobra.Command, args []string, newClient ClientFactory) (err
 		return
 	}
 
+	// Clone an existing function as the starting point rather than writing
+	// template files when --from was provided.
+	if cfg.From != "" {
+		client, done := newClient(ClientConfig{Verbose: cfg.Verbose})
+		defer done()
+		if err = client.Clone(cfg.From, cfg.Path, cfg.Name); err != nil {
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStderr(), "Created function in %v (cloned from %v)\n", cfg.Path, cfg.From)
+		return
+	}
+
 	// Client
 	// From environment variables, flags, arguments, and user prompts if --confirm
 	// (in increasing levels of precidence)
 	client, done := newClient(ClientConfig{Verbose: cfg.Verbose},
 		fn.WithRepository(cfg.Repository),             // Use exactly this repo OR
-		fn.WithRepositoriesPath(cfg.RepositoriesPath)) // Path on disk to installed repos
+		fn.WithRepositoriesPath(cfg.RepositoriesPath), // Path on disk to installed repos
+		fn.WithRepositoryRefresh(cfg.Refresh),         // Bypass any cached clone
+		fn.WithDefaultTemplate(cfg.DefaultTemplate),   // Template when none specified
+		fn.WithRunPostCreate(interactiveTerminal()))   // Template hooks when interactive
 	defer done()
 
 	// Validate - a deeper validation than that which is performed when
@@ -195,6 +237,14 @@ type createConfig struct {
 	// Name of the function
 	Name string
 
+	// From is an optional path to an existing function to clone as the
+	// starting point, rather than creating from a template.
+	From string
+
+	// Refresh requests the template repository specified with --repository
+	// be re-fetched even when a cached clone is available.
+	Refresh bool
+
 	// RepositoriesPath is an optional configuration setting (not set via flags)
 	// which overrides the location on disk at which to search for installed
 	// repositories.
@@ -202,6 +252,12 @@ type createConfig struct {
 	// Default value is $XDG_CONFIG_HOME/func/repositories
 	// (~/.config/func/repositories)
 	RepositoriesPath string
+
+	// DefaultTemplate is an optional configuration setting (not set via flags)
+	// sourced from global config ($XDG_CONFIG_HOME/func/config.yaml) which is
+	// used as the template when none was specified.  The static default
+	// (fn.DefaultTemplate) applies when this too is empty.
+	DefaultTemplate string
 }
 
 // newCreateConfig returns a config populated from the current execution context
@@ -235,15 +291,26 @@ func newCreateConfig(cmd *cobra.Command, args []string, newClient ClientFactory)
 		repositoriesPath = fn.New().RepositoriesPath() // use ~/.config/func/repositories
 	}
 
+	// Global Config
+	// Supplies defaults not expressed as flags, such as the template to use
+	// when none is specified via flag or environment variable.
+	globalCfg, err := fn.NewConfig()
+	if err != nil {
+		return
+	}
+
 	// Config is the final default values based off the execution context.
 	// When prompting, these become the defaults presented.
 	cfg = createConfig{
 		Name:             dirName, // TODO: refactor to be git-like
 		Path:             absolutePath,
 		RepositoriesPath: repositoriesPath,
+		DefaultTemplate:  globalCfg.DefaultTemplate,
 		Repository:       viper.GetString("repository"),
 		Runtime:          viper.GetString("language"), // users refer to it is language
 		Template:         viper.GetString("template"),
+		From:             viper.GetString("from"),
+		Refresh:          viper.GetBool("refresh"),
 		Confirm:          viper.GetBool("confirm"),
 		Verbose:          viper.GetBool("verbose"),
 	}
@@ -341,9 +408,16 @@ func (c createConfig) Validate(client *fn.Client) (err error) {
 		return newInvalidRuntimeError(client, c.Runtime)
 	}
 
-	if c.Template != "" && c.Repository == "" &&
-		!isValidTemplate(client, c.Runtime, c.Template) {
-		return newInvalidTemplateError(client, c.Runtime, c.Template)
+	// The effective template is the one provided via flag or environment
+	// variable, or the global config's default when unset (the static default
+	// fn.DefaultTemplate, which then applies, is always valid).
+	template := c.Template
+	if template == "" {
+		template = c.DefaultTemplate
+	}
+	if template != "" && c.Repository == "" &&
+		!isValidTemplate(client, c.Runtime, template) {
+		return newInvalidTemplateError(client, c.Runtime, template)
 	}
 
 	return
@@ -394,7 +468,7 @@ func noRuntimeError(client *fn.Client) error {
 	for _, v := range runtimes {
 		fmt.Fprintf(&b, "  %v\n", v)
 	}
-	return ErrNoRuntime(errors.New(b.String()))
+	return ErrNoRuntime{Err: errors.New(b.String())}
 }
 
 // newInvalidRuntimeError creates an error stating that the given language
@@ -410,7 +484,7 @@ func newInvalidRuntimeError(client *fn.Client, runtime string) error {
 	for _, v := range runtimes {
 		fmt.Fprintf(&b, "  %v\n", v)
 	}
-	return ErrInvalidRuntime(errors.New(b.String()))
+	return ErrInvalidRuntime{Err: errors.New(b.String())}
 }
 
 // newInvalidTemplateError creates an error stating that the given template
@@ -427,7 +501,7 @@ func newInvalidTemplateError(client *fn.Client, runtime, template string) error
 	for _, v := range templates {
 		fmt.Fprintf(&b, "  %v\n", v)
 	}
-	return ErrInvalidTemplate(errors.New(b.String()))
+	return ErrInvalidTemplate{Err: errors.New(b.String())}
 }
 
 // prompt the user with value of config members, allowing for interactively
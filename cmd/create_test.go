@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"errors"
+	"os"
 	"path/filepath"
 	"testing"
 
+	fn "knative.dev/kn-plugin-func"
 	. "knative.dev/kn-plugin-func/testing"
 	"knative.dev/kn-plugin-func/utils"
 )
@@ -107,3 +109,64 @@ func TestCreateConfig_RepositoriesPath(t *testing.T) {
 		t.Fatalf("expected repositories default path to be '%v', got '%v'", expected, cfg.RepositoriesPath)
 	}
 }
+
+// TestCreate_DefaultTemplateConfigurable ensures that an invocation of create
+// without an explicit template uses the default template configured in the
+// global config file.
+func TestCreate_DefaultTemplateConfigurable(t *testing.T) {
+	defer Fromtemp(t)()
+
+	// Write a global config specifying 'cloudevents' as the default template
+	// at the expected location within XDG_CONFIG_HOME.
+	xdgConfigHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdgConfigHome)
+	if err := os.MkdirAll(filepath.Join(xdgConfigHome, "func"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	err := os.WriteFile(filepath.Join(xdgConfigHome, "func", fn.ConfigFile),
+		[]byte("defaultTemplate: cloudevents\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create without specifying a template
+	cmd := NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--language", "go"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The configured default, rather than the static default "http", should
+	// have been used, as evidenced by the resultant invocation format.
+	f, err := fn.NewFunction(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Invocation.Format != "cloudevent" {
+		t.Fatalf("expected the 'cloudevents' template (invocation format 'cloudevent'), got format '%v'", f.Invocation.Format)
+	}
+}
+
+// TestCreate_ErrorsMatchable ensures the create command's errors can be
+// matched programmatically with errors.Is, in addition to errors.As.
+func TestCreate_ErrorsMatchable(t *testing.T) {
+	defer Fromtemp(t)()
+
+	cmd := NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); !errors.Is(err, ErrNoRuntime{}) {
+		t.Fatalf("expected error matching ErrNoRuntime, got %v", err)
+	}
+
+	cmd = NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--language", "invalid"})
+	if err := cmd.Execute(); !errors.Is(err, ErrInvalidRuntime{}) {
+		t.Fatalf("expected error matching ErrInvalidRuntime, got %v", err)
+	}
+
+	cmd = NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--language", "go", "--template", "invalid"})
+	if err := cmd.Execute(); !errors.Is(err, ErrInvalidTemplate{}) {
+		t.Fatalf("expected error matching ErrInvalidTemplate, got %v", err)
+	}
+}
@@ -101,7 +101,7 @@ EXAMPLES
 
 `,
 		SuggestFor: []string{"delpoy", "deplyo"},
-		PreRunE:    bindEnv("confirm", "env", "git-url", "git-branch", "git-dir", "remote", "build", "builder", "builder-image", "image", "registry", "push", "platform", "path", "namespace"),
+		PreRunE:    bindEnv("confirm", "env", "git-url", "git-branch", "git-dir", "remote", "build", "builder", "builder-image", "image", "registry", "push", "platform", "path", "namespace", "pull-policy", "min-scale", "scale-to-zero", "output-manifest", "traffic"),
 	}
 
 	cmd.Flags().BoolP("confirm", "c", false, "Prompt to confirm all configuration options (Env: $FUNC_CONFIRM)")
@@ -122,7 +122,14 @@ EXAMPLES
 	cmd.Flags().StringP("registry", "r", GetDefaultRegistry(), "Registry + namespace part of the image to build, ex 'ghcr.io/myuser'.  The full image name is automatically determined. (Env: $FUNC_REGISTRY)")
 	cmd.Flags().BoolP("push", "u", true, "Push the function image to registry before deploying (Env: $FUNC_PUSH)")
 	cmd.Flags().StringP("platform", "", "", "Target platform to build (e.g. linux/amd64).")
+	cmd.Flags().StringArrayP("build-arg", "", []string{}, "Build argument to pass to the build tool in the form KEY=VALUE. "+
+		"You may provide this flag multiple times.  Unlike --build-env these are build-tool directives, not container environment variables.")
 	cmd.Flags().StringP("namespace", "n", "", "Deploy into a specific namespace. (Env: $FUNC_NAMESPACE)")
+	cmd.Flags().StringP("pull-policy", "", "", "Image pull policy applied to the deployed function. [Always|IfNotPresent|Never]. The platform's default is used when not set. (Env: $FUNC_PULL_POLICY)")
+	cmd.Flags().Int64P("min-scale", "", 0, "Minimum number of replicas to retain for the deployed function (Env: $FUNC_MIN_SCALE)")
+	cmd.Flags().BoolP("scale-to-zero", "", true, "Allow the deployed function to scale to zero replicas when idle.  Disabling requires --min-scale of at least 1 (Env: $FUNC_SCALE_TO_ZERO)")
+	cmd.Flags().StringP("output-manifest", "", "", "Write the deployment manifest which would be applied to the given file instead of deploying.  The function is still built and pushed such that the manifest references the resolved image. (Env: $FUNC_OUTPUT_MANIFEST)")
+	cmd.Flags().IntP("traffic", "", 100, "Percent of traffic to route to the newly deployed revision, with the remainder pinned to the previous revision; a canary rollout. (Env: $FUNC_TRAFFIC)")
 	setPathFlag(cmd)
 
 	if err := cmd.RegisterFlagCompletionFunc("builder", CompleteBuilderList); err != nil {
@@ -175,8 +182,9 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 	if !f.Initialized() {
 		return fmt.Errorf("'%v' does not contain an initialized function", config.Path)
 	}
-	if f.Registry == "" || cmd.Flags().Changed("registry") {
-		// Sets default AND accepts any user-provided overrides
+	if f.Registry == "" || cmd.Flags().Changed("registry") || os.Getenv("FUNC_REGISTRY") != "" {
+		// Sets default AND accepts any user-provided overrides, with the
+		// flag and environment taking precedence over the persisted registry
 		f.Registry = config.Registry
 	}
 	if f.Build.Builder == "" || cmd.Flags().Changed("builder") {
@@ -209,6 +217,44 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 	if config.GitDir != "" {
 		f.Build.Git.ContextDir = config.GitDir
 	}
+	buildArgs, err := buildArgsFromCmd(cmd)
+	if err != nil {
+		return
+	}
+	if len(buildArgs) > 0 {
+		if f.Build.BuildArgs == nil {
+			f.Build.BuildArgs = make(map[string]string, len(buildArgs))
+		}
+		for k, v := range buildArgs {
+			f.Build.BuildArgs[k] = v
+		}
+	}
+	if cmd.Flags().Changed("pull-policy") {
+		f.Run.ImagePullPolicy = config.PullPolicy
+	}
+	if cmd.Flags().Changed("min-scale") || cmd.Flags().Changed("scale-to-zero") {
+		if f.Deploy.Options.Scale == nil {
+			f.Deploy.Options.Scale = &fn.ScaleOptions{}
+		}
+		if cmd.Flags().Changed("min-scale") {
+			f.Deploy.Options.Scale.Min = &config.MinScale
+		}
+		if cmd.Flags().Changed("scale-to-zero") {
+			f.Deploy.Options.Scale.ScaleToZero = &config.ScaleToZero
+		}
+	}
+	// Keeping a warm replica is effected via the minimum scale, so disabling
+	// scale to zero requires one be pinned.
+	if scale := f.Deploy.Options.Scale; scale != nil && scale.ScaleToZero != nil && !*scale.ScaleToZero {
+		if scale.Min == nil || *scale.Min < 1 {
+			return errors.New("disabling scale to zero requires a minimum scale of at least 1 (--min-scale)")
+		}
+	}
+	// A digest-pinned image is immutable; pulling it anew can never yield a
+	// different image, so Always is unnecessary.
+	if f.ImageDigest != "" && f.Run.ImagePullPolicy == "Always" {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Warning: pull policy 'Always' is unnecessary when deploying an image pinned by digest.")
+	}
 
 	f.Deploy.Namespace = namespace(config, f, cmd.ErrOrStderr())
 	if err != nil {
@@ -240,13 +286,23 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 			s2i.WithPlatform(config.Platform),
 			s2i.WithVerbose(config.Verbose))
 	} else {
-		err = fmt.Errorf("builder '%v' is not recognized", f.Build.Builder)
-		return
+		// Not a built-in builder; delegate to the third-party builder plugin
+		// of that name (a func-builder-<name> executable on PATH), whose
+		// presence was confirmed by ValidateBuilder above.
+		builder = builders.NewExecBuilder(f.Build.Builder, config.Verbose)
 	}
 
-	client, done := newClient(ClientConfig{Namespace: f.Deploy.Namespace, Verbose: config.Verbose},
+	clientOptions := []fn.Option{
 		fn.WithRegistry(config.Registry),
-		fn.WithBuilder(builder))
+		fn.WithBuilder(builder),
+	}
+	// A canary rollout: route only the requested share of traffic to the
+	// new revision.
+	if cmd.Flags().Changed("traffic") {
+		clientOptions = append(clientOptions, fn.WithDeployOptions(fn.DeployOptions{TrafficPercent: config.Traffic}))
+	}
+	client, done := newClient(ClientConfig{Namespace: f.Deploy.Namespace, Verbose: config.Verbose},
+		clientOptions...)
 	defer done()
 
 	// Default Client Registry, Function Registry or explicit Image required
@@ -281,7 +337,8 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 		if err = f.Write(); err != nil { // TODO: remove when client API uses 'f'
 			return
 		}
-		if build(config.Build, f, client) { // --build or "auto" with FS changes
+		// A digest-pinned image deploys as-is; "auto" build is skipped.
+		if config.ImageDigest == "" && build(config.Build, f, client) { // --build or "auto" with FS changes
 			if err = client.Build(cmd.Context(), f.Root); err != nil {
 				return
 			}
@@ -294,7 +351,20 @@ func runDeploy(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 				return
 			}
 		}
-		if err = client.Deploy(cmd.Context(), f.Root); err != nil {
+		// When a manifest output path is given, write the manifest which
+		// would be applied rather than contacting the cluster.
+		if config.OutputManifest != "" {
+			var manifest []byte
+			if manifest, err = client.Manifest(cmd.Context(), f.Root); err != nil {
+				return
+			}
+			if err = os.WriteFile(config.OutputManifest, manifest, 0644); err != nil {
+				return
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Manifest written to %v\n", config.OutputManifest)
+			return f.Write()
+		}
+		if _, err = client.Deploy(cmd.Context(), f.Root); err != nil {
 			return
 		}
 		if f, err = fn.NewFunction(f.Root); err != nil { // TODO: remove when client API uses 'f'
@@ -350,6 +420,11 @@ func ValidateBuilder(name string) (err error) {
 			return
 		}
 	}
+	// Builder plugins (func-builder-<name> executables on PATH) extend the
+	// set of built-in builders without recompiling.
+	if builders.PluginInstalled(name) {
+		return
+	}
 	return builders.ErrUnknownBuilder{Name: name, Known: KnownBuilders()}
 }
 
@@ -519,6 +594,24 @@ type deployConfig struct {
 
 	// ImageDigest is automatically split off an --image tag
 	ImageDigest string
+
+	// PullPolicy applied to the deployed function's image
+	// (Always, IfNotPresent or Never).  Platform default when empty.
+	PullPolicy string
+
+	// MinScale is the minimum number of replicas to retain.
+	MinScale int64
+
+	// ScaleToZero, when explicitly disabled, keeps at least one replica warm.
+	ScaleToZero bool
+
+	// OutputManifest is an optional file path to which the deployment
+	// manifest is written instead of being applied to the cluster.
+	OutputManifest string
+
+	// Traffic is the percent of traffic to route to the new revision,
+	// with the remainder pinned to the previous one (canary rollout).
+	Traffic int
 }
 
 // newDeployConfig creates a buildConfig populated from command flags and
@@ -530,22 +623,33 @@ func newDeployConfig(cmd *cobra.Command) (deployConfig, error) {
 	}
 
 	c := deployConfig{
-		buildConfig: newBuildConfig(),
-		Build:       viper.GetString("build"),
-		Remote:      viper.GetBool("remote"),
-		Namespace:   viper.GetString("namespace"),
-		EnvToUpdate: envToUpdate,
-		EnvToRemove: envToRemove,
-		GitURL:      viper.GetString("git-url"),
-		GitBranch:   viper.GetString("git-branch"),
-		GitDir:      viper.GetString("git-dir"),
-		ImageDigest: "", // automatically split off --image if provided below
-	}
-
-	if c.Image, c.ImageDigest, err = parseImage(c.Image); err != nil {
+		buildConfig:    newBuildConfig(),
+		Build:          viper.GetString("build"),
+		Remote:         viper.GetBool("remote"),
+		Namespace:      viper.GetString("namespace"),
+		EnvToUpdate:    envToUpdate,
+		EnvToRemove:    envToRemove,
+		GitURL:         viper.GetString("git-url"),
+		GitBranch:      viper.GetString("git-branch"),
+		GitDir:         viper.GetString("git-dir"),
+		ImageDigest:    "", // automatically split off --image if provided below
+		PullPolicy:     viper.GetString("pull-policy"),
+		MinScale:       viper.GetInt64("min-scale"),
+		ScaleToZero:    viper.GetBool("scale-to-zero"),
+		OutputManifest: viper.GetString("output-manifest"),
+		Traffic:        viper.GetInt("traffic"),
+	}
+
+	if c.Image, c.ImageDigest, err = fn.ParseImage(c.Image); err != nil {
 		return c, err
 	}
 
+	// A digest-pinned image is externally built; skip the push phase unless
+	// it was explicitly requested (in which case Validate errors).
+	if c.ImageDigest != "" && !cmd.Flags().Changed("push") {
+		c.Push = false
+	}
+
 	return c, nil
 }
 
@@ -640,31 +744,30 @@ func (c deployConfig) Validate() (err error) {
 		return fmt.Errorf("invalid --git-url '%v'", c.GitURL)
 	}
 
-	// --build can be "auto"|true|false
-	if c.Build != "auto" {
-		if _, err := strconv.ParseBool(c.Build); err != nil {
-			return fmt.Errorf("unrecognized value for --build '%v'.  accepts 'auto', 'true' or 'false' (or similarly truthy value)", c.Build)
-		}
+	// --min-scale must not be negative
+	if c.MinScale < 0 {
+		return fmt.Errorf("invalid value for --min-scale '%v'.  must not be negative", c.MinScale)
 	}
 
-	return
-}
-
-func parseImage(v string) (name, digest string, err error) {
-	vv := strings.Split(v, "@")
-	if len(vv) < 2 {
-		name = v
-		return
+	// --pull-policy can be Always, IfNotPresent or Never
+	switch c.PullPolicy {
+	case "", "Always", "IfNotPresent", "Never":
+		// valid
+	default:
+		return fmt.Errorf("unrecognized value for --pull-policy '%v'.  accepts 'Always', 'IfNotPresent' or 'Never'", c.PullPolicy)
 	}
-	name = vv[0]
-	digest = vv[1]
 
-	if !strings.HasPrefix(digest, "sha256:") {
-		return v, "", fmt.Errorf("image '%s' has an invalid prefix syntax for digest (should be 'sha256:')", v)
+	// --output-manifest renders locally and is thus incompatible with
+	// remote deployment
+	if c.OutputManifest != "" && c.Remote {
+		return errors.New("--output-manifest is not supported with --remote")
 	}
 
-	if len(digest[7:]) != 64 {
-		return v, "", fmt.Errorf("sha256 hash in '%s' has the wrong length (%d), should be 64", digest, len(digest[7:]))
+	// --build can be "auto"|true|false
+	if c.Build != "auto" {
+		if _, err := strconv.ParseBool(c.Build); err != nil {
+			return fmt.Errorf("unrecognized value for --build '%v'.  accepts 'auto', 'true' or 'false' (or similarly truthy value)", c.Build)
+		}
 	}
 
 	return
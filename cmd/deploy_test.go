@@ -1022,3 +1022,49 @@ func TestDeploy_NamespaceRedeployWarning(t *testing.T) {
 		t.Fatalf("expected function to be updated with namespace 'myns'.  got '%v'", f.Deploy.Namespace)
 	}
 }
+
+// TestDeploy_ImageWithDigest ensures that providing --image with a pinned
+// digest deploys the given image as-is: the build and push phases are
+// skipped, and explicitly requesting either is an error.
+func TestDeploy_ImageWithDigest(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	ref := "example.com/alice/myfunc@sha256:" + strings.Repeat("a", 64)
+
+	if err := fn.New().Create(fn.Function{Runtime: "go", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := mock.NewBuilder()
+	pusher := mock.NewPusher()
+	deployer := mock.NewDeployer()
+
+	cmd := NewDeployCmd(NewClientFactory(func() *fn.Client {
+		return fn.New(
+			fn.WithBuilder(builder),
+			fn.WithPusher(pusher),
+			fn.WithDeployer(deployer))
+	}))
+	cmd.SetArgs([]string{"--image", ref})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if builder.BuildInvoked {
+		t.Fatal("build should be skipped when deploying an image with digest")
+	}
+	if pusher.PushInvoked {
+		t.Fatal("push should be skipped when deploying an image with digest")
+	}
+	if !deployer.DeployInvoked {
+		t.Fatal("deployer was not invoked")
+	}
+
+	// Explicitly requesting a push alongside a digest-pinned image is
+	// contradictory and should error.
+	cmd.SetArgs([]string{"--image", ref, "--push"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected explicit --push with a digest-pinned image to error")
+	}
+}
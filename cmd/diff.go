@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+)
+
+func NewDiffCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare local configuration with the deployed function",
+		Long: `Compare local configuration with the deployed function
+
+Prints the differences between the configuration of the function project in
+the current directory (or from the directory specified with --path) and its
+currently deployed instance: image, environment variables, labels, scaling
+settings and volume mounts.  Useful for checking what a deploy would change
+in shared environments.
+`,
+		Example: `
+# Show what deploying the function in the current directory would change
+{{.Name}} diff
+`,
+		SuggestFor:   []string{"dif", "compare", "changes"},
+		PreRunE:      bindEnv("path"),
+		SilenceUsage: true, // no usage dump on error
+	}
+
+	setPathFlag(cmd)
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runDiff(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, _ []string, newClient ClientFactory) (err error) {
+	var (
+		path      = getPathFlag()
+		namespace = viper.GetString("namespace")
+		verbose   = viper.GetBool("verbose")
+	)
+
+	client, done := newClient(ClientConfig{Namespace: namespace, Verbose: verbose})
+	defer done()
+
+	diff, err := client.Diff(cmd.Context(), path)
+	if err != nil {
+		return
+	}
+	fmt.Fprint(cmd.OutOrStdout(), diff)
+	return
+}
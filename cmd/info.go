@@ -68,10 +68,22 @@ func runInfo(cmd *cobra.Command, args []string, newClient ClientFactory) (err er
 	client, done := newClient(ClientConfig{Namespace: config.Namespace, Verbose: config.Verbose})
 	defer done()
 
-	// Get the description
+	// Get the description, falling back to the locally recorded last
+	// deployment when the cluster cannot be queried.
 	d, err := client.Info(cmd.Context(), config.Name, config.Path)
 	if err != nil {
-		return
+		stamp, stampErr := client.DeployStamp(config.Path)
+		if stampErr != nil {
+			return // return the original error; the stamp is best-effort
+		}
+		fmt.Fprintf(os.Stderr, "Warning: unable to query the cluster (%v); showing the last recorded deployment\n", err)
+		d = fn.Instance{
+			Name:      config.Name,
+			Route:     stamp.URL,
+			Routes:    []string{stamp.URL},
+			Namespace: stamp.Namespace,
+		}
+		err = nil
 	}
 	d.Image = function.Image
 
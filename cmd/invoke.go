@@ -53,6 +53,8 @@ DESCRIPTION
 	    {{.Name}} invoke --target=remote
 	  To target an arbitrary endpoint, provide a URL:
 	    {{.Name}} invoke --target=https://myfunction.example.com
+	  To target a specific revision or traffic tag of the deployed function:
+	    {{.Name}} invoke --target=revision:myfunction-00003
 
 	Invocation Data
 	  Providing a filename in the --file flag will base64 encode its contents
@@ -108,7 +110,7 @@ EXAMPLES
 	// Flags
 	setPathFlag(cmd)
 	cmd.Flags().StringP("format", "f", "", "Format of message to send, 'http' or 'cloudevent'.  Default is to choose automatically. (Env: $FUNC_FORMAT)")
-	cmd.Flags().StringP("target", "t", "", "Function instance to invoke.  Can be 'local', 'remote' or a URL.  Defaults to auto-discovery if not provided. (Env: $FUNC_TARGET)")
+	cmd.Flags().StringP("target", "t", "", "Function instance to invoke.  Can be 'local', 'remote', 'revision:<name-or-tag>' or a URL.  Defaults to auto-discovery if not provided. (Env: $FUNC_TARGET)")
 	cmd.Flags().StringP("id", "", "", "ID for the request data. (Env: $FUNC_ID)")
 	cmd.Flags().StringP("source", "", fn.DefaultInvokeSource, "Source value for the request data. (Env: $FUNC_SOURCE)")
 	cmd.Flags().StringP("type", "", fn.DefaultInvokeType, "Type value for the request data. (Env: $FUNC_TYPE)")
@@ -35,10 +35,11 @@ Lists all deployed functions in a given namespace.
 {{.Name}} list --all-namespaces --output json
 `,
 		SuggestFor: []string{"ls", "lsit"},
-		PreRunE:    bindEnv("all-namespaces", "output"),
+		PreRunE:    bindEnv("all-namespaces", "output", "runtime"),
 	}
 
 	cmd.Flags().BoolP("all-namespaces", "A", false, "List functions in all namespaces. If set, the --namespace flag is ignored.")
+	cmd.Flags().StringP("runtime", "r", "", "List only functions of the given language runtime (Env: $FUNC_RUNTIME)")
 	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json|xml|yaml) (Env: $FUNC_OUTPUT)")
 
 	if err := cmd.RegisterFlagCompletionFunc("output", CompleteOutputFormatList); err != nil {
@@ -64,21 +65,27 @@ func runList(cmd *cobra.Command, _ []string, newClient ClientFactory) (err error
 	client, done := newClient(ClientConfig{Namespace: config.Namespace, Verbose: config.Verbose})
 	defer done()
 
-	items, err := client.List(cmd.Context())
+	items, err := client.List(cmd.Context(), fn.ListOptions{Runtime: config.Runtime})
 	if err != nil {
 		return
 	}
 
 	if len(items) == 0 {
-		// TODO(lkingland): this isn't particularly script friendly.  Suggest this
-		// prints bo only on --verbose.  Possible future tweak, as I don't want to
-		// make functional changes during a refactor.
-		if config.Namespace != "" && !config.AllNamespaces {
-			fmt.Printf("no functions found in namespace '%v'\n", config.Namespace)
-		} else {
-			fmt.Println("no functions found")
+		// Machine-readable formats emit an empty list ('[]', not 'null') so
+		// that output remains parseable by scripts; the human-oriented
+		// formats retain the friendly message.
+		if config.Output == "human" || config.Output == "plain" {
+			// TODO(lkingland): this isn't particularly script friendly.  Suggest this
+			// prints bo only on --verbose.  Possible future tweak, as I don't want to
+			// make functional changes during a refactor.
+			if config.Namespace != "" && !config.AllNamespaces {
+				fmt.Printf("no functions found in namespace '%v'\n", config.Namespace)
+			} else {
+				fmt.Println("no functions found")
+			}
+			return
 		}
-		return
+		items = []fn.ListItem{}
 	}
 
 	write(os.Stdout, listItems(items), config.Output)
@@ -92,6 +99,7 @@ func runList(cmd *cobra.Command, _ []string, newClient ClientFactory) (err error
 type listConfig struct {
 	Namespace     string
 	Output        string
+	Runtime       string
 	AllNamespaces bool
 	Verbose       bool
 }
@@ -100,6 +108,7 @@ func newListConfig() listConfig {
 	return listConfig{
 		Namespace:     viper.GetString("namespace"),
 		Output:        viper.GetString("output"),
+		Runtime:       viper.GetString("runtime"),
 		AllNamespaces: viper.GetBool("all-namespaces"),
 		Verbose:       viper.GetBool("verbose"),
 	}
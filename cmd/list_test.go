@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/mock"
+)
+
+// TestList_OutputEmptyJSON ensures that listing zero functions with a
+// machine-readable output format emits an empty list ('[]', not 'null' and
+// not a human-oriented message) for script consumption.
+func TestList_OutputEmptyJSON(t *testing.T) {
+	lister := mock.NewLister()
+	lister.ListFn = func(fn.ListOptions) ([]fn.ListItem, error) {
+		return nil, nil
+	}
+
+	cmd := NewListCmd(NewClientFactory(func() *fn.Client {
+		return fn.New(fn.WithLister(lister))
+	}))
+	cmd.SetArgs([]string{"--output=json"})
+
+	stdout := piped(t)
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if output := stdout(); output != "[]" {
+		t.Fatalf("expected '[]', got '%v'", output)
+	}
+	if !lister.ListInvoked {
+		t.Fatal("lister was not invoked")
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewMigrateCmd(loadSaver functionLoaderSaver) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate a function's configuration to the latest version",
+		Long: `Migrate a function's configuration to the latest version
+
+Applies any pending migrations to the func.yaml of the function project in
+the current directory or from the directory specified with --path, rewriting
+it in the latest schema.  Use --dry-run to print the migrations which would
+be applied without modifying the file.
+`,
+		Example: `
+# Show which migrations would be applied to the function in the current directory
+{{.Name}} migrate --dry-run
+
+# Migrate the function's configuration to the latest version
+{{.Name}} migrate
+`,
+		SuggestFor: []string{"migrat", "upgrade"},
+		PreRunE:    bindEnv("path", "dry-run"),
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Print the migrations which would be applied without modifying func.yaml (Env: $FUNC_DRY_RUN)")
+	setPathFlag(cmd)
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runMigrate(cmd, args, loadSaver)
+	}
+
+	return cmd
+}
+
+func runMigrate(cmd *cobra.Command, args []string, loadSaver functionLoaderSaver) (err error) {
+	config := newMigrateConfig()
+
+	// Loading a function applies migrations in memory, so the plan must be
+	// calculated from the spec version as written on disk.
+	plan, err := migrationPlanAtPath(config.Path)
+	if err != nil {
+		return
+	}
+
+	if len(plan) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Function is up to date (specVersion %v)\n", fn.LastSpecVersion())
+		return
+	}
+
+	if config.DryRun {
+		fmt.Fprintln(cmd.OutOrStdout(), "Migrations which would be applied:")
+	} else {
+		fmt.Fprintln(cmd.OutOrStdout(), "Applying migrations:")
+	}
+	for _, step := range plan {
+		fmt.Fprintf(cmd.OutOrStdout(), " - %v: %v\n", step.Version, step.Description)
+	}
+
+	if config.DryRun {
+		return
+	}
+
+	// Loading migrates; saving persists the result.
+	function, err := loadSaver.Load(config.Path)
+	if err != nil {
+		return
+	}
+	return loadSaver.Save(function)
+}
+
+// migrationPlanAtPath returns the migrations pending for the function at the
+// given path, calculated from its serialized (pre-migration) spec version.
+func migrationPlanAtPath(path string) ([]fn.MigrationStep, error) {
+	filename := filepath.Join(path, fn.FunctionFile)
+	bb, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("the given path '%v' does not contain an initialized function", path)
+		}
+		return nil, err
+	}
+	// Only the spec version is needed; older schemas may fail to unmarshal
+	// into the current Function struct entirely.
+	onDisk := struct {
+		SpecVersion string `yaml:"specVersion"`
+	}{}
+	if err = yaml.Unmarshal(bb, &onDisk); err != nil {
+		return nil, err
+	}
+	return fn.Function{SpecVersion: onDisk.SpecVersion}.MigrationPlan()
+}
+
+type migrateConfig struct {
+	Path    string
+	DryRun  bool
+	Verbose bool
+}
+
+func newMigrateConfig() migrateConfig {
+	return migrateConfig{
+		Path:    getPathFlag(),
+		DryRun:  viper.GetBool("dry-run"),
+		Verbose: viper.GetBool("verbose"),
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+)
+
+func NewRepairCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Repair a corrupted func.yaml",
+		Long: `Repair a corrupted func.yaml
+
+Attempts to reconstruct a usable func.yaml for the function in the current
+directory (or from the directory specified with --path) when the file has
+been malformed, for example by a bad merge or a truncated write.  As much of
+the file as possible is retained, missing required values are defaulted,
+migrations are applied, and remaining invalid values are reset.  The repairs
+which would be made are listed; nothing is written unless --write is
+provided.
+`,
+		Example: `
+# Show the repairs which would be made to the function in the current directory
+{{.Name}} repair
+
+# Apply them, rewriting func.yaml
+{{.Name}} repair --write
+`,
+		SuggestFor:   []string{"repiar", "fix", "recover"},
+		PreRunE:      bindEnv("path", "write"),
+		SilenceUsage: true, // no usage dump on error
+	}
+
+	cmd.Flags().BoolP("write", "w", false, "Write the repaired func.yaml back to disk (Env: $FUNC_WRITE)")
+	setPathFlag(cmd)
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runRepair(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func runRepair(cmd *cobra.Command, _ []string, newClient ClientFactory) (err error) {
+	var (
+		path    = getPathFlag()
+		write   = viper.GetBool("write")
+		verbose = viper.GetBool("verbose")
+	)
+
+	client, done := newClient(ClientConfig{Verbose: verbose})
+	defer done()
+
+	f, repaired, err := client.Repair(path)
+	if err != nil {
+		return
+	}
+
+	if len(repaired) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "func.yaml is healthy; no repairs necessary")
+		return
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "Repairs:")
+	for _, r := range repaired {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %v\n", r)
+	}
+
+	if !write {
+		fmt.Fprintln(cmd.OutOrStdout(), "Nothing written (use --write to persist the repaired func.yaml)")
+		return
+	}
+	if err = client.WriteRepaired(f); err != nil {
+		return
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Wrote repaired func.yaml")
+	return
+}
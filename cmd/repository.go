@@ -152,6 +152,8 @@ EXAMPLES
 	cmd.AddCommand(NewRepositoryAddCmd(newClient))
 	cmd.AddCommand(NewRepositoryRenameCmd(newClient))
 	cmd.AddCommand(NewRepositoryRemoveCmd(newClient))
+	cmd.AddCommand(NewRepositoryUpdateCmd(newClient))
+	cmd.AddCommand(NewRepositoryPruneCmd(newClient))
 
 	return cmd
 }
@@ -174,10 +176,11 @@ func NewRepositoryAddCmd(newClient ClientFactory) *cobra.Command {
 		Short:      "Add a repository",
 		Use:        "add <name> <url>",
 		SuggestFor: []string{"ad", "install"},
-		PreRunE:    bindEnv("confirm"),
+		PreRunE:    bindEnv("confirm", "overwrite"),
 	}
 
 	cmd.Flags().BoolP("confirm", "c", false, "Prompt to confirm all options interactively (Env: $FUNC_CONFIRM)")
+	cmd.Flags().BoolP("overwrite", "", false, "Replace the repository if one of the name already exists (Env: $FUNC_OVERWRITE)")
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 		return runRepositoryAdd(cmd, args, newClient)
@@ -220,6 +223,38 @@ func NewRepositoryRemoveCmd(newClient ClientFactory) *cobra.Command {
 	return cmd
 }
 
+func NewRepositoryUpdateCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Short:      "Update installed repositories",
+		Use:        "update <name>",
+		SuggestFor: []string{"upgrade", "pull", "refresh"},
+		PreRunE:    bindEnv("all", "force"),
+	}
+
+	cmd.Flags().BoolP("all", "a", false, "Update all installed repositories (Env: $FUNC_ALL)")
+	cmd.Flags().BoolP("force", "f", false, "Discard uncommitted local modifications to the repository (Env: $FUNC_FORCE)")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runRepositoryUpdate(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func NewRepositoryPruneCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Short:      "Prune broken repositories",
+		Use:        "prune",
+		SuggestFor: []string{"clean", "gc"},
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runRepositoryPrune(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
 // command implementations
 // -----------------------
 
@@ -294,7 +329,7 @@ func runRepositoryList(_ *cobra.Command, args []string, newClient ClientFactory)
 }
 
 // Add
-func runRepositoryAdd(_ *cobra.Command, args []string, newClient ClientFactory) (err error) {
+func runRepositoryAdd(cmd *cobra.Command, args []string, newClient ClientFactory) (err error) {
 	// Supports both composable, discrete CLI commands or prompt-based "config"
 	// by setting the argument values (name and ulr) to value of positional args,
 	// but only requires them if not prompting.  If prompting, those values
@@ -369,9 +404,15 @@ func runRepositoryAdd(_ *cobra.Command, args []string, newClient ClientFactory)
 		fmt.Fprintf(os.Stdout, "URL:  %v\n", params.URL)
 	}
 
-	// Add repository
+	// Add repository, replacing any existing repository of the name when
+	// --overwrite was requested.
 	var n string
-	if n, err = client.Repositories().Add(params.Name, params.URL); err != nil {
+	if viper.GetBool("overwrite") {
+		n, err = client.Repositories().Overwrite(cmd.Context(), params.Name, params.URL)
+	} else {
+		n, err = client.Repositories().Add(cmd.Context(), params.Name, params.URL)
+	}
+	if err != nil {
 		return
 	}
 	if cfg.Verbose {
@@ -545,6 +586,77 @@ func runRepositoryRemove(_ *cobra.Command, args []string, newClient ClientFactor
 	return
 }
 
+// Update
+// (a single named repository, or all installed with --all)
+func runRepositoryUpdate(cmd *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	cfg, err := newRepositoryConfig(args)
+	if err != nil {
+		return
+	}
+	var (
+		all   = viper.GetBool("all")
+		force = viper.GetBool("force")
+	)
+
+	client, done := newClient(ClientConfig{Verbose: cfg.Verbose},
+		fn.WithRepositoriesPath(cfg.RepositoriesPath))
+	defer done()
+
+	// Preconditions
+	if len(args) != 1 && !all {
+		return fmt.Errorf("usage: func repository update <name> (or --all)")
+	}
+
+	names := args
+	if all {
+		if names, err = installedRepositories(client); err != nil {
+			return
+		}
+		if len(names) == 0 {
+			return errors.New("No repositories installed. use 'add' to install")
+		}
+	}
+
+	// Update each, reporting whether new commits were received.
+	for _, name := range names {
+		changed, err := client.Repositories().Update(cmd.Context(), name, force)
+		if err != nil {
+			return err
+		}
+		if changed {
+			fmt.Fprintf(os.Stdout, "%v updated\n", name)
+		} else {
+			fmt.Fprintf(os.Stdout, "%v already up-to-date\n", name)
+		}
+	}
+	return
+}
+
+// Prune
+func runRepositoryPrune(_ *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	cfg, err := newRepositoryConfig(args)
+	if err != nil {
+		return
+	}
+
+	client, done := newClient(ClientConfig{Verbose: cfg.Verbose},
+		fn.WithRepositoriesPath(cfg.RepositoriesPath))
+	defer done()
+
+	// Remove repositories which no longer load, printing the names pruned.
+	pruned, err := client.Repositories().Prune()
+	if err != nil {
+		return
+	}
+	for _, name := range pruned {
+		fmt.Fprintln(os.Stdout, name)
+	}
+	if cfg.Verbose {
+		fmt.Fprintf(os.Stdout, "Pruned %v repositories\n", len(pruned))
+	}
+	return
+}
+
 // Installed repositories
 // All repositories which have been installed (does not include builtin)
 func installedRepositories(client *fn.Client) ([]string, error) {
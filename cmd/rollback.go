@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewRollbackCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback [NAME]",
+		Short: "Roll back a function to a previous revision",
+		Long: `Roll back a function to a previous revision
+
+This command moves all traffic of a deployed function back to a previously
+deployed revision. By default the function from the project in the current
+directory is rolled back to the revision deployed immediately prior to the
+current one. Alternatively either the name of the function can be given as
+argument or the project path provided with --path, and a specific revision
+can be targeted with --revision.
+`,
+		Example: `
+# Roll back the function defined in the local directory to its previous revision
+{{.Name}} rollback
+
+# Roll back the function 'myfunc' to a specific revision
+{{.Name}} rollback myfunc --revision myfunc-00001
+`,
+		SuggestFor:        []string{"revert", "undo"},
+		ValidArgsFunction: CompleteFunctionList,
+		PreRunE:           bindEnv("path", "revision"),
+		SilenceUsage:      true, // no usage dump on error
+	}
+
+	cmd.Flags().StringP("revision", "r", "", "Revision to roll back to.  Defaults to the revision deployed prior to the current (Env: $FUNC_REVISION)")
+	setPathFlag(cmd)
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runRollback(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	config := newRollbackConfig(args)
+
+	var name string
+
+	// Use the explicit name (when provided), otherwise that of the function
+	// at the effective path.
+	if len(args) > 0 && args[0] != "" {
+		if cmd.Flags().Changed("path") {
+			return fmt.Errorf("Only one of --path and [NAME] should be provided")
+		}
+		name = args[0]
+	} else {
+		var function fn.Function
+		if function, err = fn.NewFunction(config.Path); err != nil {
+			return
+		}
+		if !function.Initialized() {
+			return fmt.Errorf("the given path '%v' does not contain an initialized function", config.Path)
+		}
+		name = function.Name
+	}
+
+	client, done := newClient(ClientConfig{Namespace: config.Namespace, Verbose: config.Verbose})
+	defer done()
+
+	return client.Rollback(cmd.Context(), name, config.Revision)
+}
+
+type rollbackConfig struct {
+	Name      string
+	Namespace string
+	Path      string
+	Revision  string
+	Verbose   bool
+}
+
+// newRollbackConfig returns a config populated from the current execution
+// context (args, flags and environment variables)
+func newRollbackConfig(args []string) rollbackConfig {
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+	return rollbackConfig{
+		Path:      getPathFlag(),
+		Namespace: viper.GetString("namespace"),
+		Revision:  viper.GetString("revision"),
+		Name:      deriveName(name, getPathFlag()), // args[0] or derived
+		Verbose:   viper.GetBool("verbose"),        // defined on root
+	}
+}
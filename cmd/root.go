@@ -100,12 +100,18 @@ EXAMPLES
 				NewCreateCmd(newClient),
 				NewDeleteCmd(newClient),
 				NewDeployCmd(newClient),
+				NewDiffCmd(newClient),
 				NewInfoCmd(newClient),
 				NewInvokeCmd(newClient),
 				NewLanguagesCmd(newClient),
 				NewListCmd(newClient),
+				NewMigrateCmd(defaultLoaderSaver),
+				NewRepairCmd(newClient),
 				NewRepositoryCmd(newClient),
+				NewRollbackCmd(newClient),
 				NewRunCmd(newClient),
+				NewStopCmd(newClient),
+				NewSubscribeCmd(newClient),
 				NewTemplatesCmd(newClient),
 			},
 		},
@@ -170,7 +176,7 @@ func deriveName(explicitName string, path string) string {
 
 // deriveNameAndAbsolutePathFromPath returns resolved function name and absolute path
 // to the function project root. The input parameter path could be one of:
-// 'relative/path/to/foo', '/absolute/path/to/foo', 'foo' or ''
+// 'relative/path/to/foo', '/absolute/path/to/foo', 'foo' or ”
 func deriveNameAndAbsolutePathFromPath(path string) (string, string) {
 	var absPath string
 
@@ -201,8 +207,9 @@ func deriveNameAndAbsolutePathFromPath(path string) (string, string) {
 //
 // Derivation logic:
 // deriveImage attempts to arrive at a final, full image name:
-//   format:  [registry]/[username]/[functionName]:[tag]
-//   example: quay.io/myname/my.function.name:tag.
+//
+//	format:  [registry]/[username]/[functionName]:[tag]
+//	example: quay.io/myname/my.function.name:tag.
 //
 // Registry can optionally be omitted, in which case DefaultRegistry
 // will be prepended.
@@ -240,6 +247,38 @@ func envFromCmd(cmd *cobra.Command) (*util.OrderedMap, []string, error) {
 	return util.NewOrderedMap(), []string{}, nil
 }
 
+func buildEnvFromCmd(cmd *cobra.Command) (*util.OrderedMap, []string, error) {
+	if cmd.Flags().Changed("build-env") {
+		env, err := cmd.Flags().GetStringArray("build-env")
+		if err != nil {
+			return nil, []string{}, fmt.Errorf("Invalid --build-env: %w", err)
+		}
+		return util.OrderedMapAndRemovalListFromArray(env, "=")
+	}
+	return util.NewOrderedMap(), []string{}, nil
+}
+
+// buildArgsFromCmd parses any --build-arg flags (KEY=VALUE) into a map of
+// build-tool directives keyed by name.  Returns nil when none were provided.
+func buildArgsFromCmd(cmd *cobra.Command) (map[string]string, error) {
+	if !cmd.Flags().Changed("build-arg") {
+		return nil, nil
+	}
+	args, err := cmd.Flags().GetStringArray("build-arg")
+	if err != nil {
+		return nil, fmt.Errorf("Invalid --build-arg: %w", err)
+	}
+	buildArgs := make(map[string]string, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("Invalid --build-arg '%v': must be in the form KEY=VALUE", arg)
+		}
+		buildArgs[parts[0]] = parts[1]
+	}
+	return buildArgs, nil
+}
+
 func mergeEnvs(envs []fn.Env, envToUpdate *util.OrderedMap, envToRemove []string) ([]fn.Env, int, error) {
 	updated := sets.NewString()
 
@@ -295,6 +334,12 @@ func setPathFlag(cmd *cobra.Command) {
 func getPathFlag() string {
 	path := viper.GetString("path")
 	if path == "." {
+		// When no explicit path was provided, resolve upward from the
+		// current working directory to the nearest enclosing function
+		// project, allowing commands to be run from subdirectories.
+		if root, err := fn.SearchFunctionRoot(cwd()); err == nil {
+			return root
+		}
 		path = cwd()
 	}
 	return path
@@ -368,11 +413,11 @@ func (v Version) StringVerbose() string {
 //
 // Usage Example:
 //
-//  languages := []string{ "go", "node", "rust" },
-//  survey.Select{
-//    Options: options,
-//    Default: surveySelectDefaut(cfg.Language, languages),
-//  }
+//	languages := []string{ "go", "node", "rust" },
+//	survey.Select{
+//	  Options: options,
+//	  Default: surveySelectDefaut(cfg.Language, languages),
+//	}
 //
 // Summary:
 //
@@ -385,10 +430,11 @@ func (v Version) StringVerbose() string {
 //
 // The above example chooses the default for the Survey (--confirm) question
 // in a way that works with user-provided flag and environment variable values.
-//  `cfg.Language` is the current value set in the config struct, which is
-//     populated from (in ascending order of precedence):
-//     static flag default, associated environment variable, or command flag.
-//  `languages` are the options which are being used by the survey select.
+//
+//	`cfg.Language` is the current value set in the config struct, which is
+//	   populated from (in ascending order of precedence):
+//	   static flag default, associated environment variable, or command flag.
+//	`languages` are the options which are being used by the survey select.
 //
 // This cascade allows for the Survey questions to be properly pre-initialzed
 // with their associated environment variables or flags.  For example,
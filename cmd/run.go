@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/ory/viper"
 	"github.com/spf13/cobra"
 	"knative.dev/client/pkg/util"
 
 	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/docker"
+	"knative.dev/kn-plugin-func/knative"
 )
 
 func NewRunCmd(newClient ClientFactory) *cobra.Command {
@@ -43,16 +48,32 @@ to the function's source.  Use --build to override this behavior.
 
 `,
 		SuggestFor: []string{"rnu"},
-		PreRunE:    bindEnv("build", "path", "registry"),
+		PreRunE:    bindEnv("build", "path", "registry", "no-volumes", "port", "env-file", "remote"),
 	}
 
 	cmd.Flags().StringArrayP("env", "e", []string{},
 		"Environment variable to set in the form NAME=VALUE. "+
 			"You may provide this flag multiple times for setting multiple environment variables. "+
 			"To unset, specify the environment variable name followed by a \"-\" (e.g., NAME-).")
+	cmd.Flags().StringArray("build-env", []string{},
+		"Build-time environment variable to set in the form NAME=VALUE, merged into the function's build environment before any rebuild. "+
+			"You may provide this flag multiple times.  Values may reference the local environment via {{\"{{\"}} env:NAME {{\"}}\"}}. "+
+			"To unset, specify the environment variable name followed by a \"-\" (e.g., NAME-).")
+	cmd.Flags().String("env-file", "",
+		"Path to a file of NAME=VALUE pairs to set in the runtime environment.  "+
+			"By default a '.env' file in the function root is loaded if present.  "+
+			"These values are not written to func.yaml, and envs configured on the function (including via --env) take precedence (Env: $FUNC_ENV_FILE)")
+	cmd.Flags().Bool("remote", false,
+		"Run the function as a short-lived service in the target cluster rather than a local container, "+
+			"proxying its route to the reported local port and removing it when stopped.  "+
+			"The image is pushed to the registry before running (Env: $FUNC_REMOTE)")
+	cmd.Flags().Bool("no-volumes", false,
+		"Do not mount the function's configured volumes into the local container. "+
+			"By default the referenced Secrets and ConfigMaps are fetched from the cluster and mounted at their configured paths.")
 	cmd.Flags().StringP("build", "b", "auto", "Build the function. [auto|true|false].")
 	cmd.Flags().Lookup("build").NoOptDefVal = "true" // --build is equivalient to --build=true
 	cmd.Flags().StringP("registry", "r", GetDefaultRegistry(), "Registry + namespace part of the image if building, ex 'quay.io/myuser' (Env: $FUNC_REGISTRY)")
+	cmd.Flags().Int("port", 0, "Host port on which to serve the function.  Errors if the port is already in use.  The default of 0 automatically selects an available port (Env: $FUNC_PORT)")
 	setPathFlag(cmd)
 
 	cmd.SetHelpFunc(defaultTemplatedHelp)
@@ -77,22 +98,49 @@ func runRun(cmd *cobra.Command, args []string, newClient ClientFactory) (err err
 	if !function.Initialized() {
 		return fmt.Errorf("the given path '%v' does not contain an initialized function", config.Path)
 	}
-	var updated int
+	var updated, buildUpdated int
 	function.Run.Envs, updated, err = mergeEnvs(function.Run.Envs, config.EnvToUpdate, config.EnvToRemove)
 	if err != nil {
 		return
 	}
-	if updated > 0 {
+	function.Build.BuildEnvs, buildUpdated, err = mergeEnvs(function.Build.BuildEnvs, config.BuildEnvToUpdate, config.BuildEnvToRemove)
+	if err != nil {
+		return
+	}
+	if updated+buildUpdated > 0 {
 		err = function.Write()
 		if err != nil {
 			return
 		}
 	}
 
+	// Load an env file if explicitly requested, or a .env in the function
+	// root if present.  These are passed through to the runtime environment
+	// without being written back to func.yaml.
+	fileEnvs, err := envsFromFile(config.EnvFile, function.Root)
+	if err != nil {
+		return
+	}
+
 	// Client for use running (and potentially building), using the config
 	// gathered plus any additional option overrieds (such as for providing
 	// mocks when testing for builder and runner)
-	client, done := newClient(ClientConfig{Verbose: config.Verbose}, fn.WithRegistry(config.Registry))
+	o := []fn.Option{fn.WithRegistry(config.Registry)}
+	if config.Remote {
+		// Run in-cluster: an ephemeral service whose route is proxied to the
+		// local port reported below, removed when the run is stopped.
+		o = append(o, fn.WithRunner(knative.NewClusterRunner("", config.Verbose)))
+	} else if config.NoVolumes || config.Port != 0 || len(fileEnvs) > 0 {
+		ro := []docker.RunnerOpt{docker.WithVolumeMounts(!config.NoVolumes)}
+		if config.Port != 0 {
+			ro = append(ro, docker.WithPort(strconv.Itoa(config.Port)))
+		}
+		if len(fileEnvs) > 0 {
+			ro = append(ro, docker.WithExtraEnvs(fileEnvs))
+		}
+		o = append(o, fn.WithRunner(docker.NewRunner(config.Verbose, ro...)))
+	}
+	client, done := newClient(ClientConfig{Verbose: config.Verbose}, o...)
 	defer done()
 
 	// Build?
@@ -123,6 +171,13 @@ func runRun(cmd *cobra.Command, args []string, newClient ClientFactory) (err err
 
 	}
 
+	// In-cluster runs require the image be available to the cluster.
+	if config.Remote {
+		if err = client.Push(cmd.Context(), config.Path); err != nil {
+			return
+		}
+	}
+
 	// Run the function at path
 	job, err := client.Run(cmd.Context(), config.Path)
 	if err != nil {
@@ -143,6 +198,56 @@ func runRun(cmd *cobra.Command, args []string, newClient ClientFactory) (err err
 	}
 }
 
+// envsFromFile returns environment variables from the env file at path or,
+// when path is empty, from a '.env' file in the function root if one exists.
+// An explicitly requested file which does not exist is an error; the
+// implicit default is optional.
+func envsFromFile(path, root string) ([]fn.Env, error) {
+	explicit := path != ""
+	if !explicit {
+		path = filepath.Join(root, ".env")
+	}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to load env file '%v': %v", path, err)
+	}
+	return parseEnvFile(path)
+}
+
+// parseEnvFile reads NAME=VALUE pairs from the file at path.  Blank lines
+// and lines beginning with '#' are ignored, a leading 'export ' is allowed,
+// and values may be single- or double-quoted.
+func parseEnvFile(path string) (envs []fn.Env, err error) {
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	for i, line := range strings.Split(string(bb), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, found := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("env file '%v' line %v is not in NAME=VALUE form: '%v'", path, i+1, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 {
+			if (value[0] == '"' && value[len(value)-1] == '"') ||
+				(value[0] == '\'' && value[len(value)-1] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+		}
+		n, v := name, value // copies whose addresses survive the loop
+		envs = append(envs, fn.Env{Name: &n, Value: &v})
+	}
+	return
+}
+
 type runConfig struct {
 	// Path of the function implementation on local disk. Defaults to current
 	// working directory of the process.
@@ -157,12 +262,35 @@ type runConfig struct {
 	// Envs passed via cmd to removed
 	EnvToRemove []string
 
+	// Build envs passed via cmd to be added/updated
+	BuildEnvToUpdate *util.OrderedMap
+
+	// Build envs passed via cmd to be removed
+	BuildEnvToRemove []string
+
 	// Perform build.  Acceptable values are the keyword 'auto', or a truthy
 	// value such as 'true', 'false, '1' or '0'.
 	Build string
 
 	// Registry for the build tag if building
 	Registry string
+
+	// NoVolumes disables mounting the function's configured volumes into the
+	// local container.
+	NoVolumes bool
+
+	// Port is an explicitly requested host port on which to serve the
+	// function.  The default of 0 automatically selects an available port.
+	Port int
+
+	// EnvFile is an explicit path to a file of NAME=VALUE pairs to load into
+	// the runtime environment (without persisting to func.yaml).  When empty,
+	// a '.env' file in the function root is loaded if present.
+	EnvFile string
+
+	// Remote runs the function as an ephemeral service in the target
+	// cluster rather than a local container.
+	Remote bool
 }
 
 func newRunConfig(cmd *cobra.Command) (cfg runConfig, err error) {
@@ -170,6 +298,10 @@ func newRunConfig(cmd *cobra.Command) (cfg runConfig, err error) {
 	if err != nil {
 		return
 	}
+	buildEnvToUpdate, buildEnvToRemove, err := buildEnvFromCmd(cmd)
+	if err != nil {
+		return
+	}
 	cfg = runConfig{
 		Build:       viper.GetString("build"),
 		Path:        getPathFlag(),
@@ -177,6 +309,13 @@ func newRunConfig(cmd *cobra.Command) (cfg runConfig, err error) {
 		Registry:    viper.GetString("registry"),
 		EnvToUpdate: envToUpdate,
 		EnvToRemove: envToRemove,
+
+		BuildEnvToUpdate: buildEnvToUpdate,
+		BuildEnvToRemove: buildEnvToRemove,
+		NoVolumes:        viper.GetBool("no-volumes"),
+		Port:             viper.GetInt("port"),
+		EnvFile:          viper.GetString("env-file"),
+		Remote:           viper.GetBool("remote"),
 	}
 	return
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	fn "knative.dev/kn-plugin-func"
@@ -167,3 +168,122 @@ created: 2009-11-10 23:00:00`,
 		})
 	}
 }
+
+// TestRun_BuildEnvs ensures that build-time environment variables provided
+// via --build-env are merged into the function's build environment before
+// the build step.
+func TestRun_BuildEnvs(t *testing.T) {
+	defer Fromtemp(t)()
+
+	var buildEnvs []fn.Env
+	builder := mock.NewBuilder()
+	builder.BuildFn = func(f fn.Function) error {
+		buildEnvs = f.Build.BuildEnvs
+		return nil
+	}
+
+	cmd := NewRunCmd(NewClientFactory(func() *fn.Client {
+		return fn.New(
+			fn.WithRunner(mock.NewRunner()),
+			fn.WithBuilder(builder),
+			fn.WithRegistry("ghcr.com/reg"),
+		)
+	}))
+	cmd.SetArgs([]string{"--build-env", "MAVEN_OPTS=-Xmx1g"})
+
+	funcState := `name: test-func
+runtime: go
+created: 2009-11-10 23:00:00`
+	if err := os.WriteFile("func.yaml", []byte(funcState), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := cmd.ExecuteContextC(ctx)
+		done <- err
+	}()
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if !builder.BuildInvoked {
+		t.Fatal("builder was not invoked")
+	}
+	found := false
+	for _, e := range buildEnvs {
+		if e.Name != nil && *e.Name == "MAVEN_OPTS" && e.Value != nil && *e.Value == "-Xmx1g" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected MAVEN_OPTS build env to reach the builder, got %v", buildEnvs)
+	}
+}
+
+// TestRun_ParseEnvFile ensures env files are parsed handling comments, blank
+// lines, 'export' prefixes and quoted values, and that malformed lines error.
+func TestRun_ParseEnvFile(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	path := filepath.Join(root, ".env")
+	content := `# a comment line
+
+DATABASE_URL=postgres://localhost:5432/dev
+export API_KEY="secret value"
+GREETING='hello world'
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	envs, err := parseEnvFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"DATABASE_URL": "postgres://localhost:5432/dev",
+		"API_KEY":      "secret value",
+		"GREETING":     "hello world",
+	}
+	if len(envs) != len(expected) {
+		t.Fatalf("expected %v envs, got %v", len(expected), len(envs))
+	}
+	for _, e := range envs {
+		if expected[*e.Name] != *e.Value {
+			t.Errorf("env %v expected value '%v', got '%v'", *e.Name, expected[*e.Name], *e.Value)
+		}
+	}
+
+	// Malformed lines error
+	if err := os.WriteFile(path, []byte("NOT A PAIR\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseEnvFile(path); err == nil {
+		t.Fatal("expected error parsing malformed env file, got nil")
+	}
+}
+
+// TestRun_EnvsFromFile ensures that an explicitly requested env file which
+// does not exist errors, while the implicit .env default is optional.
+func TestRun_EnvsFromFile(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	// Implicit default: no .env file is not an error
+	envs, err := envsFromFile("", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 0 {
+		t.Fatalf("expected no envs, got %v", envs)
+	}
+
+	// Explicit file which does not exist is an error
+	if _, err := envsFromFile(filepath.Join(root, "missing.env"), root); err == nil {
+		t.Fatal("expected error for missing explicit env file, got nil")
+	}
+}
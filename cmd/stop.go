@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewStopCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the function running locally",
+		Long: `Stop the function running locally
+
+Stops a function which is running locally, started from the project in the
+current directory or in the directory specified by --path, possibly by a
+separate process such as another terminal's 'run' command.  The function is
+requested to shut down gracefully.  When no run is active this is a no-op.
+`,
+		Example: `
+# Stop the function started in another terminal with 'func run'
+{{.Name}} stop
+`,
+		SuggestFor:   []string{"sotp", "halt", "kill"},
+		PreRunE:      bindEnv("path"),
+		SilenceUsage: true, // no usage dump on error
+	}
+
+	setPathFlag(cmd)
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runStop(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func runStop(cmd *cobra.Command, _ []string, newClient ClientFactory) (err error) {
+	client, done := newClient(ClientConfig{Verbose: viper.GetBool("verbose")})
+	defer done()
+
+	err = client.Stop(cmd.Context(), getPathFlag())
+	if errors.Is(err, fn.ErrNotRunning) {
+		fmt.Fprintln(cmd.OutOrStdout(), "No running function found.  Nothing to stop.")
+		return nil
+	}
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "Function requested to stop")
+	return
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+func NewSubscribeCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "subscribe",
+		Short: "Subscribe a function to events",
+		Long: `Subscribe a function to events
+
+Subscribes the function in the current directory (or from the directory
+specified with --path) to events of the given type on a broker, creating the
+necessary Trigger.  The subscription is also recorded in func.yaml such that
+it is reapplied on subsequent deploys.  Subscribing again with the same
+values is a no-op.
+`,
+		Example: `
+# Subscribe the function to 'com.example.order' events on the default broker
+{{.Name}} subscribe --type com.example.order
+
+# Subscribe to events of a type from a specific source, on a named broker
+{{.Name}} subscribe --broker orders --type com.example.order --source /orders
+`,
+		SuggestFor:   []string{"subcribe", "trigger"},
+		PreRunE:      bindEnv("path", "broker", "type", "source"),
+		SilenceUsage: true, // no usage dump on error
+	}
+
+	cmd.Flags().StringP("broker", "b", fn.DefaultBroker, "Name of the broker through which events are delivered (Env: $FUNC_BROKER)")
+	cmd.Flags().StringP("type", "t", "", "CloudEvent type on which to filter; required (Env: $FUNC_TYPE)")
+	cmd.Flags().StringP("source", "s", "", "CloudEvent source on which to additionally filter (Env: $FUNC_SOURCE)")
+	setPathFlag(cmd)
+
+	cmd.SetHelpFunc(defaultTemplatedHelp)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runSubscribe(cmd, args, newClient)
+	}
+
+	return cmd
+}
+
+func runSubscribe(cmd *cobra.Command, _ []string, newClient ClientFactory) (err error) {
+	config := newSubscribeConfig()
+
+	if config.Type == "" {
+		return fmt.Errorf("the --type of events to subscribe to is required")
+	}
+
+	client, done := newClient(ClientConfig{Namespace: config.Namespace, Verbose: config.Verbose})
+	defer done()
+
+	return client.Subscribe(cmd.Context(), config.Path, fn.Subscription{
+		Broker: config.Broker,
+		Type:   config.Type,
+		Source: config.Source,
+	})
+}
+
+type subscribeConfig struct {
+	Broker    string
+	Namespace string
+	Path      string
+	Source    string
+	Type      string
+	Verbose   bool
+}
+
+// newSubscribeConfig returns a config populated from the current execution
+// context (flags and environment variables)
+func newSubscribeConfig() subscribeConfig {
+	return subscribeConfig{
+		Broker:    viper.GetString("broker"),
+		Namespace: viper.GetString("namespace"),
+		Path:      getPathFlag(),
+		Source:    viper.GetString("source"),
+		Type:      viper.GetString("type"),
+		Verbose:   viper.GetBool("verbose"),
+	}
+}
@@ -1,5 +1,37 @@
 package function
 
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFile is the name of the global configuration file, relative
+// to the effective config path (see ConfigPath).
+const ConfigFile = "config.yaml"
+
 // Config is local and global configuration which is not "part of the function"
 // and is thus not likely to be tracked in source control.
-type Config struct{}
+type Config struct {
+	// DefaultTemplate is the template used when creating functions for which
+	// no template was explicitly specified, overriding the static default
+	// (the package-level DefaultTemplate).  The value is validated against
+	// available templates when the function is created.
+	DefaultTemplate string `yaml:"defaultTemplate,omitempty"`
+}
+
+// NewConfig returns the global configuration, loaded from the config file
+// within the effective config path ([ConfigPath]/config.yaml).  A missing
+// file is not an error; the zero-valued Config is returned.
+func NewConfig() (cfg Config, err error) {
+	bb, err := os.ReadFile(filepath.Join(ConfigPath(), ConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil // config file is optional
+		}
+		return
+	}
+	err = yaml.Unmarshal(bb, &cfg)
+	return
+}
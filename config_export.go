@@ -0,0 +1,145 @@
+package function
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigExport is the portable portion of a function's configuration:
+// settings which are meaningful to copy between projects.  Identity
+// (name, image, created timestamp etc.) is deliberately excluded.
+type ConfigExport struct {
+	BuildEnvs       []Env             `yaml:"buildEnvs,omitempty"`
+	Envs            []Env             `yaml:"envs,omitempty"`
+	Volumes         []Volume          `yaml:"volumes,omitempty"`
+	Labels          []Label           `yaml:"labels,omitempty"`
+	Annotations     map[string]string `yaml:"annotations,omitempty"`
+	Options         Options           `yaml:"options,omitempty"`
+	HealthEndpoints HealthEndpoints   `yaml:"healthEndpoints,omitempty"`
+}
+
+// ExportConfig returns the portable configuration of the function at path,
+// serialized as YAML.  Only configuration (envs, volumes, labels,
+// annotations, options and health endpoints) is included; identity fields
+// such as name and image are not.
+func (c *Client) ExportConfig(path string) ([]byte, error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return nil, err
+	}
+	if !f.Initialized() {
+		return nil, ErrNotInitialized
+	}
+	export := ConfigExport{
+		BuildEnvs:       f.Build.BuildEnvs,
+		Envs:            f.Run.Envs,
+		Volumes:         f.Run.Volumes,
+		Labels:          f.Deploy.Labels,
+		Annotations:     f.Deploy.Annotations,
+		Options:         f.Deploy.Options,
+		HealthEndpoints: f.Deploy.HealthEndpoints,
+	}
+	return yaml.Marshal(export)
+}
+
+// ImportConfig merges previously exported configuration (see ExportConfig)
+// into the function at path, rewriting its func.yaml.  Imported entries
+// overwrite existing entries of the same name (or path, for volumes);
+// existing entries not present in the import are preserved.  Scalar
+// sections (options, health endpoints) are replaced only when set in the
+// import.
+func (c *Client) ImportConfig(path string, data []byte) error {
+	f, err := NewFunction(path)
+	if err != nil {
+		return err
+	}
+	if !f.Initialized() {
+		return ErrNotInitialized
+	}
+	var imported ConfigExport
+	if err = yaml.UnmarshalStrict(data, &imported); err != nil {
+		return err
+	}
+
+	f.Build.BuildEnvs = mergeImportedEnvs(f.Build.BuildEnvs, imported.BuildEnvs)
+	f.Run.Envs = mergeImportedEnvs(f.Run.Envs, imported.Envs)
+	f.Run.Volumes = mergeImportedVolumes(f.Run.Volumes, imported.Volumes)
+	f.Deploy.Labels = mergeImportedLabels(f.Deploy.Labels, imported.Labels)
+	if len(imported.Annotations) > 0 {
+		if f.Deploy.Annotations == nil {
+			f.Deploy.Annotations = map[string]string{}
+		}
+		for k, v := range imported.Annotations {
+			f.Deploy.Annotations[k] = v
+		}
+	}
+	if imported.Options.Scale != nil || imported.Options.Resources != nil {
+		f.Deploy.Options = imported.Options
+	}
+	if !reflect.DeepEqual(imported.HealthEndpoints, HealthEndpoints{}) {
+		f.Deploy.HealthEndpoints = imported.HealthEndpoints
+	}
+
+	if err = f.Validate(); err != nil {
+		return err
+	}
+	return f.Write()
+}
+
+// mergeImportedEnvs overlays imported envs onto existing:  entries sharing
+// a name are overwritten, others appended.
+func mergeImportedEnvs(existing, imported []Env) []Env {
+	for _, imp := range imported {
+		replaced := false
+		for i := range existing {
+			if existing[i].Name != nil && imp.Name != nil && *existing[i].Name == *imp.Name {
+				existing[i] = imp
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, imp)
+		}
+	}
+	return existing
+}
+
+// mergeImportedVolumes overlays imported volumes onto existing, matching
+// on mount path.
+func mergeImportedVolumes(existing, imported []Volume) []Volume {
+	for _, imp := range imported {
+		replaced := false
+		for i := range existing {
+			if existing[i].Path != nil && imp.Path != nil && *existing[i].Path == *imp.Path {
+				existing[i] = imp
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, imp)
+		}
+	}
+	return existing
+}
+
+// mergeImportedLabels overlays imported labels onto existing, matching
+// on key.
+func mergeImportedLabels(existing, imported []Label) []Label {
+	for _, imp := range imported {
+		replaced := false
+		for i := range existing {
+			if existing[i].Key != nil && imp.Key != nil && *existing[i].Key == *imp.Key {
+				existing[i] = imp
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, imp)
+		}
+	}
+	return existing
+}
@@ -0,0 +1,97 @@
+//go:build !integration
+// +build !integration
+
+package function_test
+
+import (
+	"strings"
+	"testing"
+
+	fn "knative.dev/kn-plugin-func"
+	. "knative.dev/kn-plugin-func/testing"
+)
+
+// TestConfig_ExportImportRoundTrip ensures that configuration exported from
+// one function can be imported into another:  imported entries overwrite
+// matching entries, and existing entries not present in the export are
+// preserved.  Identity fields such as name are not transferred.
+func TestConfig_ExportImportRoundTrip(t *testing.T) {
+	rootA := "testdata/example.com/testConfigExportA"
+	rootB := "testdata/example.com/testConfigExportB"
+	defer Using(t, rootA)()
+	defer Using(t, rootB)()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: rootA}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: rootB}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Configure the source function with envs, labels and annotations.
+	name, value := "SHARED", "a"
+	labelKey, labelValue := "team", "alpha"
+	a, err := fn.NewFunction(rootA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Run.Envs = []fn.Env{{Name: &name, Value: &value}}
+	a.Deploy.Labels = []fn.Label{{Key: &labelKey, Value: &labelValue}}
+	a.Deploy.Annotations = map[string]string{"division": "finance"}
+	if err = a.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Configure the target function with an env which will be overwritten
+	// and one which should be preserved.
+	overwritten, keepName, keepValue := "b", "KEEP", "yes"
+	b, err := fn.NewFunction(rootB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Run.Envs = []fn.Env{
+		{Name: &name, Value: &overwritten},
+		{Name: &keepName, Value: &keepValue},
+	}
+	if err = b.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Export from A; the serialization should not include identity fields.
+	data, err := client.ExportConfig(rootA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "testConfigExportA") {
+		t.Fatalf("exported config should not include identity fields:\n%s", data)
+	}
+
+	// Import into B and verify merge semantics.
+	if err = client.ImportConfig(rootB, data); err != nil {
+		t.Fatal(err)
+	}
+	b, err = fn.NewFunction(rootB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envs := map[string]string{}
+	for _, e := range b.Run.Envs {
+		envs[*e.Name] = *e.Value
+	}
+	if envs["SHARED"] != "a" {
+		t.Fatalf("expected imported env to overwrite, got %q", envs["SHARED"])
+	}
+	if envs["KEEP"] != "yes" {
+		t.Fatal("expected unrelated existing env to be preserved")
+	}
+	if len(b.Deploy.Labels) != 1 || *b.Deploy.Labels[0].Key != "team" {
+		t.Fatalf("expected imported label, got %v", b.Deploy.Labels)
+	}
+	if b.Deploy.Annotations["division"] != "finance" {
+		t.Fatalf("expected imported annotation, got %v", b.Deploy.Annotations)
+	}
+	if b.Name == "testConfigExportA" {
+		t.Fatal("import should not transfer function identity")
+	}
+}
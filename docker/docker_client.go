@@ -33,6 +33,15 @@ func NewClient(defaultHost string) (dockerClient client.CommonAPIClient, dockerH
 	dockerHostSSHIdentity := os.Getenv("DOCKER_HOST_SSH_IDENTITY")
 	hostKeyCallback := fnssh.NewHostKeyCbk()
 
+	// Rootless daemons listen on a per-user socket rather than the system
+	// default.  Use it when explicitly enabled or detected (see
+	// RootlessDockerHost), unless the user has configured a host themselves.
+	if dockerHost == "" {
+		if host, ok := RootlessDockerHost(); ok {
+			dockerHost = host
+		}
+	}
+
 	if dockerHost == "" {
 		_url, err = url.Parse(defaultHost)
 		if err != nil {
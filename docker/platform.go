@@ -2,6 +2,7 @@ package docker
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/containerd/containerd/platforms"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -10,6 +11,14 @@ import (
 	gcrTypes "github.com/google/go-containerregistry/pkg/v1/types"
 )
 
+// PlatformTag returns the image tag used for the given platform's image
+// during a multi-platform build (e.g. "repo/f:latest-linux-arm64").  The
+// per-platform images so tagged are assembled into a manifest list at the
+// original tag when pushed.
+func PlatformTag(image, platform string) string {
+	return image + "-" + strings.ReplaceAll(platform, "/", "-")
+}
+
 // GetPlatformImage returns image reference for specific platform.
 // If the image is not multi-arch it returns ref argument directly (provided platform matches).
 // If the image is multi-arch it returns digest based reference (provided the platform is part of the multi-arch image).
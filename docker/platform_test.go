@@ -21,6 +21,38 @@ import (
 	"knative.dev/kn-plugin-func/docker"
 )
 
+// TestPlatformTag ensures that the tag for a platform's image during a
+// multi-platform build is derived by suffixing the image tag with the
+// platform, slashes replaced such that the result remains a valid tag.
+func TestPlatformTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		platform string
+		want     string
+	}{
+		{
+			name:     "os and architecture",
+			image:    "example.com/alice/f:latest",
+			platform: "linux/amd64",
+			want:     "example.com/alice/f:latest-linux-amd64",
+		},
+		{
+			name:     "with variant",
+			image:    "example.com/alice/f:latest",
+			platform: "linux/arm/v7",
+			want:     "example.com/alice/f:latest-linux-arm-v7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := docker.PlatformTag(tt.image, tt.platform); got != tt.want {
+				t.Errorf("PlatformTag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPlatform(t *testing.T) {
 	testRegistry := startRegistry(t)
 
@@ -15,12 +15,15 @@ import (
 
 	fn "knative.dev/kn-plugin-func"
 
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 )
 
@@ -49,6 +52,8 @@ type Pusher struct {
 	progressListener    fn.ProgressListener
 	transport           http.RoundTripper
 	dockerClientFactory PusherDockerClientFactory
+	platforms           []string
+	keychain            authn.Keychain
 }
 
 func WithCredentialsProvider(cp CredentialsProvider) Opt {
@@ -75,6 +80,29 @@ func WithPusherDockerClientFactory(dockerClientFactory PusherDockerClientFactory
 	}
 }
 
+// WithPlatforms sets the platforms of a multi-platform build whose
+// per-platform images (tagged per PlatformTag) are to be pushed and
+// assembled into a manifest list at the function's image tag.  When zero or
+// one platform is provided the pusher behaves as today, pushing the single
+// image at the function's tag.
+func WithPlatforms(platforms []string) Opt {
+	return func(p *Pusher) {
+		p.platforms = platforms
+	}
+}
+
+// WithRegistryAuth configures the pusher to authenticate using the given
+// keychain, whose standard resolution includes cloud credential helpers
+// (ECR, GCR, etc.) in addition to docker config files.  When provided, the
+// keychain takes precedence over credentials from the configured
+// CredentialsProvider (typically ~/.docker/config.json), which remain the
+// fallback when no keychain is set.
+func WithRegistryAuth(keychain authn.Keychain) Opt {
+	return func(p *Pusher) {
+		p.keychain = keychain
+	}
+}
+
 func WithVerbose(verbose bool) Opt {
 	return func(pusher *Pusher) {
 		pusher.verbose = verbose
@@ -139,6 +167,12 @@ func (n *Pusher) Push(ctx context.Context, f fn.Function) (digest string, err er
 	}
 	n.progressListener.Increment(fmt.Sprintf("Pushing function image to the registry %q using the %q user credentials", registry, credentials.Username))
 
+	// Multi-platform builds are pushed per-platform then assembled into a
+	// manifest list at the function's tag, whose digest is returned.
+	if len(n.platforms) > 1 {
+		return n.pushMultiPlatform(ctx, f, credentials, output)
+	}
+
 	// if the registry is not cluster private do push directly from daemon
 	if _, err = net.DefaultResolver.LookupHost(ctx, registry); err == nil {
 		return n.daemonPush(ctx, f, credentials, output)
@@ -160,6 +194,29 @@ func (n *Pusher) daemonPush(ctx context.Context, f fn.Function, credentials Cred
 		Password: credentials.Password,
 	}
 
+	// An explicitly configured keychain takes precedence (see
+	// WithRegistryAuth), resolving credentials via helpers where configured.
+	if n.keychain != nil {
+		ref, err := name.ParseReference(f.Image, name.WeakValidation)
+		if err != nil {
+			return "", err
+		}
+		authenticator, err := n.keychain.Resolve(ref.Context().Registry)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve credentials from keychain: %w", err)
+		}
+		cfg, err := authenticator.Authorization()
+		if err != nil {
+			return "", fmt.Errorf("failed to get authorization from keychain: %w", err)
+		}
+		authConfig = types.AuthConfig{
+			Username:      cfg.Username,
+			Password:      cfg.Password,
+			IdentityToken: cfg.IdentityToken,
+			RegistryToken: cfg.RegistryToken,
+		}
+	}
+
 	b, err := json.Marshal(&authConfig)
 	if err != nil {
 		return "", err
@@ -178,6 +235,7 @@ func (n *Pusher) daemonPush(ctx context.Context, f fn.Function, credentials Cred
 
 	decoder := json.NewDecoder(r)
 	li := logItem{}
+	lastIncrement := ""
 	for {
 		err = decoder.Decode(&li)
 		if err != nil {
@@ -199,6 +257,18 @@ func (n *Pusher) daemonPush(ctx context.Context, f fn.Function, credentials Cred
 			percent = (li.ProgressDetail.Current * 100) / li.ProgressDetail.Total
 		}
 		fmt.Fprintf(output, "%s (%d%%)\n", li.Status, percent)
+
+		// Relay per-layer upload progress through the progress listener so
+		// that large pushes are visibly advancing even when not verbose.
+		// Deduplicated as the daemon reports far more often than the
+		// percentage changes.
+		if li.Status == "Pushing" && li.Id != "" {
+			increment := fmt.Sprintf("Pushing layer %s (%d%%)", li.Id, percent)
+			if increment != lastIncrement {
+				n.progressListener.Increment(increment)
+				lastIncrement = increment
+			}
+		}
 	}
 
 	digest = ParseDigest(outBuff.String())
@@ -206,6 +276,21 @@ func (n *Pusher) daemonPush(ctx context.Context, f fn.Function, credentials Cred
 	return digest, nil
 }
 
+// formatSize renders a byte count in a human-friendly unit for progress
+// reporting.
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
 var digestRE = regexp.MustCompile(`digest:\s+(sha256:\w{64})`)
 
 // ParseDigest tries to parse the last line from the output, which holds the pushed image digest
@@ -237,12 +322,92 @@ type logItem struct {
 	ProgressDetail progressDetail `json:"progressDetail"`
 }
 
-func (n *Pusher) push(ctx context.Context, f fn.Function, credentials Credentials, output io.Writer) (digest string, err error) {
-	auth := &authn.Basic{
+// pushMultiPlatform pushes each of the per-platform images produced by a
+// multi-platform build, then assembles and pushes a manifest list at the
+// function's image tag, returning the manifest list digest.
+func (n *Pusher) pushMultiPlatform(ctx context.Context, f fn.Function, credentials Credentials, output io.Writer) (digest string, err error) {
+	ref, err := name.ParseReference(f.Image)
+	if err != nil {
+		return "", err
+	}
+
+	dockerClient, err := n.dockerClientFactory()
+	if err != nil {
+		return "", fmt.Errorf("failed to create docker api client: %w", err)
+	}
+	defer dockerClient.Close()
+
+	idx := v1.ImageIndex(empty.Index)
+	for i, platform := range n.platforms {
+		plat, err := platforms.Parse(platform)
+		if err != nil {
+			return "", fmt.Errorf("cannot parse platform %q: %w", platform, err)
+		}
+
+		platRef, err := name.ParseReference(PlatformTag(f.Image, platform))
+		if err != nil {
+			return "", err
+		}
+
+		img, err := daemon.Image(platRef,
+			daemon.WithContext(ctx),
+			daemon.WithClient(dockerClient))
+		if err != nil {
+			return "", fmt.Errorf("cannot read image for platform %q: %w", platform, err)
+		}
+
+		fmt.Fprintf(output, "pushing image for platform %q\n", platform)
+		n.progressListener.Increment(fmt.Sprintf("Pushing image %d of %d (platform %q)", i+1, len(n.platforms), platform))
+		err = remote.Write(platRef, img,
+			n.authOpt(credentials),
+			remote.WithTransport(n.transport),
+			remote.WithContext(ctx))
+		if err != nil {
+			return "", err
+		}
+
+		idx = mutate.AppendManifests(idx, mutate.IndexAddendum{
+			Add: img,
+			Descriptor: v1.Descriptor{
+				Platform: &v1.Platform{
+					OS:           plat.OS,
+					Architecture: plat.Architecture,
+					Variant:      plat.Variant,
+				},
+			},
+		})
+	}
+
+	err = remote.WriteIndex(ref, idx,
+		n.authOpt(credentials),
+		remote.WithTransport(n.transport),
+		remote.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := idx.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	return hash.String(), nil
+}
+
+// authOpt returns the remote authentication option to use: an explicitly
+// configured keychain takes precedence, with basic auth using credentials
+// from the credentials provider as the fallback.
+func (n *Pusher) authOpt(credentials Credentials) remote.Option {
+	if n.keychain != nil {
+		return remote.WithAuthFromKeychain(n.keychain)
+	}
+	return remote.WithAuth(&authn.Basic{
 		Username: credentials.Username,
 		Password: credentials.Password,
-	}
+	})
+}
 
+func (n *Pusher) push(ctx context.Context, f fn.Function, credentials Credentials, output io.Writer) (digest string, err error) {
 	ref, err := name.ParseReference(f.Image)
 	if err != nil {
 		return "", err
@@ -266,19 +431,31 @@ func (n *Pusher) push(ctx context.Context, f fn.Function, credentials Credential
 	go func() {
 		defer fmt.Fprint(output, "\n")
 
+		lastPercent := int64(-1)
 		for progress := range progressChannel {
 			if progress.Error != nil {
 				errChan <- progress.Error
 				return
 			}
-			fmt.Fprintf(output, "\rprogress: %d%%", progress.Complete*100/progress.Total)
+			percent := progress.Complete * 100 / progress.Total
+			fmt.Fprintf(output, "\rprogress: %d%%", percent)
+
+			// Relay upload progress through the progress listener so that
+			// large pushes are visibly advancing even when not verbose.
+			// Deduplicated as updates arrive far more often than the
+			// percentage changes.
+			if percent != lastPercent {
+				n.progressListener.Increment(fmt.Sprintf("Pushing container image (%s of %s)",
+					formatSize(progress.Complete), formatSize(progress.Total)))
+				lastPercent = percent
+			}
 		}
 
 		errChan <- nil
 	}()
 
 	err = remote.Write(ref, img,
-		remote.WithAuth(auth),
+		n.authOpt(credentials),
 		remote.WithProgress(progressChannel),
 		remote.WithTransport(n.transport),
 		remote.WithJobs(1),
@@ -23,6 +23,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/registry"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	gcrTypes "github.com/google/go-containerregistry/pkg/v1/types"
 
 	fn "knative.dev/kn-plugin-func"
 	"knative.dev/kn-plugin-func/docker"
@@ -479,3 +481,247 @@ func (c conn) SetDeadline(t time.Time) error { return nil }
 func (c conn) SetReadDeadline(t time.Time) error { return nil }
 
 func (c conn) SetWriteDeadline(t time.Time) error { return nil }
+
+// TestNonDaemonPushWithKeychain ensures that a keychain provided via
+// WithRegistryAuth is used to authenticate the push, taking precedence over
+// the configured credentials provider (which here returns invalid
+// credentials the registry would reject).
+func TestNonDaemonPushWithKeychain(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	// in memory network emulation
+	connections := conns(make(chan net.Conn))
+
+	serveRegistry(t, connections)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	transport.DialContext = connections.DialContext
+
+	dockerClient := newMockPusherDockerClient()
+
+	dockerClient.imageSave = func(ctx context.Context, images []string) (io.ReadCloser, error) {
+		f, err := os.Open("./testData/image.tar")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load image tar: %w", err)
+		}
+		return f, nil
+	}
+
+	dockerClient.imageInspect = func(ctx context.Context, s string) (types.ImageInspect, []byte, error) {
+		return types.ImageInspect{ID: "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"}, []byte{}, nil
+	}
+
+	dockerClientFactory := func() (docker.PusherDockerClient, error) {
+		return dockerClient, nil
+	}
+
+	badCredProvider := func(ctx context.Context, registry string) (docker.Credentials, error) {
+		return docker.Credentials{Username: "bad", Password: "bad"}, nil
+	}
+
+	pusher := docker.NewPusher(
+		docker.WithTransport(transport),
+		docker.WithCredentialsProvider(badCredProvider),
+		docker.WithRegistryAuth(testKeychain{}),
+		docker.WithPusherDockerClientFactory(dockerClientFactory),
+	)
+
+	f := fn.Function{
+		Image: functionImageRemote,
+	}
+
+	digest, err := pusher.Push(ctx, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest == "" {
+		t.Error("expected a digest from the push")
+	}
+}
+
+// testKeychain resolves every registry to the test user's basic credentials.
+type testKeychain struct{}
+
+func (testKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return &authn.Basic{Username: testUser, Password: testPwd}, nil
+}
+
+// recordingProgressListener records Increment messages for assertions.
+type recordingProgressListener struct {
+	sync.Mutex
+	messages []string
+}
+
+func (l *recordingProgressListener) SetTotal(int) {}
+func (l *recordingProgressListener) Increment(m string) {
+	l.Lock()
+	defer l.Unlock()
+	l.messages = append(l.messages, m)
+}
+func (l *recordingProgressListener) Complete(string) {}
+func (l *recordingProgressListener) Stopping()       {}
+func (l *recordingProgressListener) Done()           {}
+
+// TestPushProgress ensures that pushing reports incremental upload progress
+// through the provided progress listener rather than appearing frozen.
+func TestPushProgress(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	// in memory network emulation
+	connections := conns(make(chan net.Conn))
+
+	serveRegistry(t, connections)
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	transport.DialContext = connections.DialContext
+
+	dockerClient := newMockPusherDockerClient()
+	dockerClient.imageSave = func(ctx context.Context, images []string) (io.ReadCloser, error) {
+		f, err := os.Open("./testData/image.tar")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load image tar: %w", err)
+		}
+		return f, nil
+	}
+	dockerClient.imageInspect = func(ctx context.Context, s string) (types.ImageInspect, []byte, error) {
+		return types.ImageInspect{ID: "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"}, []byte{}, nil
+	}
+	dockerClientFactory := func() (docker.PusherDockerClient, error) {
+		return dockerClient, nil
+	}
+
+	listener := &recordingProgressListener{}
+	pusher := docker.NewPusher(
+		docker.WithTransport(transport),
+		docker.WithCredentialsProvider(testCredProvider),
+		docker.WithPusherDockerClientFactory(dockerClientFactory),
+		docker.WithProgressListener(listener),
+	)
+
+	if _, err := pusher.Push(ctx, fn.Function{Image: functionImageRemote}); err != nil {
+		t.Fatal(err)
+	}
+
+	listener.Lock()
+	defer listener.Unlock()
+	var sawUpload bool
+	for _, m := range listener.messages {
+		if strings.Contains(m, "Pushing container image (") {
+			sawUpload = true
+		}
+	}
+	if !sawUpload {
+		t.Errorf("expected incremental upload progress to be reported, got: %v", listener.messages)
+	}
+}
+
+// TestMultiPlatformPush ensures that pushing a multi-platform build pushes
+// one image per platform at its derived tag (see PlatformTag), assembles the
+// pushed images into a manifest list at the function's tag, and returns the
+// manifest list's digest.
+func TestMultiPlatformPush(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+
+	testRegistry := startRegistry(t)
+	image := testRegistry + "/testuser/func:latest"
+	testPlatforms := []string{"linux/amd64", "linux/arm64"}
+
+	dockerClient := newMockPusherDockerClient()
+
+	var imagesPassedToMock []string
+	dockerClient.imageSave = func(ctx context.Context, images []string) (io.ReadCloser, error) {
+		imagesPassedToMock = append(imagesPassedToMock, images...)
+		f, err := os.Open("./testData/image.tar")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load image tar: %w", err)
+		}
+		return f, nil
+	}
+
+	dockerClient.imageInspect = func(ctx context.Context, s string) (types.ImageInspect, []byte, error) {
+		return types.ImageInspect{ID: "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"}, []byte{}, nil
+	}
+
+	dockerClientFactory := func() (docker.PusherDockerClient, error) {
+		return dockerClient, nil
+	}
+
+	pusher := docker.NewPusher(
+		docker.WithCredentialsProvider(testCredProvider),
+		docker.WithPusherDockerClientFactory(dockerClientFactory),
+		docker.WithPlatforms(testPlatforms),
+	)
+
+	actualDigest, err := pusher.Push(ctx, fn.Function{Image: image})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each platform's image should have been read from the daemon (and
+	// pushed) exactly once, at its derived per-platform tag.
+	expectedImages := []string{
+		docker.PlatformTag(image, "linux/amd64"),
+		docker.PlatformTag(image, "linux/arm64"),
+	}
+	if !reflect.DeepEqual(imagesPassedToMock, expectedImages) {
+		t.Errorf("expected images %q read from the daemon, got %q", expectedImages, imagesPassedToMock)
+	}
+	for _, platform := range testPlatforms {
+		platRef, err := name.ParseReference(docker.PlatformTag(image, platform))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = remote.Image(platRef); err != nil {
+			t.Errorf("image for platform %q was not pushed: %v", platform, err)
+		}
+	}
+
+	// The function's tag should hold a manifest list with one entry per
+	// platform.
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc, err := remote.Get(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.MediaType != gcrTypes.OCIImageIndex {
+		t.Fatalf("expected a manifest list at %q, got media type %q", image, desc.MediaType)
+	}
+	idx, err := desc.ImageIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Manifests) != len(testPlatforms) {
+		t.Fatalf("expected %d manifests, got %d", len(testPlatforms), len(manifest.Manifests))
+	}
+	for i, platform := range testPlatforms {
+		actual := manifest.Manifests[i].Platform
+		if actual == nil || platform != actual.OS+"/"+actual.Architecture {
+			t.Errorf("expected manifest %d to be for platform %q, got %v", i, platform, actual)
+		}
+	}
+
+	// The returned digest should be that of the manifest list.
+	expectedDigest, err := idx.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actualDigest != expectedDigest.String() {
+		t.Errorf("expected the manifest list digest %q, got %q", expectedDigest, actualDigest)
+	}
+}
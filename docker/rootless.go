@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FuncRootless is the environment variable through which rootless operation
+// can be explicitly enabled ("true") or disabled ("false").  When unset,
+// rootless operation is auto-detected.
+const FuncRootless = "FUNC_ROOTLESS"
+
+// RootlessDockerHost returns the docker host (socket URL) of the current
+// user's rootless docker/podman daemon, and whether it should be used.
+// Precedence:
+//   - FUNC_ROOTLESS=false: rootless operation is never used.
+//   - FUNC_ROOTLESS=true: the per-user socket is used even if detection
+//     would have chosen otherwise (useful when detection misfires).
+//   - unset: the per-user socket is used when the process is not running as
+//     root and the socket exists.
+func RootlessDockerHost() (dockerHost string, ok bool) {
+	switch os.Getenv(FuncRootless) {
+	case "false":
+		return "", false
+	case "true":
+		return rootlessSocket(), true
+	}
+
+	// Auto-detect:  a non-root user with a per-user daemon socket present.
+	if os.Geteuid() == 0 {
+		return "", false
+	}
+	socket := rootlessSocket()
+	if _, err := os.Stat(strings.TrimPrefix(socket, "unix://")); err != nil {
+		return "", false
+	}
+	return socket, true
+}
+
+// rootlessSocket returns the expected location of the per-user daemon
+// socket: $XDG_RUNTIME_DIR/docker.sock, falling back to
+// /run/user/<uid>/docker.sock.
+func rootlessSocket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+	return "unix://" + filepath.Join(dir, "docker.sock")
+}
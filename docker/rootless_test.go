@@ -0,0 +1,65 @@
+package docker_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"knative.dev/kn-plugin-func/docker"
+)
+
+// TestRootlessDockerHost_Disabled ensures rootless operation is never used
+// when explicitly disabled.
+func TestRootlessDockerHost_Disabled(t *testing.T) {
+	t.Setenv(docker.FuncRootless, "false")
+	if _, ok := docker.RootlessDockerHost(); ok {
+		t.Fatal("rootless host returned despite being explicitly disabled")
+	}
+}
+
+// TestRootlessDockerHost_Enabled ensures that when rootless operation is
+// explicitly enabled the client is configured with the per-user socket, even
+// if auto-detection would have chosen otherwise.
+func TestRootlessDockerHost_Enabled(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	t.Setenv(docker.FuncRootless, "true")
+
+	host, ok := docker.RootlessDockerHost()
+	if !ok {
+		t.Fatal("rootless host not returned despite being explicitly enabled")
+	}
+	expected := "unix://" + filepath.Join(runtimeDir, "docker.sock")
+	if host != expected {
+		t.Fatalf("expected rootless host '%v', got '%v'", expected, host)
+	}
+}
+
+// TestRootlessDockerHost_AutoDetect ensures auto-detection only chooses the
+// per-user socket when it exists and the process is not running as root.
+func TestRootlessDockerHost_AutoDetect(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	t.Setenv(docker.FuncRootless, "")
+
+	if os.Geteuid() == 0 {
+		// Root never auto-detects as rootless.
+		if _, ok := docker.RootlessDockerHost(); ok {
+			t.Fatal("rootless host auto-detected while running as root")
+		}
+		return
+	}
+
+	// Without the socket present, detection declines.
+	if _, ok := docker.RootlessDockerHost(); ok {
+		t.Fatal("rootless host auto-detected without a socket present")
+	}
+
+	// With the socket present, detection chooses it.
+	if err := os.WriteFile(filepath.Join(runtimeDir, "docker.sock"), []byte{}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := docker.RootlessDockerHost(); !ok {
+		t.Fatal("rootless host not auto-detected despite socket present")
+	}
+}
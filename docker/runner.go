@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -15,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 
 	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/k8s"
 )
 
 const (
@@ -34,19 +38,71 @@ const (
 
 // Runner starts and stops functions as local containers.
 type Runner struct {
-	verbose bool // Verbose logging
+	verbose      bool     // Verbose logging
+	mountVolumes bool     // Materialize and mount the function's volumes
+	port         string   // Explicitly requested host port (default: choose)
+	portFallback bool     // Use an unused port when the requested is taken
+	extraEnvs    []fn.Env // Additional, unpersisted environment variables
+}
+
+// RunnerOpt allows customization of the runner.
+type RunnerOpt func(*Runner)
+
+// WithVolumeMounts instructs the runner to materialize the Secrets and
+// ConfigMaps referenced by the function's volumes from the currently
+// connected cluster and mount them into the local container at their
+// configured paths, such that the function behaves the same locally as
+// when deployed.  Disabled by default.
+func WithVolumeMounts(enabled bool) RunnerOpt {
+	return func(r *Runner) {
+		r.mountVolumes = enabled
+	}
+}
+
+// WithPort requests a specific host port on which the function will be
+// served rather than allowing the runner to choose.  If the port is
+// already in use the run fails with a clear error, unless fallback to an
+// unused port is enabled (see WithRunPortFallback).
+func WithPort(port string) RunnerOpt {
+	return func(r *Runner) {
+		r.port = port
+	}
+}
+
+// WithExtraEnvs provides additional environment variables to set in the
+// running container.  Unlike the function's configured envs these are not
+// persisted to func.yaml, and configured envs take precedence on conflict.
+// Used, for example, for local development variables loaded from an
+// uncommitted .env file.
+func WithExtraEnvs(envs []fn.Env) RunnerOpt {
+	return func(r *Runner) {
+		r.extraEnvs = envs
+	}
+}
+
+// WithRunPortFallback instructs the runner to select an unused port when
+// the explicitly requested port (see WithPort) is already in use, rather
+// than failing.  The effective port is reported on the returned Job.
+func WithRunPortFallback(enabled bool) RunnerOpt {
+	return func(r *Runner) {
+		r.portFallback = enabled
+	}
 }
 
 // NewRunner creates an instance of a docker-backed runner.
-func NewRunner(verbose bool) *Runner {
-	return &Runner{verbose: verbose}
+func NewRunner(verbose bool, opts ...RunnerOpt) *Runner {
+	r := &Runner{verbose: verbose}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Run the function.
 func (n *Runner) Run(ctx context.Context, f fn.Function) (job *fn.Job, err error) {
 
 	var (
-		port = choosePort(DefaultHost, DefaultPort, DefaultDialTimeout)
+		port string                 // Effective host port
 		c    client.CommonAPIClient // Docker client
 		id   string                 // ID of running container
 		conn net.Conn               // Connection to container's stdio
@@ -60,13 +116,37 @@ func (n *Runner) Run(ctx context.Context, f fn.Function) (job *fn.Job, err error
 		runtimeErrCh = make(chan error, 10)
 	)
 
+	// Additional envs are evaluated before the function's own such that, on
+	// conflict, the function's configured (persisted) envs take precedence.
+	if len(n.extraEnvs) > 0 {
+		f.Run.Envs = append(append([]fn.Env{}, n.extraEnvs...), f.Run.Envs...)
+	}
+
 	if f.Image == "" {
 		return job, errors.New("Function has no associated image. Has it been built?")
 	}
+	if port, err = n.effectivePort(); err != nil {
+		return job, err
+	}
 	if c, _, err = NewClient(client.DefaultDockerHost); err != nil {
 		return job, errors.Wrap(err, "failed to create Docker API client")
 	}
-	if id, err = newContainer(ctx, c, f, port, n.verbose); err != nil {
+
+	// Materialize the function's volumes locally (if so configured) such that
+	// they can be bind-mounted into the container at their configured paths.
+	var (
+		binds          []string
+		volumesCleanup = func() {}
+	)
+	if n.mountVolumes && len(f.Run.Volumes) > 0 {
+		if binds, volumesCleanup, err = materializeVolumes(ctx, f); err != nil {
+			volumesCleanup()
+			return job, err
+		}
+	}
+
+	if id, err = newContainer(ctx, c, f, port, n.verbose, binds); err != nil {
+		volumesCleanup()
 		return job, errors.Wrap(err, "runner unable to create container")
 	}
 	if conn, err = copyStdio(ctx, c, id, copyErrCh); err != nil {
@@ -117,12 +197,31 @@ func (n *Runner) Run(ctx context.Context, f fn.Function) (job *fn.Job, err error
 		if err = c.Close(); err != nil {
 			fmt.Fprintf(os.Stderr, "error closing daemon client: %v\n", err)
 		}
+		volumesCleanup()
 	}
 
 	// Job reporting port, runtime errors and provides a mechanism for stopping.
 	return fn.NewJob(f, port, runtimeErrCh, stop)
 }
 
+// effectivePort returns the host port on which to serve:  the explicitly
+// requested port if set (an unused port when it is taken and fallback is
+// enabled, an error when it is not), or an unused port preferring the
+// default.
+func (n *Runner) effectivePort() (string, error) {
+	if n.port == "" {
+		return choosePort(DefaultHost, DefaultPort, DefaultDialTimeout), nil
+	}
+	// The requested port being unreachable implies it is available.
+	if err := dial(DefaultHost, n.port, DefaultDialTimeout); err != nil {
+		return n.port, nil
+	}
+	if !n.portFallback {
+		return "", fmt.Errorf("port %v is already in use", n.port)
+	}
+	return unusedPort(DefaultHost), nil
+}
+
 // Dial the given (tcp) port on the given interface, returning an error if it is
 // unreachable.
 func dial(host, port string, dialTimeout time.Duration) (err error) {
@@ -144,8 +243,12 @@ func choosePort(host string, preferredPort string, dialTimeout time.Duration) st
 	if err := dial(host, preferredPort, dialTimeout); err != nil {
 		return preferredPort
 	}
+	return unusedPort(host)
+}
 
-	// Use an OS-chosen port
+// unusedPort returns an OS-chosen unused port, falling back to the default
+// should the OS be unable to provide one.
+func unusedPort(host string) string {
 	lis, err := net.Listen("tcp", net.JoinHostPort(host, "")) // listen on any open port
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "unable to check for open ports. using fallback %v. %v", DefaultPort, err)
@@ -162,15 +265,15 @@ func choosePort(host string, preferredPort string, dialTimeout time.Duration) st
 
 }
 
-func newContainer(ctx context.Context, c client.CommonAPIClient, f fn.Function, port string, verbose bool) (id string, err error) {
+func newContainer(ctx context.Context, c client.CommonAPIClient, f fn.Function, port string, verbose bool, binds []string) (id string, err error) {
 	var (
 		containerCfg container.Config
 		hostCfg      container.HostConfig
 	)
-	if containerCfg, err = newContainerConfig(f, port, verbose); err != nil {
+	if containerCfg, err = newContainerConfig(ctx, f, port, verbose); err != nil {
 		return
 	}
-	if hostCfg, err = newHostConfig(port); err != nil {
+	if hostCfg, err = newHostConfig(port, binds); err != nil {
 		return
 	}
 	t, err := c.ContainerCreate(ctx, &containerCfg, &hostCfg, nil, nil, "")
@@ -180,7 +283,7 @@ func newContainer(ctx context.Context, c client.CommonAPIClient, f fn.Function,
 	return t.ID, nil
 }
 
-func newContainerConfig(f fn.Function, _ string, verbose bool) (c container.Config, err error) {
+func newContainerConfig(ctx context.Context, f fn.Function, _ string, verbose bool) (c container.Config, err error) {
 	// httpPort := nat.Port(fmt.Sprintf("%v/tcp", port))
 	httpPort := nat.Port("8080/tcp")
 	c = container.Config{
@@ -192,6 +295,16 @@ func newContainerConfig(f fn.Function, _ string, verbose bool) (c container.Conf
 		ExposedPorts: map[nat.Port]struct{}{httpPort: {}},
 	}
 
+	// An explicitly configured command and/or args override the image's
+	// entrypoint and command; the image defaults are used otherwise, matching
+	// the behavior of the deployed function.
+	if len(f.Run.Command) > 0 {
+		c.Entrypoint = f.Run.Command
+	}
+	if len(f.Run.Args) > 0 {
+		c.Cmd = f.Run.Args
+	}
+
 	// Environment Variables
 	// Interpolate references to local environment variables and convert to a
 	// simple string slice for use with container.Config
@@ -199,6 +312,12 @@ func newContainerConfig(f fn.Function, _ string, verbose bool) (c container.Conf
 	if err != nil {
 		return
 	}
+	// Values referencing a cluster Secret key are resolved against the
+	// currently connected cluster such that secret-backed config behaves the
+	// same locally as when deployed.
+	if envs, err = resolveSecretEnvs(ctx, envs, f.Deploy.Namespace); err != nil {
+		return
+	}
 	for k, v := range envs {
 		c.Env = append(c.Env, k+"="+v)
 	}
@@ -209,7 +328,39 @@ func newContainerConfig(f fn.Function, _ string, verbose bool) (c container.Conf
 	return
 }
 
-func newHostConfig(port string) (c container.HostConfig, err error) {
+// secretEnvPattern matches env values of the form "{{ secret:name:key }}",
+// mirroring the format supported by the deployer.
+var secretEnvPattern = regexp.MustCompile(`^{{\s*secret:((?:\w|['-]\w)+):([-._a-zA-Z0-9]+)\s*}}$`)
+
+// getSecret fetches the named Secret from the currently connected cluster.
+// A variable such that tests can substitute a stub for the cluster.
+var getSecret = k8s.GetSecret
+
+// resolveSecretEnvs replaces env values of the form "{{ secret:name:key }}"
+// with the value of the named key of the Secret fetched from the cluster.
+// Values in other formats are returned unmodified.  An unreachable cluster
+// or a missing Secret/key results in an error naming the unresolved env.
+func resolveSecretEnvs(ctx context.Context, envs map[string]string, namespace string) (map[string]string, error) {
+	for name, value := range envs {
+		match := secretEnvPattern.FindStringSubmatch(value)
+		if len(match) != 3 {
+			continue
+		}
+		secretName, key := match[1], match[2]
+		secret, err := getSecret(ctx, secretName, namespace)
+		if err != nil {
+			return envs, fmt.Errorf("cannot resolve env %q: unable to fetch secret %q from the cluster: %w", name, secretName, err)
+		}
+		content, ok := secret.Data[key]
+		if !ok {
+			return envs, fmt.Errorf("cannot resolve env %q: secret %q has no key %q", name, secretName, key)
+		}
+		envs[name] = string(content)
+	}
+	return envs, nil
+}
+
+func newHostConfig(port string, binds []string) (c container.HostConfig, err error) {
 	// httpPort := nat.Port(fmt.Sprintf("%v/tcp", port))
 	httpPort := nat.Port("8080/tcp")
 	ports := map[nat.Port][]nat.PortBinding{
@@ -220,7 +371,59 @@ func newHostConfig(port string) (c container.HostConfig, err error) {
 			},
 		},
 	}
-	return container.HostConfig{PortBindings: ports}, nil
+	return container.HostConfig{PortBindings: ports, Binds: binds}, nil
+}
+
+// materializeVolumes fetches the contents of the Secrets and ConfigMaps
+// referenced by the function's volumes from the currently connected cluster
+// and writes them into temporary directories, returning docker bind
+// specifications mounting those directories at the volumes' configured
+// paths.  The returned cleanup function removes the temporary directories.
+func materializeVolumes(ctx context.Context, f fn.Function) (binds []string, cleanup func(), err error) {
+	tmp, err := os.MkdirTemp("", "func-volumes")
+	if err != nil {
+		return nil, func() {}, err
+	}
+	cleanup = func() { _ = os.RemoveAll(tmp) }
+
+	for i, v := range f.Run.Volumes {
+		if v.Path == nil {
+			continue
+		}
+		data := map[string][]byte{}
+		if v.Secret != nil {
+			secret, e := k8s.GetSecret(ctx, *v.Secret, f.Deploy.Namespace)
+			if e != nil {
+				err = fmt.Errorf("cannot fetch secret %q for volume mounted at %q: %w", *v.Secret, *v.Path, e)
+				return
+			}
+			data = secret.Data
+		} else if v.ConfigMap != nil {
+			cm, e := k8s.GetConfigMap(ctx, *v.ConfigMap, f.Deploy.Namespace)
+			if e != nil {
+				err = fmt.Errorf("cannot fetch configMap %q for volume mounted at %q: %w", *v.ConfigMap, *v.Path, e)
+				return
+			}
+			for k, val := range cm.Data {
+				data[k] = []byte(val)
+			}
+			for k, val := range cm.BinaryData {
+				data[k] = val
+			}
+		}
+
+		dir := filepath.Join(tmp, strconv.Itoa(i))
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return
+		}
+		for name, content := range data {
+			if err = os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+				return
+			}
+		}
+		binds = append(binds, dir+":"+*v.Path)
+	}
+	return
 }
 
 // copy stdin and stdout from the container of the given ID.  Errors encountered
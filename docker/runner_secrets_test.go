@@ -0,0 +1,87 @@
+//go:build !integration
+// +build !integration
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// withSecretStub replaces the cluster Secret lookup with the given stub for
+// the duration of the test.
+func withSecretStub(t *testing.T, stub func(ctx context.Context, name, namespace string) (*corev1.Secret, error)) {
+	t.Helper()
+	old := getSecret
+	getSecret = stub
+	t.Cleanup(func() { getSecret = old })
+}
+
+// TestResolveSecretEnvs ensures that env values of the form
+// "{{ secret:name:key }}" are replaced with the value of the named key of
+// the Secret from the cluster, and that values in other formats pass
+// through unmodified.
+func TestResolveSecretEnvs(t *testing.T) {
+	withSecretStub(t, func(ctx context.Context, name, namespace string) (*corev1.Secret, error) {
+		if name != "creds" {
+			return nil, errors.New("secret not found")
+		}
+		return &corev1.Secret{Data: map[string][]byte{"token": []byte("opensesame")}}, nil
+	})
+
+	envs := map[string]string{
+		"TOKEN":  "{{ secret:creds:token }}",
+		"PLAIN":  "a plain value",
+		"BRACES": "{{ not a secret reference }}",
+	}
+	envs, err := resolveSecretEnvs(context.Background(), envs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if envs["TOKEN"] != "opensesame" {
+		t.Errorf("expected the secret-backed env to resolve to 'opensesame', got %q", envs["TOKEN"])
+	}
+	if envs["PLAIN"] != "a plain value" {
+		t.Errorf("expected the plain env to pass through unmodified, got %q", envs["PLAIN"])
+	}
+	if envs["BRACES"] != "{{ not a secret reference }}" {
+		t.Errorf("expected the non-matching templated env to pass through unmodified, got %q", envs["BRACES"])
+	}
+}
+
+// TestResolveSecretEnvs_Missing ensures that a reference to a Secret (or a
+// key) which does not exist on the cluster results in an error naming the
+// unresolvable env.
+func TestResolveSecretEnvs_Missing(t *testing.T) {
+	withSecretStub(t, func(ctx context.Context, name, namespace string) (*corev1.Secret, error) {
+		if name != "creds" {
+			return nil, errors.New("secret not found")
+		}
+		return &corev1.Secret{Data: map[string][]byte{"token": []byte("opensesame")}}, nil
+	})
+
+	// Reference to a Secret which does not exist
+	envs := map[string]string{"TOKEN": "{{ secret:nosuchsecret:token }}"}
+	_, err := resolveSecretEnvs(context.Background(), envs, "")
+	if err == nil {
+		t.Fatal("expected an error resolving a reference to a missing secret")
+	}
+	if !strings.Contains(err.Error(), `"TOKEN"`) {
+		t.Errorf("expected the error to name the unresolvable env, got: %v", err)
+	}
+
+	// Reference to a key the Secret does not contain
+	envs = map[string]string{"TOKEN": "{{ secret:creds:nosuchkey }}"}
+	_, err = resolveSecretEnvs(context.Background(), envs, "")
+	if err == nil {
+		t.Fatal("expected an error resolving a reference to a missing key")
+	}
+	if !strings.Contains(err.Error(), `"TOKEN"`) {
+		t.Errorf("expected the error to name the unresolvable env, got: %v", err)
+	}
+}
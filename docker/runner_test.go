@@ -7,7 +7,9 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"strings"
 	"testing"
 
 	fn "knative.dev/kn-plugin-func"
@@ -57,3 +59,59 @@ func TestDockerRunImagelessError(t *testing.T) {
 		t.Fatalf("Expected error '%v', got '%v'", expectedErrorMessage, err)
 	}
 }
+
+// TestDockerRunPortInUseError ensures that requesting a specific port which
+// is already occupied yields a clear error rather than a cryptic failure.
+func TestDockerRunPortInUseError(t *testing.T) {
+	// Occupy a port
+	lis, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := docker.NewRunner(false, docker.WithPort(port))
+	f := fn.NewFunctionWith(fn.Function{Image: "example.com/alice/runnable:latest"})
+
+	_, err = runner.Run(context.Background(), f)
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("port %v is already in use", port)) {
+		t.Fatalf("expected 'port in use' error, got '%v'", err)
+	}
+}
+
+// TestDockerRunPortFallback ensures that, with fallback enabled, an occupied
+// requested port is not an error:  the runner selects an unused port instead
+// (reported on the Job when a docker daemon is available).
+func TestDockerRunPortFallback(t *testing.T) {
+	// Occupy a port
+	lis, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := docker.NewRunner(false, docker.WithPort(port), docker.WithRunPortFallback(true))
+	f := fn.NewFunctionWith(fn.Function{Image: "example.com/alice/runnable:latest"})
+
+	job, err := runner.Run(context.Background(), f)
+	if err != nil {
+		// Runs in environments without a docker daemon fail later for
+		// unrelated reasons; assert only that the occupied port was not.
+		if strings.Contains(err.Error(), "in use") {
+			t.Fatalf("fallback did not avoid the occupied port: %v", err)
+		}
+		return
+	}
+	defer job.Stop()
+	if job.Port == port {
+		t.Fatalf("fallback selected the occupied port %v", port)
+	}
+}
@@ -2,6 +2,7 @@ package function
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
@@ -229,7 +230,7 @@ func initGitFS(t *testing.T) Filesystem {
 
 	uri := fmt.Sprintf(`file://%s`, filepath.ToSlash(repoDir))
 
-	result, err := filesystemFromRepo(uri)
+	result, err := filesystemFromRepo(context.Background(), uri)
 	if err != nil {
 		t.Fatal(err)
 	}
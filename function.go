@@ -1,6 +1,7 @@
 package function
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -11,7 +12,9 @@ import (
 	"time"
 
 	"gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
 	fnlabels "knative.dev/kn-plugin-func/k8s/labels"
+	"knative.dev/kn-plugin-func/utils"
 	"knative.dev/pkg/ptr"
 )
 
@@ -91,12 +94,30 @@ type BuildSpec struct {
 	// Optional list of buildpacks to use when building the function
 	Buildpacks []string `yaml:"buildpacks"`
 
+	// RunImage optionally overrides the base image the built function runs
+	// on (the final stage base), independent of the builder image used to
+	// compile it.  Useful for compliance-approved base images.  When empty
+	// the builder's built-in run image is used.
+	RunImage string `yaml:"runImage,omitempty"`
+
 	// Builder is the name of the subsystem that will complete the underlying
 	// build (pack, s2i, etc)
 	Builder string `yaml:"builder" jsonschema:"enum=pack,enum=s2i"`
 
 	// Build Env variables to be set
 	BuildEnvs []Env `yaml:"buildEnvs"`
+
+	// BuildArgs are arguments passed through to the build tool itself
+	// (e.g. a Go build flag, a Node build script name), keyed by name.
+	// Unlike BuildEnvs these are build-tool directives and do not become
+	// environment variables in the resulting container.
+	BuildArgs map[string]string `yaml:"buildArgs,omitempty"`
+
+	// PlatformBuildEnvs are build env variables scoped to a target platform
+	// (e.g. "linux/arm64"), applied in addition to BuildEnvs only when
+	// building for that platform.  Used for arch-specific build flags during
+	// multi-platform builds.
+	PlatformBuildEnvs map[string][]Env `yaml:"platformBuildEnvs,omitempty"`
 }
 
 // RunSpec
@@ -106,6 +127,56 @@ type RunSpec struct {
 
 	// Env variables to be set
 	Envs []Env `yaml:"envs"`
+
+	// ImagePullPolicy applied to the function's container on supported
+	// platforms (Always, IfNotPresent or Never).  When empty the platform's
+	// default is used.  Useful when iterating with mutable tags, where a
+	// cached image may otherwise be run.
+	ImagePullPolicy string `yaml:"imagePullPolicy,omitempty" jsonschema:"enum=Always,enum=IfNotPresent,enum=Never"`
+
+	// Resources requests and limits (cpu, memory) applied to the function's
+	// container on deploy.  When empty the platform's defaults are used.
+	Resources *ResourcesOptions `yaml:"resources,omitempty"`
+
+	// ServiceAccountName of the Kubernetes service account the deployed
+	// function runs as.  When empty the namespace's default service account
+	// is used.  Useful for functions which access the Kubernetes API or rely
+	// on identity-based cloud credentials (Workload Identity, IRSA).
+	ServiceAccountName string `yaml:"serviceAccountName,omitempty"`
+
+	// Command overrides the image's entrypoint when running the function,
+	// both locally and when deployed.  Useful for wrapping the function with
+	// an init script or profiler.  When empty the image default is used.
+	Command []string `yaml:"command,omitempty"`
+
+	// Args overrides the arguments passed to the image's entrypoint (or to
+	// Command, when also set).  When empty the image default is used.
+	Args []string `yaml:"args,omitempty"`
+
+	// InitContainers run to completion, in order, before the function's
+	// container starts.  Useful for setup tasks such as running a migration
+	// or fetching configuration.  Volumes configured on the function are
+	// also mounted into init containers.  When empty, behavior is unchanged.
+	InitContainers []InitContainer `yaml:"initContainers,omitempty"`
+}
+
+// InitContainer is a container run to completion before the function's
+// container starts.
+type InitContainer struct {
+	// Name of the init container.  When empty a name is generated from its
+	// position in the list.
+	Name string `yaml:"name,omitempty"`
+
+	// Image run by the init container.  Required.
+	Image string `yaml:"image"`
+
+	// Command run in the image.  When empty the image's entrypoint is used.
+	Command []string `yaml:"command,omitempty"`
+
+	// Env variables to be set in the init container.  Supports the same
+	// sources as the function's env variables (values, local environment
+	// interpolation, Secrets and ConfigMaps).
+	Envs []Env `yaml:"envs,omitempty"`
 }
 
 // DeploySpec
@@ -123,14 +194,58 @@ type DeploySpec struct {
 	// Map of user-supplied labels
 	Labels []Label `yaml:"labels"`
 
+	// Subscriptions of the function to event sources, reapplied (as
+	// Triggers or the equivalent) on each deploy.  See Client.Subscribe.
+	Subscriptions []Subscription `yaml:"subscriptions,omitempty"`
+
 	// Health endpoints specified by the language pack
 	HealthEndpoints HealthEndpoints `yaml:"healthEndpoints"`
 }
 
 // HealthEndpoints specify the liveness and readiness endpoints for a Runtime
+// along with optional probe timings, applied to both probes when set.
+// Timings left unset defer to the platform's defaults.
 type HealthEndpoints struct {
 	Liveness  string `yaml:"liveness,omitempty"`
 	Readiness string `yaml:"readiness,omitempty"`
+
+	// InitialDelaySeconds to wait before the first probe, allowing
+	// slow-starting functions time to come up before being killed.
+	InitialDelaySeconds *int32 `yaml:"initialDelaySeconds,omitempty"`
+
+	// PeriodSeconds between successive probes.
+	PeriodSeconds *int32 `yaml:"periodSeconds,omitempty"`
+
+	// FailureThreshold is the number of consecutive probe failures after
+	// which the function is considered unhealthy.
+	FailureThreshold *int32 `yaml:"failureThreshold,omitempty"`
+
+	// Endpoints optionally specifies health endpoints by probe type,
+	// taking precedence over the simple Liveness and Readiness paths for
+	// their type, and additionally supporting a dedicated "startup" probe
+	// (for slow-starting functions) and custom ports.
+	Endpoints []HealthEndpoint `yaml:"endpoints,omitempty"`
+}
+
+// Health endpoint probe types.
+const (
+	ProbeLiveness  = "liveness"
+	ProbeReadiness = "readiness"
+	ProbeStartup   = "startup"
+)
+
+// HealthEndpoint is a single health endpoint with an explicit probe type.
+type HealthEndpoint struct {
+	// Type of probe the endpoint backs: "liveness", "readiness" or
+	// "startup".  Each type may be specified at most once.
+	Type string `yaml:"type" jsonschema:"enum=liveness,enum=readiness,enum=startup"`
+
+	// Path of the endpoint.  Must be absolute.
+	Path string `yaml:"path"`
+
+	// Port on which the endpoint is served.  The default of zero probes
+	// the port on which the function itself is serving.
+	Port int32 `yaml:"port,omitempty"`
 }
 
 // BuildConfig defines builders and buildpacks
@@ -162,6 +277,43 @@ func NewFunctionWith(defaults Function) Function {
 	return defaults
 }
 
+// NewFunctionFromCwd loads the function whose root is the nearest ancestor
+// of the current working directory (inclusive) which contains a function,
+// allowing commands to be run from subdirectories of a project.
+func NewFunctionFromCwd() (Function, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return Function{}, err
+	}
+	root, err := SearchFunctionRoot(cwd)
+	if err != nil {
+		return Function{}, err
+	}
+	return NewFunction(root)
+}
+
+// SearchFunctionRoot returns the nearest ancestor of path (inclusive) which
+// contains a function (a func.yaml), in the same way git resolves its
+// repository root from a subdirectory.  If no ancestor contains a function
+// the given path is returned unmodified, such that subsequent loading
+// errors report the path actually requested.
+func SearchFunctionRoot(path string) (string, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return path, err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, FunctionFile)); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir { // reached the filesystem root
+			return path, nil
+		}
+		dir = parent
+	}
+}
+
 // NewFunction from a given path.
 // Invalid paths, or no function at path are errors.
 // Syntactic errors are returned immediately (yaml unmarshal errors).
@@ -221,10 +373,16 @@ func (f Function) Validate() error {
 	errs := [][]string{
 		validateVolumes(f.Run.Volumes),
 		ValidateBuildEnvs(f.Build.BuildEnvs),
+		validatePlatformBuildEnvs(f.Build.PlatformBuildEnvs),
 		ValidateEnvs(f.Run.Envs),
 		validateOptions(f.Deploy.Options),
 		ValidateLabels(f.Deploy.Labels),
 		validateGit(f.Build.Git),
+		validateServiceAccount(f.Run.ServiceAccountName),
+		validateRunCommand("command", f.Run.Command),
+		validateRunCommand("args", f.Run.Args),
+		validateHealthEndpoints(f.Deploy.HealthEndpoints),
+		validateInitContainers(f.Run.InitContainers),
 	}
 
 	var b strings.Builder
@@ -247,6 +405,68 @@ func (f Function) Validate() error {
 	return errors.New(b.String())
 }
 
+// validateServiceAccount checks that the run spec's service account name, when
+// provided, is a valid Kubernetes service account name.
+func validateServiceAccount(name string) (errors []string) {
+	if name == "" {
+		return // optional; empty uses the namespace default
+	}
+	if err := utils.ValidateServiceAccountName(name); err != nil {
+		errors = append(errors, err.Error())
+	}
+	return
+}
+
+// validateRunCommand checks that the elements of a command or args override,
+// when provided, are non-empty.
+func validateRunCommand(field string, values []string) (errors []string) {
+	for i, v := range values {
+		if strings.TrimSpace(v) == "" {
+			errors = append(errors, fmt.Sprintf("%v element %v is empty", field, i))
+		}
+	}
+	return
+}
+
+// validateInitContainers checks that each init container, when provided,
+// has an image, and that its env variables are correctly set.
+func validateInitContainers(containers []InitContainer) (errors []string) {
+	for i, c := range containers {
+		if c.Image == "" {
+			errors = append(errors, fmt.Sprintf("init container entry #%d is missing an image", i))
+		}
+		for _, msg := range ValidateEnvs(c.Envs) {
+			errors = append(errors, fmt.Sprintf("init container entry #%d %s", i, msg))
+		}
+	}
+	return
+}
+
+// validateHealthEndpoints checks that typed health endpoints, when provided,
+// have a recognized probe type, an absolute path and a valid port, and that
+// no probe type appears more than once.
+func validateHealthEndpoints(he HealthEndpoints) (errors []string) {
+	seen := map[string]bool{}
+	for i, e := range he.Endpoints {
+		switch e.Type {
+		case ProbeLiveness, ProbeReadiness, ProbeStartup:
+			if seen[e.Type] {
+				errors = append(errors, fmt.Sprintf("health endpoint entry #%d has duplicate type %q", i, e.Type))
+			}
+			seen[e.Type] = true
+		default:
+			errors = append(errors, fmt.Sprintf("health endpoint entry #%d has invalid type %q; allowed are %q, %q and %q", i, e.Type, ProbeLiveness, ProbeReadiness, ProbeStartup))
+		}
+		if !strings.HasPrefix(e.Path, "/") {
+			errors = append(errors, fmt.Sprintf("health endpoint entry #%d path %q is not absolute", i, e.Path))
+		}
+		if e.Port < 0 || e.Port > 65535 {
+			errors = append(errors, fmt.Sprintf("health endpoint entry #%d has invalid port %d", i, e.Port))
+		}
+	}
+	return
+}
+
 var envPattern = regexp.MustCompile(`^{{\s*(\w+)\s*:(\w+)\s*}}$`)
 
 // Interpolate Env slice
@@ -316,17 +536,92 @@ func nameFromPath(path string) string {
 }
 
 // Write aka (save, serialize, marshal) the function to disk at its path.
+// Comments in a preexisting func.yaml are preserved (see preserveComments).
 func (f Function) Write() (err error) {
 	path := filepath.Join(f.Root, FunctionFile)
 	var bb []byte
 	if bb, err = yaml.Marshal(&f); err != nil {
 		return
 	}
+	// When overwriting, preserve any comments the user has added to the file
+	// by applying the new values onto the existing document tree rather than
+	// replacing the document wholesale.
+	if old, e := os.ReadFile(path); e == nil {
+		if bb, err = preserveComments(old, bb); err != nil {
+			return
+		}
+	}
 	// TODO: open existing file for writing, such that existing permissions
 	// are preserved.
 	return os.WriteFile(path, bb, 0644)
 }
 
+// preserveComments returns the serialization of the new document with
+// comments from the old document applied to its equivalent nodes, such that
+// hand-written documentation in func.yaml survives rewrites.
+func preserveComments(old, new []byte) ([]byte, error) {
+	var oldDoc, newDoc yaml3.Node
+	if err := yaml3.Unmarshal(old, &oldDoc); err != nil {
+		// An unparseable preexisting file is simply replaced.
+		return new, nil
+	}
+	if err := yaml3.Unmarshal(new, &newDoc); err != nil {
+		return nil, err
+	}
+	copyComments(&oldDoc, &newDoc)
+
+	var b bytes.Buffer
+	e := yaml3.NewEncoder(&b)
+	e.SetIndent(2)
+	if len(newDoc.Content) == 0 {
+		return new, nil
+	}
+	if err := e.Encode(newDoc.Content[0]); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// copyComments recursively copies comments from nodes of the old document
+// onto the equivalent (same key or index) nodes of the new.
+func copyComments(old, new *yaml3.Node) {
+	if old == nil || new == nil {
+		return
+	}
+	if new.HeadComment == "" {
+		new.HeadComment = old.HeadComment
+	}
+	if new.LineComment == "" {
+		new.LineComment = old.LineComment
+	}
+	if new.FootComment == "" {
+		new.FootComment = old.FootComment
+	}
+	switch {
+	case old.Kind == yaml3.DocumentNode && new.Kind == yaml3.DocumentNode:
+		if len(old.Content) > 0 && len(new.Content) > 0 {
+			copyComments(old.Content[0], new.Content[0])
+		}
+	case old.Kind == yaml3.MappingNode && new.Kind == yaml3.MappingNode:
+		for i := 0; i+1 < len(old.Content); i += 2 {
+			for j := 0; j+1 < len(new.Content); j += 2 {
+				if old.Content[i].Value == new.Content[j].Value {
+					copyComments(old.Content[i], new.Content[j])     // key
+					copyComments(old.Content[i+1], new.Content[j+1]) // value
+					break
+				}
+			}
+		}
+	case old.Kind == yaml3.SequenceNode && new.Kind == yaml3.SequenceNode:
+		for i := 0; i < len(old.Content) && i < len(new.Content); i++ {
+			copyComments(old.Content[i], new.Content[i])
+		}
+	}
+}
+
 // Initialized returns if the function has been initialized.
 // Any errors are considered failure (invalid or inaccessible root, config file, etc).
 func (f Function) Initialized() bool {
@@ -364,6 +659,37 @@ func (f Function) ImageWithDigest() string {
 	return part1 + strings.Split(part2, ":")[0] + "@" + f.ImageDigest
 }
 
+// ParseImage splits an image reference into its name and (optional) digest,
+// validating that any digest provided is a well-formed sha256.  Used when
+// accepting externally built images, for which a pinned digest indicates
+// the build and push phases are unnecessary.
+func ParseImage(v string) (name, digest string, err error) {
+	vv := strings.Split(v, "@")
+	if len(vv) > 2 {
+		return v, "", fmt.Errorf("image '%s' contains more than one digest delimiter '@'", v)
+	}
+	if len(vv) < 2 {
+		name = v
+		return
+	}
+	name = vv[0]
+	digest = vv[1]
+
+	if name == "" {
+		return v, "", fmt.Errorf("image '%s' is missing a name preceding the digest", v)
+	}
+
+	if !strings.HasPrefix(digest, "sha256:") {
+		return v, "", fmt.Errorf("image '%s' has an invalid prefix syntax for digest (should be 'sha256:')", v)
+	}
+
+	if len(digest[7:]) != 64 {
+		return v, "", fmt.Errorf("sha256 hash in '%s' has the wrong length (%d), should be 64", digest, len(digest[7:]))
+	}
+
+	return
+}
+
 // LabelsMap combines default labels with the labels slice provided.
 // It will the resulting slice with ValidateLabels and return a key/value map.
 //   - key: EXAMPLE1                            # Label directly from a value
@@ -419,6 +745,23 @@ func (f Function) LabelsMap() (map[string]string, error) {
 	return l, nil
 }
 
+// ImageLabels returns the labels to be stamped onto images built for the
+// function:  the function's labels (see LabelsMap) plus standard OCI
+// provenance labels derived from function metadata.
+func (f Function) ImageLabels() (map[string]string, error) {
+	labels, err := f.LabelsMap()
+	if err != nil {
+		return nil, err
+	}
+	if !f.Created.IsZero() {
+		labels["org.opencontainers.image.created"] = f.Created.Format(time.RFC3339)
+	}
+	if f.Build.Git.URL != "" {
+		labels["org.opencontainers.image.source"] = f.Build.Git.URL
+	}
+	return labels, nil
+}
+
 // assertEmptyRoot ensures that the directory is empty enough to be used for
 // initializing a new function.
 func assertEmptyRoot(path string) (err error) {
@@ -486,10 +829,10 @@ func (f Function) ImageName() (image string, err error) {
 }
 
 // contentiousFiles are files which, if extant, preclude the creation of a
-// function rooted in the given directory.
+// function rooted in the given directory.  A pre-existing .gitignore is not
+// contentious as its content is preserved (the /.func entry is appended).
 var contentiousFiles = []string{
 	FunctionFile,
-	".gitignore",
 }
 
 // contentiousFilesIn the given directory
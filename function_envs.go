@@ -140,3 +140,15 @@ func ValidateBuildEnvs(envs []Env) (errors []string) {
 
 	return
 }
+
+// validatePlatformBuildEnvs checks that platform-scoped BuildEnvs are correct,
+// applying the same rules as ValidateBuildEnvs to each platform's entries.
+// Returns array of error messages, empty if no errors are found
+func validatePlatformBuildEnvs(platformEnvs map[string][]Env) (errors []string) {
+	for platform, envs := range platformEnvs {
+		for _, e := range ValidateBuildEnvs(envs) {
+			errors = append(errors, fmt.Sprintf("platform '%s' %s", platform, e))
+		}
+	}
+	return
+}
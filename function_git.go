@@ -11,6 +11,28 @@ type Git struct {
 	URL        string `yaml:"url,omitempty"`
 	Revision   string `yaml:"revision,omitempty"`
 	ContextDir string `yaml:"contextDir,omitempty"`
+
+	// Credentials is an optional reference to a cluster secret containing
+	// credentials for cloning a private repository.
+	Credentials GitCredentials `yaml:"credentials,omitempty"`
+}
+
+// GitCredentials references a cluster secret holding credentials for a
+// private Git repository.  Only the reference is serialized to func.yaml;
+// plaintext credentials are never written to disk.  The secret either
+// contains username/token entries (basic auth) or an SSH private key, with
+// the key names within the secret optionally overridden.
+type GitCredentials struct {
+	// SecretName is the name of the secret in the function's namespace.
+	SecretName string `yaml:"secretName,omitempty"`
+	// UsernameKey is the secret key holding the username (default "username").
+	UsernameKey string `yaml:"usernameKey,omitempty"`
+	// TokenKey is the secret key holding the token or password
+	// (default "password").
+	TokenKey string `yaml:"tokenKey,omitempty"`
+	// SSHPrivateKeyKey is the secret key holding an SSH private key.  When
+	// set, SSH authentication is used rather than basic auth.
+	SSHPrivateKeyKey string `yaml:"sshPrivateKeyKey,omitempty"`
 }
 
 // validateGit validates input Git option from Function config
@@ -30,5 +52,9 @@ func validateGit(git Git) (errors []string) {
 			errors = append(errors, errMsg)
 		}
 	}
+	if git.Credentials.SecretName == "" &&
+		(git.Credentials.UsernameKey != "" || git.Credentials.TokenKey != "" || git.Credentials.SSHPrivateKeyKey != "") {
+		errors = append(errors, "specified option \"git.credentials\" requires a secretName")
+	}
 	return
 }
@@ -29,6 +29,55 @@ func (l Label) String() string {
 	return ""
 }
 
+// reservedLabelPrefixes are key prefixes reserved for Kubernetes core
+// components; user-supplied labels must not use them.
+var reservedLabelPrefixes = []string{"kubernetes.io/", "k8s.io/"}
+
+// Validate checks that the label is correct and contains all necessary fields.
+// Returns an array of error messages, empty if no errors are found.  Each
+// message describes a single violation; all violations are reported, not just
+// the first.
+func (l Label) Validate() (errors []string) {
+	if l.Key == nil && l.Value == nil {
+		return append(errors, "is not properly set")
+	}
+	if l.Key == nil && l.Value != nil {
+		return append(errors, fmt.Sprintf("is missing key field, only value '%s' is set", *l.Value))
+	}
+
+	if err := utils.ValidateLabelKey(*l.Key); err != nil {
+		errors = append(errors, fmt.Sprintf("has invalid key set: %q; %s", *l.Key, err.Error()))
+	}
+	for _, prefix := range reservedLabelPrefixes {
+		if strings.HasPrefix(*l.Key, prefix) {
+			errors = append(errors, fmt.Sprintf("has key with prefix %q which is reserved for Kubernetes core components", prefix))
+		}
+	}
+	if l.Value != nil {
+		if err := utils.ValidateLabelValue(*l.Value); err != nil {
+			errors = append(errors, fmt.Sprintf("has invalid value set: %q; %s", *l.Value, err.Error()))
+		}
+
+		if strings.HasPrefix(*l.Value, "{{") {
+			// ENV from the local ENV var; {{ env:MY_ENV }}
+			if !regLocalEnv.MatchString(*l.Value) {
+				errors = append(errors,
+					fmt.Sprintf(
+						"with key '%s' has invalid value field set, it has '%s', but allowed is only '{{ env:MY_ENV }}'",
+						*l.Key, *l.Value))
+			} else {
+				match := regLocalEnv.FindStringSubmatch(*l.Value)
+				value := os.Getenv(match[1])
+				if err := utils.ValidateLabelValue(value); err != nil {
+					errors = append(errors, fmt.Sprintf("with key '%s' has invalid value when the environment is evaluated: '%s': %s", *l.Key, value, err.Error()))
+				}
+			}
+		}
+	}
+
+	return
+}
+
 // ValidateLabels checks that input labels are correct and contain all necessary fields.
 // Returns array of error messages, empty if no errors are found
 //
@@ -39,35 +88,8 @@ func (l Label) String() string {
 //   value: {{ env:MY_ENV }}
 func ValidateLabels(labels []Label) (errors []string) {
 	for i, label := range labels {
-		if label.Key == nil && label.Value == nil {
-			errors = append(errors, fmt.Sprintf("label entry #%d is not properly set", i))
-		} else if label.Key == nil && label.Value != nil {
-			errors = append(errors, fmt.Sprintf("label entry #%d is missing key field, only value '%s' is set", i, *label.Value))
-		} else {
-			if err := utils.ValidateLabelKey(*label.Key); err != nil {
-				errors = append(errors, fmt.Sprintf("label entry #%d has invalid key set: %q; %s", i, *label.Key, err.Error()))
-			}
-			if label.Value != nil {
-				if err := utils.ValidateLabelValue(*label.Value); err != nil {
-					errors = append(errors, fmt.Sprintf("label entry #%d has invalid value set: %q; %s", i, *label.Value, err.Error()))
-				}
-
-				if strings.HasPrefix(*label.Value, "{{") {
-					// ENV from the local ENV var; {{ env:MY_ENV }}
-					if !regLocalEnv.MatchString(*label.Value) {
-						errors = append(errors,
-							fmt.Sprintf(
-								"label entry #%d with key '%s' has invalid value field set, it has '%s', but allowed is only '{{ env:MY_ENV }}'",
-								i, *label.Key, *label.Value))
-					} else {
-						match := regLocalEnv.FindStringSubmatch(*label.Value)
-						value := os.Getenv(match[1])
-						if err := utils.ValidateLabelValue(value); err != nil {
-							errors = append(errors, fmt.Sprintf("label entry #%d with key '%s' has invalid value when the environment is evaluated: '%s': %s", i, *label.Key, value, err.Error()))
-						}
-					}
-				}
-			}
+		for _, msg := range label.Validate() {
+			errors = append(errors, fmt.Sprintf("label entry #%d %s", i, msg))
 		}
 	}
 
@@ -20,6 +20,9 @@ func Test_validateLabels(t *testing.T) {
 	incorrectKey2 := ":foo"
 	incorrectValue := ":foo"
 
+	reservedKey := "kubernetes.io/name"
+	reservedKey2 := "k8s.io/name"
+
 	valueLocalEnv := "{{ env:MY_ENV }}"
 	valueLocalEnv2 := "{{ env:MY_ENV2 }}"
 	valueLocalEnv3 := "{{env:MY_ENV3}}"
@@ -97,6 +100,26 @@ func Test_validateLabels(t *testing.T) {
 			},
 			1,
 		},
+		{
+			"incorrect entry - reserved key prefix",
+			[]Label{
+				{
+					Key:   &reservedKey,
+					Value: &value,
+				},
+			},
+			1,
+		},
+		{
+			"incorrect entry - reserved key prefix2",
+			[]Label{
+				{
+					Key:   &reservedKey2,
+					Value: &value,
+				},
+			},
+			1,
+		},
 		{
 			"incorrect entry - invalid value",
 			[]Label{
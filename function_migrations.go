@@ -2,6 +2,7 @@ package function
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -37,11 +38,43 @@ func (f Function) Migrate() (migrated Function, err error) {
 	return
 }
 
+// MigrationPlan returns the migrations which would be applied by Migrate,
+// in order, without mutating the function.  An empty plan indicates the
+// function is fully migrated.
+func (f Function) MigrationPlan() (plan []MigrationStep, err error) {
+	if f.Migrated() {
+		return
+	}
+	var current *semver.Version
+	if f.SpecVersion != "" {
+		if current, err = semver.NewVersion(f.SpecVersion); err != nil {
+			return
+		}
+	}
+	for _, m := range migrations {
+		// Skip migrations already applied (see Migrate for the equivalent
+		// logic used when actually applying).
+		if current != nil && !current.LessThan(*semver.New(m.version)) {
+			continue
+		}
+		plan = append(plan, MigrationStep{Version: m.version, Description: m.description})
+	}
+	return
+}
+
+// MigrationStep describes a single pending migration: the spec version it
+// imparts and a human-readable description of what it changes.
+type MigrationStep struct {
+	Version     string
+	Description string
+}
+
 // migration is a migration which should be applied to function's whose version
 // is below that indicated.
 type migration struct {
-	version string   // version before which this migration may be needed.
-	migrate migrator // Migrator migrates.
+	version     string   // version before which this migration may be needed.
+	description string   // human-readable summary of what the migration changes.
+	migrate     migrator // Migrator migrates.
 }
 
 // migrator is a function which returns a migrated copy of an inbound function.
@@ -77,10 +110,11 @@ func LastSpecVersion() string {
 // No two migrations may have the exact version number (introduce a patch
 // version for the migration if necessary)
 var migrations = []migration{
-	{"0.19.0", migrateToCreationStamp},
-	{"0.23.0", migrateToBuilderImages},
-	{"0.25.0", migrateToSpecVersion},
-	{"0.34.0", migrateToSpecsStructure},
+	{"0.19.0", "Add a creation timestamp to pre-versioning functions", migrateToCreationStamp},
+	{"0.23.0", "Replace the 'builder' attribute with per-implementation 'builderImages'", migrateToBuilderImages},
+	{"0.25.0", "Rename 'version' to 'specVersion'", migrateToSpecVersion},
+	{"0.34.0", "Move attributes into 'build', 'run' and 'deploy' sub-structures", migrateToSpecsStructure},
+	{"0.34.1", "Merge a lingering top-level 'buildpacks' list into 'build.buildpacks'", migrateToMergedBuildpacks},
 	// New Migrations Here.
 }
 
@@ -282,6 +316,51 @@ func migrateToSpecsStructure(f1 Function, m migration) (Function, error) {
 	return f1, nil
 }
 
+// migrateToMergedBuildpacks merges a lingering top-level 'buildpacks' key
+// into build.buildpacks.  Functions written by intermediate builds could
+// contain both keys, in which case the top-level list was silently dropped
+// on unmarshal.  The union of both lists (deduplicated, order preserved)
+// is retained and a warning printed when a conflict is detected.
+func migrateToMergedBuildpacks(f1 Function, m migration) (Function, error) {
+	// Load the Function using pertinent parts of the previous version's schema:
+	f0Filename := filepath.Join(f1.Root, FunctionFile)
+	bb, err := os.ReadFile(f0Filename)
+	if err != nil {
+		return f1, errors.New("migration 'migrateToMergedBuildpacks' error: " + err.Error())
+	}
+	f0 := migrateToMergedBuildpacks_previousFunction{}
+	if err = yaml.Unmarshal(bb, &f0); err != nil {
+		return f1, errors.New("migration 'migrateToMergedBuildpacks' error: " + err.Error())
+	}
+
+	// The nested list is parsed separately because in schemas prior to the
+	// specs-structure migration 'build' was a scalar (the build type) and
+	// would fail to unmarshal as a struct; such functions have no conflict.
+	f0Build := migrateToMergedBuildpacks_previousBuild{}
+	_ = yaml.Unmarshal(bb, &f0Build)
+
+	if len(f0.Buildpacks) > 0 && len(f0Build.Build.Buildpacks) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: func.yaml contains both 'buildpacks' and 'build.buildpacks'; merging the two lists.\n")
+	}
+
+	// Append any top-level buildpacks not already present, preserving order.
+	for _, bp := range f0.Buildpacks {
+		exists := false
+		for _, existing := range f1.Build.Buildpacks {
+			if existing == bp {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			f1.Build.Buildpacks = append(f1.Build.Buildpacks, bp)
+		}
+	}
+
+	f1.SpecVersion = m.version
+	return f1, nil
+}
+
 // The pertinent aspects of the Function's schema prior the 1.0.0 version migrations
 type migrateToSpecs_previousFunction struct {
 
@@ -340,3 +419,18 @@ type migrateToSpecVersion_previousFunction struct {
 type migrateToBuilderImages_previousFunction struct {
 	Builder string `yaml:"builder"`
 }
+
+// The pertinent aspects of the function schema as written by intermediate
+// builds which could populate both a top-level and a build-scoped
+// buildpacks list.
+type migrateToMergedBuildpacks_previousFunction struct {
+	Buildpacks []string `yaml:"buildpacks"`
+}
+
+// The build-scoped buildpacks list, parsed separately since 'build' may be
+// a scalar in schemas prior to the specs-structure migration.
+type migrateToMergedBuildpacks_previousBuild struct {
+	Build struct {
+		Buildpacks []string `yaml:"buildpacks"`
+	} `yaml:"build"`
+}
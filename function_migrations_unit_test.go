@@ -65,6 +65,51 @@ func TestMigrate(t *testing.T) {
 	}
 }
 
+// TestMigrationPlan ensures that the pending migrations for a function are
+// reported, in order, without mutating the function, and that a fully
+// migrated function has an empty plan.
+func TestMigrationPlan(t *testing.T) {
+	// A function with no specVersion stamp has all migrations pending.
+	f := Function{}
+	plan, err := f.MigrationPlan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != len(migrations) {
+		t.Fatalf("expected %v pending migrations, got %v", len(migrations), len(plan))
+	}
+	for i, step := range plan {
+		if step.Version != migrations[i].version {
+			t.Fatalf("expected step %v to be version %v, got %v", i, migrations[i].version, step.Version)
+		}
+		if step.Description == "" {
+			t.Fatalf("migration %v has no description", step.Version)
+		}
+	}
+	if f.SpecVersion != "" {
+		t.Fatal("MigrationPlan should not mutate the function")
+	}
+
+	// A function partway through the migrations has only those remaining.
+	f = Function{SpecVersion: "0.23.0"}
+	plan, err = f.MigrationPlan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) == 0 || plan[0].Version != "0.25.0" {
+		t.Fatalf("expected plan to begin at 0.25.0, got %v", plan)
+	}
+
+	// A fully migrated function has an empty plan.
+	f = Function{SpecVersion: LastSpecVersion()}
+	if plan, err = f.MigrationPlan(); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 0 {
+		t.Fatalf("expected empty plan for migrated function, got %v", plan)
+	}
+}
+
 // TestMigrateToCreationStamp ensures that the creation timestamp migration
 // introduced for functions 0.19.0 and earlier is applied.
 func TestMigrateToCreationStamp(t *testing.T) {
@@ -170,3 +215,33 @@ func TestMigrateToSpecs(t *testing.T) {
 	}
 
 }
+
+// TestMigrateToMergedBuildpacks ensures that a function which has both a
+// top-level 'buildpacks' list and a 'build.buildpacks' list has the two
+// merged (deduplicated, order preserved) and the version stamped.
+func TestMigrateToMergedBuildpacks(t *testing.T) {
+	root := "testdata/migrations/v0.34.1"
+	expected := []string{
+		"paketo-buildpacks/go-dist",
+		"example.com/user/custom-buildpack",
+		"ghcr.io/boson-project/go-function-buildpack:tip",
+	}
+
+	f, err := NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.Build.Buildpacks) != len(expected) {
+		t.Fatalf("migrated Function expected %v buildpacks, got %v: %v", len(expected), len(f.Build.Buildpacks), f.Build.Buildpacks)
+	}
+	for i, bp := range expected {
+		if f.Build.Buildpacks[i] != bp {
+			t.Fatalf("migrated Function expected buildpack '%v' at index %v, got '%v'", bp, i, f.Build.Buildpacks[i])
+		}
+	}
+
+	if f.SpecVersion != LastSpecVersion() {
+		t.Fatalf("migrated Function should have the latest specVersion '%v', got '%v'", LastSpecVersion(), f.SpecVersion)
+	}
+}
@@ -2,6 +2,7 @@ package function
 
 import (
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -17,6 +18,72 @@ type ScaleOptions struct {
 	Metric      *string  `yaml:"metric,omitempty" jsonschema:"enum=concurrency,enum=rps"`
 	Target      *float64 `yaml:"target,omitempty" jsonschema_extras:"minimum=0.01"`
 	Utilization *float64 `yaml:"utilization,omitempty" jsonschema:"minimum=1,maximum=100"`
+	// ScaleToZeroGrace is the duration for which the last replica is retained
+	// after the function is deemed scalable to zero, expressed as a Go
+	// duration string (e.g. "1m30s").
+	ScaleToZeroGrace *string `yaml:"scaleToZeroGrace,omitempty"`
+	// ScaleToZero, when explicitly false, indicates at least one replica is
+	// to be kept warm at all times (requires min of at least 1, which is how
+	// the platform effects the setting).  Nil or true defers to the platform.
+	ScaleToZero *bool `yaml:"scaleToZero,omitempty"`
+}
+
+// ScalingOptions is a typed, bounds-checked view of the autoscaling subset
+// of function Options, for use by programmatic callers.  Apply to a function
+// with SetScalingOptions, which validates before mutating.
+type ScalingOptions struct {
+	// Min is the minimum number of replicas (0 allows scale to zero).
+	Min *int64
+	// Max is the maximum number of replicas (0 denotes unlimited).
+	Max *int64
+	// Concurrency is the target number of concurrent requests per replica.
+	Concurrency *float64
+	// ScaleToZeroGrace is the duration for which the last replica is
+	// retained after the function is deemed scalable to zero.
+	ScaleToZeroGrace *string
+}
+
+// SetScalingOptions validates the given scaling options and applies them to
+// the function's deploy options.  These flow through to the deployed
+// service's autoscaling annotations on next deploy.
+func (f *Function) SetScalingOptions(o ScalingOptions) error {
+	if o.Min != nil && *o.Min < 0 {
+		return fmt.Errorf("scaling option \"min\" must not be negative, got %d", *o.Min)
+	}
+	if o.Max != nil && *o.Max < 0 {
+		return fmt.Errorf("scaling option \"max\" must not be negative, got %d", *o.Max)
+	}
+	if o.Min != nil && o.Max != nil && *o.Max < *o.Min {
+		return fmt.Errorf("scaling option \"max\" (%d) must be greater than or equal to \"min\" (%d)", *o.Max, *o.Min)
+	}
+	if o.Concurrency != nil && *o.Concurrency <= 0 {
+		return fmt.Errorf("scaling option \"concurrency\" must be greater than 0, got %f", *o.Concurrency)
+	}
+	if o.ScaleToZeroGrace != nil {
+		if _, err := time.ParseDuration(*o.ScaleToZeroGrace); err != nil {
+			return fmt.Errorf("scaling option \"scaleToZeroGrace\" has invalid value \"%s\"; %v", *o.ScaleToZeroGrace, err)
+		}
+	}
+
+	if f.Deploy.Options.Scale == nil {
+		f.Deploy.Options.Scale = &ScaleOptions{}
+	}
+	scale := f.Deploy.Options.Scale
+	if o.Min != nil {
+		scale.Min = o.Min
+	}
+	if o.Max != nil {
+		scale.Max = o.Max
+	}
+	if o.Concurrency != nil {
+		metric := "concurrency"
+		scale.Metric = &metric
+		scale.Target = o.Concurrency
+	}
+	if o.ScaleToZeroGrace != nil {
+		scale.ScaleToZeroGrace = o.ScaleToZeroGrace
+	}
+	return nil
 }
 
 type ResourcesOptions struct {
@@ -82,55 +149,110 @@ func validateOptions(options Options) (errors []string) {
 						*options.Scale.Utilization))
 			}
 		}
+
+		if options.Scale.ScaleToZeroGrace != nil {
+			if _, err := time.ParseDuration(*options.Scale.ScaleToZeroGrace); err != nil {
+				errors = append(errors, fmt.Sprintf("options field \"scale.scaleToZeroGrace\" has invalid value set: \"%s\"; \"%s\"",
+					*options.Scale.ScaleToZeroGrace, err.Error()))
+			}
+		}
+
+		if options.Scale.ScaleToZero != nil && !*options.Scale.ScaleToZero {
+			if options.Scale.Min == nil || *options.Scale.Min < 1 {
+				errors = append(errors, "options field \"scale.scaleToZero\" is false, which requires \"scale.min\" to be at least 1")
+			}
+		}
 	}
 
 	// options.resource
-	if options.Resources != nil {
+	errors = append(errors, validateResources(options.Resources, "options")...)
+
+	return
+}
 
-		// options.resource.requests
-		if options.Resources.Requests != nil {
+// validateResources checks that input ResourcesOptions are correctly set:
+// quantities must parse, concurrency must not be negative and, where both a
+// request and the corresponding limit are given, the limit must not be less
+// than the request.  Field names in the returned messages are prefixed with
+// the given owner ("options" or "run" depending on where the resources are
+// defined).
+// Returns array of error messages, empty if no errors are found
+func validateResources(resources *ResourcesOptions, owner string) (errors []string) {
+	if resources == nil {
+		return
+	}
+
+	// resources.requests
+	if resources.Requests != nil {
 
-			if options.Resources.Requests.CPU != nil {
-				_, err := resource.ParseQuantity(*options.Resources.Requests.CPU)
-				if err != nil {
-					errors = append(errors, fmt.Sprintf("options field \"resources.requests.cpu\" has invalid value set: \"%s\"; \"%s\"",
-						*options.Resources.Requests.CPU, err.Error()))
-				}
+		if resources.Requests.CPU != nil {
+			_, err := resource.ParseQuantity(*resources.Requests.CPU)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s field \"resources.requests.cpu\" has invalid value set: \"%s\"; \"%s\"",
+					owner, *resources.Requests.CPU, err.Error()))
 			}
+		}
 
-			if options.Resources.Requests.Memory != nil {
-				_, err := resource.ParseQuantity(*options.Resources.Requests.Memory)
-				if err != nil {
-					errors = append(errors, fmt.Sprintf("options field \"resources.requests.memory\" has invalid value set: \"%s\"; \"%s\"",
-						*options.Resources.Requests.Memory, err.Error()))
-				}
+		if resources.Requests.Memory != nil {
+			_, err := resource.ParseQuantity(*resources.Requests.Memory)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s field \"resources.requests.memory\" has invalid value set: \"%s\"; \"%s\"",
+					owner, *resources.Requests.Memory, err.Error()))
 			}
 		}
+	}
 
-		// options.resource.limits
-		if options.Resources.Limits != nil {
+	// resources.limits
+	if resources.Limits != nil {
 
-			if options.Resources.Limits.CPU != nil {
-				_, err := resource.ParseQuantity(*options.Resources.Limits.CPU)
-				if err != nil {
-					errors = append(errors, fmt.Sprintf("options field \"resources.limits.cpu\" has invalid value set: \"%s\"; \"%s\"",
-						*options.Resources.Limits.CPU, err.Error()))
-				}
+		if resources.Limits.CPU != nil {
+			_, err := resource.ParseQuantity(*resources.Limits.CPU)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s field \"resources.limits.cpu\" has invalid value set: \"%s\"; \"%s\"",
+					owner, *resources.Limits.CPU, err.Error()))
 			}
+		}
 
-			if options.Resources.Limits.Memory != nil {
-				_, err := resource.ParseQuantity(*options.Resources.Limits.Memory)
-				if err != nil {
-					errors = append(errors, fmt.Sprintf("options field \"resources.limits.memory\" has invalid value set: \"%s\"; \"%s\"",
-						*options.Resources.Limits.Memory, err.Error()))
-				}
+		if resources.Limits.Memory != nil {
+			_, err := resource.ParseQuantity(*resources.Limits.Memory)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s field \"resources.limits.memory\" has invalid value set: \"%s\"; \"%s\"",
+					owner, *resources.Limits.Memory, err.Error()))
 			}
+		}
 
-			if options.Resources.Limits.Concurrency != nil {
-				if *options.Resources.Limits.Concurrency < 0 {
-					errors = append(errors, fmt.Sprintf("options field \"resources.limits.concurrency\" has value set to \"%d\", but it must not be less than 0",
-						*options.Resources.Limits.Concurrency))
-				}
+		if resources.Limits.Concurrency != nil {
+			if *resources.Limits.Concurrency < 0 {
+				errors = append(errors, fmt.Sprintf("%s field \"resources.limits.concurrency\" has value set to \"%d\", but it must not be less than 0",
+					owner, *resources.Limits.Concurrency))
+			}
+		}
+	}
+
+	// Limits, where both they and the corresponding request parse, must be
+	// at least the request.
+	if resources.Requests != nil && resources.Limits != nil {
+		for _, pair := range []struct {
+			name           string
+			request, limit *string
+		}{
+			{"cpu", resources.Requests.CPU, resources.Limits.CPU},
+			{"memory", resources.Requests.Memory, resources.Limits.Memory},
+		} {
+			if pair.request == nil || pair.limit == nil {
+				continue
+			}
+			request, err := resource.ParseQuantity(*pair.request)
+			if err != nil {
+				continue // parse errors reported above
+			}
+			limit, err := resource.ParseQuantity(*pair.limit)
+			if err != nil {
+				continue
+			}
+			if limit.Cmp(request) < 0 {
+				errors = append(errors, fmt.Sprintf("%s field \"resources.limits.%s\" (%s) must not be less than \"resources.requests.%s\" (%s)",
+					owner, pair.name, *pair.limit, pair.name, *pair.request))
 			}
 		}
 	}
@@ -99,6 +99,25 @@ func Test_validateOptions(t *testing.T) {
 			},
 			1,
 		},
+		{
+			"correct 'scale.scaleToZero' - disabled with min pinned",
+			Options{
+				Scale: &ScaleOptions{
+					Min:         ptr.Int64(1),
+					ScaleToZero: ptr.Bool(false),
+				},
+			},
+			0,
+		},
+		{
+			"incorrect 'scale.scaleToZero' - disabled without min",
+			Options{
+				Scale: &ScaleOptions{
+					ScaleToZero: ptr.Bool(false),
+				},
+			},
+			1,
+		},
 		{
 			"correct 'scale.target'",
 			Options{
@@ -144,6 +163,24 @@ func Test_validateOptions(t *testing.T) {
 			},
 			1,
 		},
+		{
+			"correct 'scale.scaleToZeroGrace'",
+			Options{
+				Scale: &ScaleOptions{
+					ScaleToZeroGrace: ptr.String("1m30s"),
+				},
+			},
+			0,
+		},
+		{
+			"incorrect 'scale.scaleToZeroGrace'",
+			Options{
+				Scale: &ScaleOptions{
+					ScaleToZeroGrace: ptr.String("ninety seconds"),
+				},
+			},
+			1,
+		},
 		{
 			"correct 'resources.requests.cpu'",
 			Options{
@@ -265,6 +302,36 @@ func Test_validateOptions(t *testing.T) {
 			},
 			1,
 		},
+		{
+			"correct 'resources' - limit equal to request",
+			Options{
+				Resources: &ResourcesOptions{
+					Requests: &ResourcesRequestsOptions{
+						CPU: ptr.String("100m"),
+					},
+					Limits: &ResourcesLimitsOptions{
+						CPU: ptr.String("100m"),
+					},
+				},
+			},
+			0,
+		},
+		{
+			"incorrect 'resources' - limit less than request",
+			Options{
+				Resources: &ResourcesOptions{
+					Requests: &ResourcesRequestsOptions{
+						CPU:    ptr.String("1000m"),
+						Memory: ptr.String("256Mi"),
+					},
+					Limits: &ResourcesLimitsOptions{
+						CPU:    ptr.String("100m"),
+						Memory: ptr.String("128Mi"),
+					},
+				},
+			},
+			2,
+		},
 		{
 			"correct all options",
 			Options{
@@ -324,3 +391,53 @@ func Test_validateOptions(t *testing.T) {
 	}
 
 }
+
+// TestFunction_SetScalingOptions ensures that valid scaling options are
+// applied to the function's deploy options and invalid bounds are rejected
+// before any mutation.
+func TestFunction_SetScalingOptions(t *testing.T) {
+	f := Function{}
+
+	// Invalid bounds are rejected
+	if err := f.SetScalingOptions(ScalingOptions{Min: ptr.Int64(10), Max: ptr.Int64(1)}); err == nil {
+		t.Error("expected error setting max < min, got nil")
+	}
+	if err := f.SetScalingOptions(ScalingOptions{Concurrency: ptr.Float64(0)}); err == nil {
+		t.Error("expected error setting concurrency of 0, got nil")
+	}
+	if err := f.SetScalingOptions(ScalingOptions{ScaleToZeroGrace: ptr.String("not a duration")}); err == nil {
+		t.Error("expected error setting an unparseable grace duration, got nil")
+	}
+	if f.Deploy.Options.Scale != nil {
+		t.Fatal("function options should not be mutated by rejected scaling options")
+	}
+
+	// Valid options are applied
+	err := f.SetScalingOptions(ScalingOptions{
+		Min:              ptr.Int64(1),
+		Max:              ptr.Int64(10),
+		Concurrency:      ptr.Float64(50),
+		ScaleToZeroGrace: ptr.String("1m"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scale := f.Deploy.Options.Scale
+	if scale == nil {
+		t.Fatal("scale options were not set")
+	}
+	if *scale.Min != 1 || *scale.Max != 10 {
+		t.Errorf("expected min 1 max 10, got min %v max %v", *scale.Min, *scale.Max)
+	}
+	if *scale.Metric != "concurrency" || *scale.Target != 50 {
+		t.Errorf("expected concurrency target of 50, got metric %v target %v", *scale.Metric, *scale.Target)
+	}
+	if *scale.ScaleToZeroGrace != "1m" {
+		t.Errorf("expected scaleToZeroGrace '1m', got %v", *scale.ScaleToZeroGrace)
+	}
+
+	// The resulting options pass overall validation
+	if errs := validateOptions(f.Deploy.Options); len(errs) != 0 {
+		t.Errorf("options set via SetScalingOptions failed validation: %v", errs)
+	}
+}
@@ -4,6 +4,8 @@
 package function_test
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -48,6 +50,55 @@ func TestFunction_WriteIdempotency(t *testing.T) {
 	}
 }
 
+// TestFunction_WritePreservesComments ensures that comments a user has added
+// to func.yaml survive subsequent writes of the function.
+func TestFunction_WritePreservesComments(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+	client := fn.New(fn.WithRegistry(TestRegistry))
+
+	// Create a function
+	if err := client.Create(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add a comment to the serialized function
+	path := filepath.Join(root, fn.FunctionFile)
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bb = append([]byte("# this function is documented\n"), bb...)
+	if err := os.WriteFile(path, bb, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Change the function's configuration and write it again
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Deploy.Namespace = "example"
+	if err := f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The comment should survive the rewrite, as should the change
+	bb, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bb), "# this function is documented") {
+		t.Error("comment in func.yaml did not survive Write")
+	}
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Namespace != "example" {
+		t.Error("configuration change was not written")
+	}
+}
+
 // TestFunction_NameDefault ensures that a function's name is defaulted to that
 // which can be derived from the last part of its path.
 // Creating a new function from a path will error if there is no function at
@@ -167,3 +218,46 @@ func TestFunction_MigrationError(t *testing.T) {
 	}
 
 }
+
+// TestFunction_SearchFunctionRoot ensures that the root of a function is
+// resolved upward from subdirectories, and that paths outside any function
+// are returned unmodified.
+func TestFunction_SearchFunctionRoot(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	f := fn.Function{Runtime: "go", Root: root, Name: "search-root"}
+	if err := f.Write(); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "hack", "scripts")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resolving from a nested subdirectory yields the function root.
+	resolved, err := fn.SearchFunctionRoot(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != root {
+		t.Fatalf("expected root '%v' resolving from subdirectory, got '%v'", root, resolved)
+	}
+
+	// The root itself resolves to itself.
+	if resolved, err = fn.SearchFunctionRoot(root); err != nil {
+		t.Fatal(err)
+	}
+	if resolved != root {
+		t.Fatalf("expected root '%v' resolving from root, got '%v'", root, resolved)
+	}
+
+	// Paths with no enclosing function are returned unmodified.
+	outside := filepath.Join(os.TempDir(), "no-such-function")
+	if resolved, err = fn.SearchFunctionRoot(outside); err != nil {
+		t.Fatal(err)
+	}
+	if resolved != outside {
+		t.Fatalf("expected path '%v' returned unmodified, got '%v'", outside, resolved)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"knative.dev/kn-plugin-func/k8s"
@@ -28,6 +29,8 @@ type options struct {
 	selectCA           func(ctx context.Context, serverName string) (*x509.Certificate, error)
 	inClusterDialer    ContextDialer
 	insecureSkipVerify bool
+	tlsConfig          *tls.Config
+	caCertFile         string
 }
 
 type Option func(*options)
@@ -38,6 +41,24 @@ func WithSelectCA(selectCA func(ctx context.Context, serverName string) (*x509.C
 	}
 }
 
+// WithTLSConfig sets the base TLS client configuration for the transport.
+// Other options (WithInsecureSkipVerify, WithCACert) are applied on top of
+// a clone of the given config.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithCACert adds the PEM-encoded certificate(s) at the given path to the
+// pool of root CAs trusted by the transport, in addition to the system roots.
+// Useful when the registry or cluster uses an internal certificate authority.
+func WithCACert(path string) Option {
+	return func(o *options) {
+		o.caCertFile = path
+	}
+}
+
 func WithInClusterDialer(inClusterDialer ContextDialer) Option {
 	return func(o *options) {
 		o.inClusterDialer = inClusterDialer
@@ -70,7 +91,22 @@ func NewRoundTripper(opts ...Option) RoundTripCloser {
 
 	combinedDialer := newDialerWithFallback(primaryDialer, secondaryDialer)
 
-	httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: o.insecureSkipVerify}
+	tlsConfig := &tls.Config{}
+	if o.tlsConfig != nil {
+		tlsConfig = o.tlsConfig.Clone()
+	}
+	if o.insecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	var err error
+	if o.caCertFile != "" {
+		if tlsConfig.RootCAs, err = caCertPool(o.caCertFile); err != nil {
+			err = fmt.Errorf("failed to load CA certificate: %w", err)
+		}
+	}
+
+	httpTransport.TLSClientConfig = tlsConfig
 
 	httpTransport.DialContext = combinedDialer.DialContext
 
@@ -79,7 +115,26 @@ func NewRoundTripper(opts ...Option) RoundTripCloser {
 	return &roundTripCloser{
 		Transport: httpTransport,
 		dialer:    combinedDialer,
+		selectCA:  o.selectCA,
+		err:       err,
+	}
+}
+
+// caCertPool returns the system certificate pool augmented with the
+// PEM-encoded certificate(s) at the given path.
+func caCertPool(path string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %v", path)
 	}
+	return pool, nil
 }
 
 func newHTTPTransport() *http.Transport {
@@ -103,7 +158,24 @@ func newHTTPTransport() *http.Transport {
 
 type roundTripCloser struct {
 	*http.Transport
-	dialer ContextDialer
+	dialer   ContextDialer
+	selectCA func(ctx context.Context, serverName string) (*x509.Certificate, error)
+	err      error // deferred construction error, reported on first use
+}
+
+func (r *roundTripCloser) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.Transport.RoundTrip(req)
+}
+
+// SetTLSClientConfig replaces the transport's TLS client configuration,
+// rebuilding the TLS dialer such that in-cluster fallback and per-host CA
+// selection are preserved.
+func (r *roundTripCloser) SetTLSClientConfig(cfg *tls.Config) {
+	r.Transport.TLSClientConfig = cfg.Clone()
+	r.Transport.DialTLSContext = newDialTLSContext(r.dialer, r.Transport.TLSClientConfig, r.selectCA)
 }
 
 func (r *roundTripCloser) Close() error {
@@ -7,10 +7,13 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"math/big"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -64,6 +67,47 @@ func TestCustomCA(t *testing.T) {
 
 }
 
+func TestWithCACert(t *testing.T) {
+	localhostAddr, localhostCA := startServer(t, "localhost")
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: localhostCA.Raw})
+	if err := os.WriteFile(caFile, pemBytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tr := fnhttp.NewRoundTripper(fnhttp.WithCACert(caFile))
+	defer tr.Close()
+
+	client := http.Client{Transport: tr}
+
+	_, p, err := net.SplitHostPort(localhostAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Get(fmt.Sprintf("https://localhost:%s", p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// without the CA the server certificate must not verify
+	tr2 := fnhttp.NewRoundTripper()
+	defer tr2.Close()
+	client = http.Client{Transport: tr2}
+	if _, err = client.Get(fmt.Sprintf("https://localhost:%s", p)); err == nil {
+		t.Fatal("expected certificate verification error, got nil")
+	}
+
+	// a nonexistent certificate path errors on first use
+	tr3 := fnhttp.NewRoundTripper(fnhttp.WithCACert(filepath.Join(t.TempDir(), "nonexistent.crt")))
+	defer tr3.Close()
+	client = http.Client{Transport: tr3}
+	if _, err = client.Get(fmt.Sprintf("https://localhost:%s", p)); err == nil {
+		t.Fatal("expected error loading CA certificate, got nil")
+	}
+}
+
 type mockInClusterDialer struct {
 	backingAddr string
 }
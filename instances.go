@@ -50,6 +50,31 @@ func (s *Instances) Get(ctx context.Context, f Function, environment string) (In
 	}
 }
 
+// List the currently available instances of the function in all known
+// environments, keyed by environment name ('local' and 'remote').
+// Environments in which the function has no instance (not running locally,
+// not deployed) are simply omitted:  a function with no instances at all
+// yields an empty map, not an error.
+func (s *Instances) List(ctx context.Context, f Function) (map[string]Instance, error) {
+	instances := map[string]Instance{}
+
+	local, err := s.Local(ctx, f)
+	if err == nil {
+		instances[EnvironmentLocal] = local
+	} else if !errors.Is(err, ErrNotRunning) {
+		return instances, err
+	}
+
+	remote, err := s.Remote(ctx, f.Name, f.Root)
+	if err == nil {
+		instances[EnvironmentRemote] = remote
+	}
+	// Errors describing the remote instance (not deployed, cluster
+	// unreachable) indicate no available remote instance.
+
+	return instances, nil
+}
+
 // Local instance details for the function
 // If the function is not running locally the error returned is ErrNotRunning
 func (s *Instances) Local(ctx context.Context, f Function) (Instance, error) {
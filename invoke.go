@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
@@ -20,6 +21,14 @@ const (
 	DefaultInvokeContentType = "application/json"
 	DefaultInvokeData        = `{"message":"Hello World"}`
 	DefaultInvokeFormat      = "http"
+
+	// InvokeContentModeBinary sends CloudEvent attributes as headers with the
+	// data as the body (the default, matching most brokers).
+	InvokeContentModeBinary = "binary"
+	// InvokeContentModeStructured sends the full CloudEvent as a JSON
+	// envelope in the body.
+	InvokeContentModeStructured = "structured"
+	DefaultInvokeContentMode    = InvokeContentModeBinary
 )
 
 // InvokeMesage is the message used by the convenience method Invoke to provide
@@ -31,6 +40,17 @@ type InvokeMessage struct {
 	ContentType string
 	Data        string
 	Format      string //optional override for function-defined message format
+	ContentMode string //CloudEvent content mode: 'binary' (default) or 'structured'
+
+	// Method is the HTTP method with which to invoke when the format is
+	// 'http'.  Defaults to POST, preserving historical behavior.
+	Method string
+	// Headers are additional HTTP headers to set on the request when the
+	// format is 'http'.
+	Headers map[string]string
+	// QueryParams are appended to the route's query string when the format
+	// is 'http'.
+	QueryParams map[string]string
 }
 
 // NewInvokeMessage creates a new InvokeMessage with fields populated
@@ -41,6 +61,7 @@ func NewInvokeMessage() InvokeMessage {
 		Type:        DefaultInvokeType,
 		ContentType: DefaultInvokeContentType,
 		Data:        DefaultInvokeData,
+		ContentMode: DefaultInvokeContentMode,
 		// Format override not set by default: value from function being preferred.
 	}
 }
@@ -77,14 +98,23 @@ func invoke(ctx context.Context, c *Client, f Function, target string, m InvokeM
 			fmt.Printf("Invoking '%v' function using '%v' format\n", f.Invocation.Format, m.Format)
 		}
 	}
+	if strings.HasPrefix(target, brokerTargetPrefix) {
+		// Brokers only accept CloudEvents.
+		format = "cloudevent"
+	}
 
 	switch format {
 	case "http":
 		return sendPost(ctx, route, m, c.transport, verbose)
 	case "cloudevent":
 		// CouldEvents return a string which always includes a fairly verbose
-		// summation of fields, so metadata is not applicable
-		meta := make(map[string][]string)
+		// summation of fields, so metadata is limited to the content mode
+		// used to send the event (binary or structured).
+		mode := m.ContentMode
+		if mode == "" {
+			mode = DefaultInvokeContentMode
+		}
+		meta := map[string][]string{"Content-Mode": {mode}}
 		body, err = sendEvent(ctx, route, m, c.transport, verbose)
 		return meta, body, err
 	default:
@@ -93,15 +123,81 @@ func invoke(ctx context.Context, c *Client, f Function, target string, m InvokeM
 	}
 }
 
+// invokeStream opens a bidirectional connection to the function by
+// requesting a protocol upgrade on the invocation route (websocket by
+// default; message headers override).  Returned is the body of the 101
+// Switching Protocols response, which the standard library exposes as a
+// read-write connection.  Errors if the function does not upgrade.
+func invokeStream(ctx context.Context, c *Client, f Function, target string, m InvokeMessage, verbose bool) (io.ReadWriteCloser, error) {
+	route, err := invocationRoute(ctx, c, f, target)
+	if err != nil {
+		return nil, err
+	}
+	if verbose {
+		fmt.Printf("Opening streaming connection to %v\n", route)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, route, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	for k, v := range m.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := (&http.Client{Transport: c.transport}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		resp.Body.Close()
+		return nil, fmt.Errorf("the function did not upgrade the connection (HTTP %v)", resp.StatusCode)
+	}
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, errors.New("the transport does not support streaming connections")
+	}
+	return conn, nil
+}
+
+// brokerTargetPrefix precedes the name of a broker when a CloudEvent should
+// be published to a broker's ingress rather than to the function directly,
+// exercising the full trigger path.
+const brokerTargetPrefix = "broker:"
+
+// revisionTargetPrefix precedes the name or traffic tag of a specific
+// revision of the deployed function which should be invoked directly,
+// bypassing the main route's traffic split.
+const revisionTargetPrefix = "revision:"
+
 // invocationRoute returns a route to the named target instance of a func:
 // 'local': local environment; locally running function (error if not running)
 // 'remote': remote environment; first available instance (error if none)
+// 'broker:<name>': the ingress of the named CloudEvents broker
+// 'revision:<name-or-tag>': a specific revision (or traffic tag) of the
+//
+//	deployed function, resolved via the describer
+//
 // '<environment>': A valid alternate target which contains instances.
 // '<url>': An explicit URL
-// '': Default if no target is passed is to first use local, then remote.
-//     errors if neither are available.
+// ”: Default if no target is passed is to first use local, then remote.
+//
+//	errors if neither are available.
 func invocationRoute(ctx context.Context, c *Client, f Function, target string) (string, error) {
 	// TODO: this function has code-smell;  will de-smellify it in next pass.
+	if strings.HasPrefix(target, brokerTargetPrefix) {
+		return c.brokerResolver.BrokerURL(ctx, strings.TrimPrefix(target, brokerTargetPrefix))
+	}
+	if strings.HasPrefix(target, revisionTargetPrefix) {
+		rd, ok := c.describer.(RevisionDescriber)
+		if !ok {
+			return "", errors.New("the configured describer does not support revision targets")
+		}
+		return rd.RevisionURL(ctx, f.Name, strings.TrimPrefix(target, revisionTargetPrefix))
+	}
 	if target == EnvironmentLocal {
 		instance, err := c.Instances().Get(ctx, f, target)
 		if err != nil {
@@ -165,7 +261,19 @@ func sendEvent(ctx context.Context, route string, m InvokeMessage, t http.RoundT
 		// note event's stringification already includes a trailing linebreak.
 	}
 
-	evt, result := c.Request(cloudevents.ContextWithTarget(ctx, route), event)
+	// Content mode: binary (attributes as headers) unless structured (JSON
+	// envelope) was requested.
+	sendCtx := cloudevents.ContextWithTarget(ctx, route)
+	switch m.ContentMode {
+	case "", InvokeContentModeBinary:
+		sendCtx = cloudevents.WithEncodingBinary(sendCtx)
+	case InvokeContentModeStructured:
+		sendCtx = cloudevents.WithEncodingStructured(sendCtx)
+	default:
+		return "", fmt.Errorf("content mode '%v' not supported.", m.ContentMode)
+	}
+
+	evt, result := c.Request(sendCtx, event)
 	if cloudevents.IsUndelivered(result) {
 		err = fmt.Errorf("unable to invoke: %v", result)
 	} else if evt != nil { // Check for nil in case no event is returned
@@ -176,6 +284,8 @@ func sendEvent(ctx context.Context, route string, m InvokeMessage, t http.RoundT
 }
 
 // sendPost to the route populated with data in the invoke message.
+// The request's method, headers and query parameters are customizable via
+// the message; the default is a plain POST.
 func sendPost(ctx context.Context, route string, m InvokeMessage, t http.RoundTripper, verbose bool) (map[string][]string, string, error) {
 	client := http.Client{
 		Transport: t,
@@ -195,11 +305,33 @@ func sendPost(ctx context.Context, route string, m InvokeMessage, t http.RoundTr
 		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", route, bytes.NewBufferString(m.Data))
+	method := m.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	// Append any requested query parameters to those already on the route.
+	if len(m.QueryParams) > 0 {
+		u, err := url.Parse(route)
+		if err != nil {
+			return nil, "", fmt.Errorf("failure to parse route: %w", err)
+		}
+		q := u.Query()
+		for k, v := range m.QueryParams {
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+		route = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, route, bytes.NewBufferString(m.Data))
 	if err != nil {
 		return nil, "", fmt.Errorf("failure to create request: %w", err)
 	}
 	req.Header.Add("Content-Type", m.ContentType)
+	for k, v := range m.Headers {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -211,5 +343,10 @@ func sendPost(ctx context.Context, route string, m InvokeMessage, t http.RoundTr
 		return nil, "", fmt.Errorf("failure invoking '%v' (HTTP %v)", route, resp.StatusCode)
 	}
 	b, err := io.ReadAll(resp.Body)
-	return resp.Header, string(b), err
+
+	// Response headers plus the status code such that callers can assert on
+	// it programmatically.
+	metadata := map[string][]string(resp.Header)
+	metadata["Status-Code"] = []string{fmt.Sprintf("%d", resp.StatusCode)}
+	return metadata, string(b), err
 }
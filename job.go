@@ -1,11 +1,17 @@
 package function
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 )
 
+// runFilename is the name of the file, relative to the function's run data
+// directory, to which a record of the currently running local instance is
+// written.  See runRecord.
+const runFilename = "run.json"
+
 // Job represents a running function job (presumably started by this process'
 // Runner instance.
 type Job struct {
@@ -45,7 +51,18 @@ func (j *Job) save() error {
 	if err != nil {
 		return err
 	}
-	return file.Close()
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	// Record the run in <root>/.func/run.json such that separate processes
+	// (IDE stop buttons, CI cleanup) may address or stop the run.
+	// See Client.Stop.
+	bb, err := json.MarshalIndent(runRecord{PID: os.Getpid(), Port: j.Port}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(runRecordPath(j.Function), bb, 0644)
 
 	// Store the effective port for use by other client instances, possibly
 	// in other processes, such as to run Invoke from other terminal in CLI apps.
@@ -58,10 +75,36 @@ func (j *Job) save() error {
 }
 
 func (j *Job) remove() error {
+	removeRunRecord(j.Function)
 	filename := filepath.Join(j.Function.Root, RunDataDir, "instances", j.Port)
 	return os.Remove(filename)
 }
 
+// runRecord is the on-disk record of a locally running function instance,
+// written on start for use by other processes wishing to address or stop
+// the run.
+type runRecord struct {
+	PID  int    `json:"pid"`
+	Port string `json:"port"`
+}
+
+func runRecordPath(f Function) string {
+	return filepath.Join(f.Root, RunDataDir, runFilename)
+}
+
+func readRunRecord(f Function) (record runRecord, err error) {
+	bb, err := os.ReadFile(runRecordPath(f))
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(bb, &record)
+	return
+}
+
+func removeRunRecord(f Function) {
+	_ = os.Remove(runRecordPath(f))
+}
+
 // jobPorts returns all the ports on which an instance of the given function is
 // running.  len is 0 when not running.
 // Improperly initialized or nonexistent (zero value) functions are considered
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"fmt"
+	"os"
 
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -40,7 +41,16 @@ func GetNamespace(defaultNamespace string) (namespace string, err error) {
 }
 
 func GetClientConfig() clientcmd.ClientConfig {
+	overrides := clientcmd.ConfigOverrides{}
+
+	// Trust the CA certificate at the path provided via the environment,
+	// such as when the cluster's API server uses an internal certificate
+	// authority not present in the kubeconfig.
+	if ca := os.Getenv("FUNC_CA_CERT"); ca != "" {
+		overrides.ClusterInfo.CertificateAuthority = ca
+	}
+
 	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
 		clientcmd.NewDefaultClientConfigLoadingRules(),
-		&clientcmd.ConfigOverrides{})
+		&overrides)
 }
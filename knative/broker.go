@@ -0,0 +1,39 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+)
+
+// BrokerResolver resolves the ingress URL of Knative Eventing Brokers.
+type BrokerResolver struct {
+	namespace string
+	verbose   bool
+}
+
+// NewBrokerResolver creates a resolver of broker ingress URLs in the given
+// namespace (empty for the currently active namespace).
+func NewBrokerResolver(namespaceOverride string, verbose bool) *BrokerResolver {
+	return &BrokerResolver{namespace: namespaceOverride, verbose: verbose}
+}
+
+// BrokerURL returns the addressable URL of the named broker as reported by
+// its status, through which CloudEvents can be published to exercise the
+// full trigger path of subscribed functions.
+func (r *BrokerResolver) BrokerURL(ctx context.Context, name string) (string, error) {
+	client, err := NewEventingClient(r.namespace)
+	if err != nil {
+		return "", err
+	}
+
+	broker, err := client.GetBroker(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("unable to get broker %q: %w", name, err)
+	}
+
+	if broker.Status.Address.URL == nil {
+		return "", fmt.Errorf("broker %q is not yet addressable", name)
+	}
+
+	return broker.Status.Address.URL.String(), nil
+}
@@ -5,20 +5,25 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"text/template"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/client/pkg/kn/flags"
 	servingclientlib "knative.dev/client/pkg/serving"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	"knative.dev/client/pkg/wait"
+	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	"sigs.k8s.io/yaml"
 
 	fn "knative.dev/kn-plugin-func"
 	"knative.dev/kn-plugin-func/k8s"
@@ -114,6 +119,125 @@ func (d *Deployer) isImageInPrivateRegistry(ctx context.Context, client clientse
 	return false
 }
 
+// DeployWithTraffic deploys the function as Deploy does, additionally
+// configuring the service's traffic block such that only the requested
+// percentage of traffic reaches the newly created revision (optionally
+// tagged), with the remainder pinned to the previously ready revision.  On
+// a first deploy there is no previous revision, so the new one receives all
+// traffic regardless.
+func (d *Deployer) DeployWithTraffic(ctx context.Context, f fn.Function, opts fn.DeployOptions) (fn.DeploymentResult, error) {
+	var err error
+	if d.Namespace == "" {
+		if d.Namespace, err = k8s.GetNamespace(d.Namespace); err != nil {
+			return fn.DeploymentResult{}, err
+		}
+	}
+	client, err := NewServingClient(d.Namespace)
+	if err != nil {
+		return fn.DeploymentResult{}, err
+	}
+
+	// The previously ready revision, to which the remainder of traffic is
+	// pinned.  Absent on a first deploy.
+	stable := ""
+	if existing, getErr := client.GetService(ctx, f.Name); getErr == nil {
+		stable = existing.Status.LatestReadyRevisionName
+	}
+
+	result, err := d.Deploy(ctx, f)
+	if err != nil {
+		return result, err
+	}
+
+	percent := int64(opts.TrafficPercent)
+	targets := []v1.TrafficTarget{{
+		LatestRevision: ptr.Bool(true),
+		Percent:        ptr.Int64(percent),
+		Tag:            opts.Tag,
+	}}
+	if stable != "" && stable != result.RevisionName && percent < 100 {
+		targets = append(targets, v1.TrafficTarget{
+			RevisionName: stable,
+			Percent:      ptr.Int64(100 - percent),
+		})
+	} else {
+		// Nothing to split with: all traffic to the (sole) revision.
+		targets[0].Percent = ptr.Int64(100)
+	}
+
+	_, err = client.UpdateServiceWithRetry(ctx, f.Name, func(service *v1.Service) (*v1.Service, error) {
+		service.Spec.Traffic = targets
+		return service, nil
+	}, 3)
+	if err != nil {
+		return result, fmt.Errorf("knative deployer failed to configure traffic: %v", err)
+	}
+
+	// Report the resulting distribution.
+	for _, target := range targets {
+		t := fn.TrafficTarget{RevisionName: target.RevisionName, Tag: target.Tag}
+		if target.Percent != nil {
+			t.Percent = *target.Percent
+		}
+		if target.LatestRevision != nil && *target.LatestRevision {
+			t.RevisionName = result.RevisionName
+		}
+		result.Traffic = append(result.Traffic, t)
+	}
+	if d.verbose {
+		for _, t := range result.Traffic {
+			fmt.Printf("Traffic: %v%% -> %v\n", t.Percent, t.RevisionName)
+		}
+	}
+	return result, nil
+}
+
+// maxDeployErrorEvents caps the cluster events included in a DeployError.
+const maxDeployErrorEvents = 5
+
+// deployError decorates err with diagnostics gathered from the cluster: the
+// not-ready conditions of the function's Knative Service and recent warning
+// events involving the function (its service, revisions and pods).  Gathering
+// is best-effort; on any failure the diagnostics are simply omitted.
+func (d *Deployer) deployError(ctx context.Context, client clientservingv1.KnServingClient, funcName string, err error) error {
+	de := fn.DeployError{Err: err}
+
+	if ksvc, getErr := client.GetService(ctx, funcName); getErr == nil {
+		for _, cond := range ksvc.Status.Conditions {
+			if cond.Status == corev1.ConditionTrue {
+				continue
+			}
+			c := fmt.Sprintf("%v=%v", cond.Type, cond.Status)
+			if cond.Reason != "" {
+				c += " " + cond.Reason
+			}
+			if cond.Message != "" {
+				c += ": " + cond.Message
+			}
+			de.Conditions = append(de.Conditions, c)
+		}
+	}
+
+	if k8sClient, cErr := k8s.NewKubernetesClientset(); cErr == nil {
+		if events, lErr := k8sClient.CoreV1().Events(d.Namespace).List(ctx, metav1.ListOptions{}); lErr == nil {
+			for _, ev := range events.Items {
+				// The service, its revisions and their pods all share the
+				// function's name as a prefix.
+				if ev.Type == corev1.EventTypeNormal || !strings.HasPrefix(ev.InvolvedObject.Name, funcName) {
+					continue
+				}
+				de.Events = append(de.Events, fmt.Sprintf("%v %v %v/%v: %v",
+					ev.Type, ev.Reason, strings.ToLower(ev.InvolvedObject.Kind), ev.InvolvedObject.Name, ev.Message))
+			}
+			if len(de.Events) > maxDeployErrorEvents {
+				de.Events = de.Events[len(de.Events)-maxDeployErrorEvents:]
+			}
+		}
+	}
+
+	return de
+}
+
 func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResult, error) {
 	var err error
 	if d.Namespace == "" {
@@ -150,7 +274,7 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 			err = client.CreateService(ctx, service)
 			if err != nil {
 				err = fmt.Errorf("knative deployer failed to deploy the Knative Service: %v", err)
-				return fn.DeploymentResult{}, err
+				return fn.DeploymentResult{}, d.deployError(ctx, client, f.Name, err)
 			}
 
 			if d.verbose {
@@ -195,7 +319,7 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 			}
 			if err != nil {
 				err = fmt.Errorf("knative deployer failed to wait for the Knative Service to become ready: %v", err)
-				return fn.DeploymentResult{}, err
+				return fn.DeploymentResult{}, d.deployError(ctx, client, f.Name, err)
 			}
 
 			route, err := client.GetRoute(ctx, f.Name)
@@ -207,11 +331,18 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 			if d.verbose {
 				fmt.Printf("Function deployed in namespace %q and exposed at URL:\n%s\n", d.Namespace, route.Status.URL.String())
 			}
-			return fn.DeploymentResult{
+			result := fn.DeploymentResult{
 				Status:    fn.Deployed,
 				URL:       route.Status.URL.String(),
 				Namespace: d.Namespace,
-			}, nil
+			}
+			// Report the revision created by this deploy, read back from the
+			// now-ready service's status.
+			if deployed, err := client.GetService(ctx, f.Name); err == nil {
+				result.RevisionName = deployed.Status.LatestCreatedRevisionName
+				result.Generation = deployed.Generation
+			}
+			return result, nil
 
 		} else {
 			err = fmt.Errorf("knative deployer failed to get the Knative Service: %v", err)
@@ -232,16 +363,21 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 			return fn.DeploymentResult{}, err
 		}
 
+		newInitContainers, err := processInitContainers(f, newVolumeMounts, &referencedSecrets, &referencedConfigMaps)
+		if err != nil {
+			return fn.DeploymentResult{}, err
+		}
+
 		err = checkSecretsConfigMapsArePresent(ctx, d.Namespace, &referencedSecrets, &referencedConfigMaps)
 		if err != nil {
 			err = fmt.Errorf("knative deployer failed to update the Knative Service: %v", err)
 			return fn.DeploymentResult{}, err
 		}
 
-		_, err = client.UpdateServiceWithRetry(ctx, f.Name, updateService(f, newEnv, newEnvFrom, newVolumes, newVolumeMounts, d.decorator), 3)
+		_, err = client.UpdateServiceWithRetry(ctx, f.Name, updateService(f, newEnv, newEnvFrom, newVolumes, newVolumeMounts, newInitContainers, d.decorator), 3)
 		if err != nil {
 			err = fmt.Errorf("knative deployer failed to update the Knative Service: %v", err)
-			return fn.DeploymentResult{}, err
+			return fn.DeploymentResult{}, d.deployError(ctx, client, f.Name, err)
 		}
 
 		route, err := client.GetRoute(ctx, f.Name)
@@ -250,12 +386,89 @@ func (d *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 			return fn.DeploymentResult{}, err
 		}
 
-		return fn.DeploymentResult{
+		result := fn.DeploymentResult{
 			Status:    fn.Updated,
 			URL:       route.Status.URL.String(),
 			Namespace: d.Namespace,
-		}, nil
+		}
+		// Report the revision created by this update, read back from the
+		// service's status.
+		if updated, err := client.GetService(ctx, f.Name); err == nil {
+			result.RevisionName = updated.Status.LatestCreatedRevisionName
+			result.Generation = updated.Generation
+		}
+		return result, nil
+	}
+}
+
+// Manifest returns the Knative Service manifest which would be applied when
+// deploying the given function, serialized as YAML.  The cluster is not
+// contacted; the service is rendered exactly as a fresh Deploy would
+// create it.
+func (d *Deployer) Manifest(_ context.Context, f fn.Function) ([]byte, error) {
+	service, err := generateNewService(f, d.decorator)
+	if err != nil {
+		return nil, err
+	}
+	service.APIVersion = "serving.knative.dev/v1"
+	service.Kind = "Service"
+	if d.Namespace != "" {
+		service.Namespace = d.Namespace
+	}
+	return yaml.Marshal(service)
+}
+
+// PruneRevisions removes old revisions of the function's service such that
+// at most 'keep' remain, pruning oldest first.  Revisions currently
+// receiving traffic per the route are always retained, regardless of age.
+func (d *Deployer) PruneRevisions(ctx context.Context, f fn.Function, keep int) error {
+	namespace := d.Namespace
+	if namespace == "" {
+		var err error
+		if namespace, err = k8s.GetNamespace(namespace); err != nil {
+			return err
+		}
+	}
+
+	client, err := NewServingClient(namespace)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := client.ListRevisions(ctx, clientservingv1.WithService(f.Name))
+	if err != nil {
+		return fmt.Errorf("knative deployer failed to list revisions: %v", err)
+	}
+
+	// Revisions currently receiving traffic are never pruned.
+	route, err := client.GetRoute(ctx, f.Name)
+	if err != nil {
+		return fmt.Errorf("knative deployer failed to get the Route: %v", err)
+	}
+	serving := sets.NewString()
+	for _, target := range route.Status.Traffic {
+		if target.RevisionName != "" {
+			serving.Insert(target.RevisionName)
+		}
 	}
+
+	// Newest first such that the most recent 'keep' are retained.
+	items := revisions.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[j].CreationTimestamp.Before(&items[i].CreationTimestamp)
+	})
+
+	retained := 0
+	for _, revision := range items {
+		if serving.Has(revision.Name) || retained < keep {
+			retained++
+			continue
+		}
+		if err = client.DeleteRevision(ctx, revision.Name, DefaultWaitingTimeout); err != nil {
+			return fmt.Errorf("knative deployer failed to prune revision %q: %v", revision.Name, err)
+		}
+	}
+	return nil
 }
 
 func probeFor(url string) *corev1.Probe {
@@ -280,12 +493,97 @@ func setHealthEndpoints(f fn.Function, c *corev1.Container) *corev1.Container {
 	if f.Deploy.HealthEndpoints.Readiness != "" {
 		c.ReadinessProbe = probeFor(f.Deploy.HealthEndpoints.Readiness)
 	}
+
+	// Typed endpoints, when provided, take precedence for their probe type
+	// and additionally support a dedicated startup probe and custom ports.
+	for _, e := range f.Deploy.HealthEndpoints.Endpoints {
+		probe := probeFor(e.Path)
+		if e.Port != 0 {
+			probe.ProbeHandler.HTTPGet.Port = intstr.FromInt(int(e.Port))
+		}
+		switch e.Type {
+		case fn.ProbeLiveness:
+			c.LivenessProbe = probe
+		case fn.ProbeReadiness:
+			c.ReadinessProbe = probe
+		case fn.ProbeStartup:
+			c.StartupProbe = probe
+		}
+	}
+
+	// Probe timings, when provided, apply to all probes; the platform's
+	// defaults are used otherwise.
+	he := f.Deploy.HealthEndpoints
+	for _, probe := range []*corev1.Probe{c.LivenessProbe, c.ReadinessProbe, c.StartupProbe} {
+		if probe == nil {
+			continue
+		}
+		if he.InitialDelaySeconds != nil {
+			probe.InitialDelaySeconds = *he.InitialDelaySeconds
+		}
+		if he.PeriodSeconds != nil {
+			probe.PeriodSeconds = *he.PeriodSeconds
+		}
+		if he.FailureThreshold != nil {
+			probe.FailureThreshold = *he.FailureThreshold
+		}
+	}
 	return c
 }
 
+// annotationTemplateData is the metadata made available to annotation value
+// templates (see interpolateAnnotations).
+type annotationTemplateData struct {
+	Function struct {
+		Name        string
+		Runtime     string
+		Version     string // spec version of the function's metadata
+		Image       string
+		ImageDigest string
+	}
+	Git fn.Git
+}
+
+// interpolateAnnotations returns the given annotations with template
+// references to function metadata (e.g. "{{.Function.Name}}" or
+// "{{.Git.Revision}}") expanded.  Values without template syntax pass
+// through unchanged.  Invalid references error, naming the annotation.
+func interpolateAnnotations(f fn.Function, annotations map[string]string) (map[string]string, error) {
+	if len(annotations) == 0 {
+		return annotations, nil
+	}
+	data := annotationTemplateData{Git: f.Build.Git}
+	data.Function.Name = f.Name
+	data.Function.Runtime = f.Runtime
+	data.Function.Version = f.SpecVersion
+	data.Function.Image = f.Image
+	data.Function.ImageDigest = f.ImageDigest
+
+	interpolated := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if !strings.Contains(v, "{{") {
+			interpolated[k] = v
+			continue
+		}
+		tmpl, err := template.New(k).Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %q has an invalid template: %w", k, err)
+		}
+		var b strings.Builder
+		if err = tmpl.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("annotation %q failed to interpolate: %w", k, err)
+		}
+		interpolated[k] = b.String()
+	}
+	return interpolated, nil
+}
+
 func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service, error) {
 	container := corev1.Container{
-		Image: f.ImageWithDigest(),
+		Image:           f.ImageWithDigest(),
+		ImagePullPolicy: corev1.PullPolicy(f.Run.ImagePullPolicy), // empty uses the platform default
+		Command:         f.Run.Command,                            // empty uses the image entrypoint
+		Args:            f.Run.Args,                               // empty uses the image command
 	}
 	setHealthEndpoints(f, &container)
 
@@ -305,6 +603,11 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 	}
 	container.VolumeMounts = newVolumeMounts
 
+	newInitContainers, err := processInitContainers(f, newVolumeMounts, &referencedSecrets, &referencedConfigMaps)
+	if err != nil {
+		return nil, err
+	}
+
 	labels, err := f.LabelsMap()
 	if err != nil {
 		return nil, err
@@ -313,7 +616,10 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 		labels = decorator.UpdateLabels(f, labels)
 	}
 
-	annotations := f.Deploy.Annotations
+	annotations, err := interpolateAnnotations(f, f.Deploy.Annotations)
+	if err != nil {
+		return nil, err
+	}
 	if decorator != nil {
 		annotations = decorator.UpdateAnnotations(f, annotations)
 	}
@@ -336,7 +642,9 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 							Containers: []corev1.Container{
 								container,
 							},
-							Volumes: newVolumes,
+							InitContainers:     newInitContainers,
+							ServiceAccountName: f.Run.ServiceAccountName, // empty uses the namespace default
+							Volumes:            newVolumes,
 						},
 					},
 				},
@@ -344,7 +652,7 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 		},
 	}
 
-	err = setServiceOptions(&service.Spec.Template, f.Deploy.Options)
+	err = setServiceOptions(&service.Spec.Template, optionsWithRunResources(f))
 	if err != nil {
 		return service, err
 	}
@@ -352,7 +660,7 @@ func generateNewService(f fn.Function, decorator DeployDecorator) (*v1.Service,
 	return service, nil
 }
 
-func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.EnvFromSource, newVolumes []corev1.Volume, newVolumeMounts []corev1.VolumeMount, decorator DeployDecorator) func(service *v1.Service) (*v1.Service, error) {
+func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.EnvFromSource, newVolumes []corev1.Volume, newVolumeMounts []corev1.VolumeMount, newInitContainers []corev1.Container, decorator DeployDecorator) func(service *v1.Service) (*v1.Service, error) {
 	return func(service *v1.Service) (*v1.Service, error) {
 		// Removing the name so the k8s server can fill it in with generated name,
 		// this prevents conflicts in Revision name when updating the KService from multiple places.
@@ -364,7 +672,11 @@ func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.En
 			service.ObjectMeta.Annotations = decorator.UpdateAnnotations(f, service.ObjectMeta.Annotations)
 		}
 
-		for k, v := range f.Deploy.Annotations {
+		annotations, err := interpolateAnnotations(f, f.Deploy.Annotations)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range annotations {
 			service.ObjectMeta.Annotations[k] = v
 			service.Spec.Template.ObjectMeta.Annotations[k] = v
 		}
@@ -382,9 +694,12 @@ func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.En
 		// know what this would mean for developers using the func library directly.
 		cp := &service.Spec.Template.Spec.Containers[0]
 		setHealthEndpoints(f, cp)
+		cp.ImagePullPolicy = corev1.PullPolicy(f.Run.ImagePullPolicy)            // empty uses the platform default
+		cp.Command = f.Run.Command                                               // empty reverts to the image entrypoint
+		cp.Args = f.Run.Args                                                     // empty reverts to the image command
+		service.Spec.Template.Spec.ServiceAccountName = f.Run.ServiceAccountName // empty reverts to the namespace default
 
-		err := setServiceOptions(&service.Spec.Template, f.Deploy.Options)
-		if err != nil {
+		if err = setServiceOptions(&service.Spec.Template, optionsWithRunResources(f)); err != nil {
 			return service, err
 		}
 
@@ -408,6 +723,7 @@ func updateService(f fn.Function, newEnv []corev1.EnvVar, newEnvFrom []corev1.En
 		cp.EnvFrom = newEnvFrom
 		cp.VolumeMounts = newVolumeMounts
 		service.Spec.ConfigurationSpec.Template.Spec.Volumes = newVolumes
+		service.Spec.ConfigurationSpec.Template.Spec.InitContainers = newInitContainers // empty removes them
 
 		return service, nil
 	}
@@ -651,14 +967,18 @@ func processVolumes(volumes []fn.Volume, referencedSecrets, referencedConfigMaps
 		}
 
 		if volumeName != "" {
-			if !usedPaths.Has(*vol.Path) {
+			path, err := expandVolumePath(vol)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !usedPaths.Has(path) {
 				newVolumeMounts = append(newVolumeMounts, corev1.VolumeMount{
 					Name:      volumeName,
-					MountPath: *vol.Path,
+					MountPath: path,
 				})
-				usedPaths.Insert(*vol.Path)
+				usedPaths.Insert(path)
 			} else {
-				return nil, nil, fmt.Errorf("mount path %s is defined multiple times", *vol.Path)
+				return nil, nil, fmt.Errorf("mount path %s is defined multiple times", path)
 			}
 		}
 	}
@@ -666,6 +986,63 @@ func processVolumes(volumes []fn.Volume, referencedSecrets, referencedConfigMaps
 	return newVolumes, newVolumeMounts, nil
 }
 
+// processInitContainers generates Containers run to completion before the
+// function's container starts from the function's init container spec.  The
+// function's volume mounts are applied to each such that setup tasks may
+// share volumes with the function's container.
+func processInitContainers(f fn.Function, volumeMounts []corev1.VolumeMount, referencedSecrets, referencedConfigMaps *sets.String) ([]corev1.Container, error) {
+	if len(f.Run.InitContainers) == 0 {
+		return nil, nil
+	}
+	containers := []corev1.Container{}
+	for i, ic := range f.Run.InitContainers {
+		name := ic.Name
+		if name == "" {
+			name = fmt.Sprintf("init-%d", i)
+		}
+		env, envFrom, err := processEnvs(ic.Envs, referencedSecrets, referencedConfigMaps)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, corev1.Container{
+			Name:         name,
+			Image:        ic.Image,
+			Command:      ic.Command, // empty uses the image entrypoint
+			Env:          env,
+			EnvFrom:      envFrom,
+			VolumeMounts: volumeMounts,
+		})
+	}
+	return containers, nil
+}
+
+// volumePathEnvRegex matches references of the form {{ env:NAME }}
+// embedded anywhere within a volume's mount path.
+var volumePathEnvRegex = regexp.MustCompile(`{{\s*env:(\w+)\s*}}`)
+
+// expandVolumePath returns the volume's mount path with any embedded
+// {{ env:NAME }} references expanded from the local environment (the same
+// interpolation applied to build env values), validating that the result
+// remains an absolute path.
+func expandVolumePath(vol fn.Volume) (string, error) {
+	var expandErr error
+	path := volumePathEnvRegex.ReplaceAllStringFunc(*vol.Path, func(ref string) string {
+		name := volumePathEnvRegex.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok && expandErr == nil {
+			expandErr = fmt.Errorf("volume %s references environment variable %q in its path which is not set", vol, name)
+		}
+		return value
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	if !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("volume %s mount path %q is not absolute after expansion", vol, path)
+	}
+	return path, nil
+}
+
 // checkSecretsConfigMapsArePresent returns error if Secrets or ConfigMaps
 // referenced in input sets are not deployed on the cluster in the specified namespace
 func checkSecretsConfigMapsArePresent(ctx context.Context, namespace string, referencedSecrets, referencedConfigMaps *sets.String) error {
@@ -692,6 +1069,18 @@ func checkSecretsConfigMapsArePresent(ctx context.Context, namespace string, ref
 	return nil
 }
 
+// optionsWithRunResources returns the function's deploy options with the
+// run-level resource requests/limits applied when the options do not
+// themselves specify resources.  Options, being the more specific setting,
+// take precedence.
+func optionsWithRunResources(f fn.Function) fn.Options {
+	options := f.Deploy.Options
+	if options.Resources == nil {
+		options.Resources = f.Run.Resources
+	}
+	return options
+}
+
 // setServiceOptions sets annotations on Service Revision Template or in the Service Spec
 // from values specifed in function configuration options
 func setServiceOptions(template *v1.RevisionTemplateSpec, options fn.Options) error {
@@ -702,6 +1091,10 @@ func setServiceOptions(template *v1.RevisionTemplateSpec, options fn.Options) er
 	if options.Scale != nil {
 		if options.Scale.Min != nil {
 			toUpdate[autoscaling.MinScaleAnnotationKey] = fmt.Sprintf("%d", *options.Scale.Min)
+		} else if options.Scale.ScaleToZero != nil && !*options.Scale.ScaleToZero {
+			// Disabling scale to zero with no explicit minimum pins one
+			// warm replica (validation normally requires min be set).
+			toUpdate[autoscaling.MinScaleAnnotationKey] = "1"
 		} else {
 			toRemove = append(toRemove, autoscaling.MinScaleAnnotationKey)
 		}
@@ -730,6 +1123,12 @@ func setServiceOptions(template *v1.RevisionTemplateSpec, options fn.Options) er
 			toRemove = append(toRemove, autoscaling.TargetUtilizationPercentageKey)
 		}
 
+		if options.Scale.ScaleToZeroGrace != nil {
+			toUpdate[autoscaling.ScaleToZeroPodRetentionPeriodKey] = *options.Scale.ScaleToZeroGrace
+		} else {
+			toRemove = append(toRemove, autoscaling.ScaleToZeroPodRetentionPeriodKey)
+		}
+
 	}
 
 	// in the container always set Requests/Limits & Concurrency values based on the contents of config
@@ -4,12 +4,15 @@
 package knative
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	fn "knative.dev/kn-plugin-func"
+	"knative.dev/pkg/ptr"
 )
 
 // Test_DefaultNamespace ensures that if there is an active kubeconfig,
@@ -56,6 +59,128 @@ func Test_setHealthEndpoints(t *testing.T) {
 	}
 }
 
+// Test_InitContainers ensures that configured init containers are applied
+// to the generated service's pod spec, with the function's volume mounts
+// shared and a name generated when not provided, and that a function
+// without init containers is unaffected.
+func Test_InitContainers(t *testing.T) {
+	f := fn.Function{
+		Name:    "testing",
+		Runtime: "go",
+	}
+	svc, err := generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svc.Spec.Template.Spec.InitContainers) != 0 {
+		t.Fatal("expected no init containers by default")
+	}
+
+	f.Run.InitContainers = []fn.InitContainer{
+		{Image: "example.com/migrate:latest", Command: []string{"migrate", "up"}},
+		{Name: "fetch-config", Image: "example.com/fetch:latest"},
+	}
+	svc, err = generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ics := svc.Spec.Template.Spec.InitContainers
+	if len(ics) != 2 {
+		t.Fatalf("expected 2 init containers, got %v", len(ics))
+	}
+	if ics[0].Name != "init-0" {
+		t.Errorf("expected generated name \"init-0\", got %q", ics[0].Name)
+	}
+	if ics[0].Image != "example.com/migrate:latest" {
+		t.Errorf("unexpected image %q", ics[0].Image)
+	}
+	if len(ics[0].Command) != 2 || ics[0].Command[0] != "migrate" {
+		t.Errorf("unexpected command %v", ics[0].Command)
+	}
+	if ics[1].Name != "fetch-config" {
+		t.Errorf("expected name \"fetch-config\", got %q", ics[1].Name)
+	}
+}
+
+// Test_setHealthEndpointsTyped ensures that typed endpoints are mapped to
+// the probe of their type, including a dedicated startup probe, and that a
+// custom port is applied.
+func Test_setHealthEndpointsTyped(t *testing.T) {
+	f := fn.Function{
+		Name: "testing",
+		Deploy: fn.DeploySpec{
+			HealthEndpoints: fn.HealthEndpoints{
+				Liveness: "/lively",
+				Endpoints: []fn.HealthEndpoint{
+					{Type: fn.ProbeLiveness, Path: "/alive"},
+					{Type: fn.ProbeStartup, Path: "/started", Port: 8081},
+				},
+			},
+		},
+	}
+	c := corev1.Container{}
+	setHealthEndpoints(f, &c)
+
+	// The typed entry takes precedence over the simple liveness path.
+	if got := c.LivenessProbe.HTTPGet.Path; got != "/alive" {
+		t.Errorf("expected \"/alive\" but got %v", got)
+	}
+	// Untyped defaults remain in effect for types without an entry.
+	if got := c.ReadinessProbe.HTTPGet.Path; got != READINESS_ENDPOINT {
+		t.Errorf("expected %q but got %v", READINESS_ENDPOINT, got)
+	}
+	if c.StartupProbe == nil {
+		t.Fatal("expected a startup probe to be set")
+	}
+	if got := c.StartupProbe.HTTPGet.Path; got != "/started" {
+		t.Errorf("expected \"/started\" but got %v", got)
+	}
+	if got := c.StartupProbe.HTTPGet.Port.IntValue(); got != 8081 {
+		t.Errorf("expected port 8081 but got %v", got)
+	}
+}
+
+// Test_setHealthEndpointTimings ensures that probe timings, when provided,
+// are applied to both probes, with the platform defaults (zero values)
+// used otherwise.
+func Test_setHealthEndpointTimings(t *testing.T) {
+	var (
+		delay     int32 = 15
+		period    int32 = 5
+		threshold int32 = 6
+	)
+	f := fn.Function{
+		Name: "testing",
+		Deploy: fn.DeploySpec{
+			HealthEndpoints: fn.HealthEndpoints{
+				InitialDelaySeconds: &delay,
+				PeriodSeconds:       &period,
+				FailureThreshold:    &threshold,
+			},
+		},
+	}
+	c := corev1.Container{}
+	setHealthEndpoints(f, &c)
+	for _, probe := range []*corev1.Probe{c.LivenessProbe, c.ReadinessProbe} {
+		if probe.InitialDelaySeconds != delay {
+			t.Errorf("expected initial delay %v, got %v", delay, probe.InitialDelaySeconds)
+		}
+		if probe.PeriodSeconds != period {
+			t.Errorf("expected period %v, got %v", period, probe.PeriodSeconds)
+		}
+		if probe.FailureThreshold != threshold {
+			t.Errorf("expected failure threshold %v, got %v", threshold, probe.FailureThreshold)
+		}
+	}
+
+	// Timings left unset defer to the platform defaults.
+	c = corev1.Container{}
+	setHealthEndpoints(fn.Function{Name: "testing"}, &c)
+	if c.LivenessProbe.InitialDelaySeconds != 0 || c.LivenessProbe.PeriodSeconds != 0 {
+		t.Error("expected unset timings to remain zero valued")
+	}
+}
+
 func Test_setHealthEndpointDefaults(t *testing.T) {
 	f := fn.Function{
 		Name: "testing",
@@ -115,3 +240,226 @@ func Test_processValue(t *testing.T) {
 		})
 	}
 }
+
+// Test_ImagePullPolicy ensures that the function's configured image pull
+// policy is applied to the service's container, with the platform's default
+// (unset) used otherwise.
+func Test_ImagePullPolicy(t *testing.T) {
+	f := fn.Function{Name: "testing", Runtime: "go"}
+
+	service, err := generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := service.Spec.Template.Spec.Containers[0].ImagePullPolicy; p != "" {
+		t.Fatalf("expected no pull policy by default, got '%v'", p)
+	}
+
+	f.Run.ImagePullPolicy = "Always"
+	if service, err = generateNewService(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	if p := service.Spec.Template.Spec.Containers[0].ImagePullPolicy; p != corev1.PullAlways {
+		t.Fatalf("expected pull policy '%v', got '%v'", corev1.PullAlways, p)
+	}
+}
+
+// Test_CommandOverride ensures the run spec's command and args overrides are
+// applied to the service container, with the image defaults (empty) when
+// unset.
+func Test_CommandOverride(t *testing.T) {
+	f := fn.Function{Name: "testing", Runtime: "go"}
+
+	service, err := generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := service.Spec.Template.Spec.Containers[0]
+	if len(c.Command) != 0 || len(c.Args) != 0 {
+		t.Fatalf("expected no command or args by default, got %v %v", c.Command, c.Args)
+	}
+
+	f.Run.Command = []string{"/wrapper.sh"}
+	f.Run.Args = []string{"--profile"}
+	if service, err = generateNewService(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	c = service.Spec.Template.Spec.Containers[0]
+	if len(c.Command) != 1 || c.Command[0] != "/wrapper.sh" {
+		t.Fatalf("expected command override, got %v", c.Command)
+	}
+	if len(c.Args) != 1 || c.Args[0] != "--profile" {
+		t.Fatalf("expected args override, got %v", c.Args)
+	}
+}
+
+// Test_ServiceAccountName ensures the run spec's service account is applied
+// to the service's pod spec, with the namespace default (empty) when unset.
+func Test_ServiceAccountName(t *testing.T) {
+	f := fn.Function{Name: "testing", Runtime: "go"}
+
+	service, err := generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sa := service.Spec.Template.Spec.ServiceAccountName; sa != "" {
+		t.Fatalf("expected no service account by default, got '%v'", sa)
+	}
+
+	f.Run.ServiceAccountName = "my-sa"
+	if service, err = generateNewService(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sa := service.Spec.Template.Spec.ServiceAccountName; sa != "my-sa" {
+		t.Fatalf("expected service account 'my-sa', got '%v'", sa)
+	}
+}
+
+// Test_RunResources ensures run-level resource requests and limits are
+// applied to the service container, with deploy options taking precedence.
+func Test_RunResources(t *testing.T) {
+	f := fn.Function{Name: "testing", Runtime: "go"}
+
+	// No resources configured leaves the container unconstrained
+	service, err := generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if service.Spec.Template.Spec.Containers[0].Resources.Requests != nil {
+		t.Fatal("expected no resource requests by default")
+	}
+
+	// Run-level resources are applied
+	f.Run.Resources = &fn.ResourcesOptions{
+		Requests: &fn.ResourcesRequestsOptions{CPU: ptr.String("100m")},
+	}
+	if service, err = generateNewService(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	requests := service.Spec.Template.Spec.Containers[0].Resources.Requests
+	if v := requests[corev1.ResourceCPU]; v.String() != "100m" {
+		t.Fatalf("expected cpu request '100m', got '%v'", v.String())
+	}
+
+	// Deploy options, being the more specific setting, take precedence
+	f.Deploy.Options.Resources = &fn.ResourcesOptions{
+		Requests: &fn.ResourcesRequestsOptions{CPU: ptr.String("200m")},
+	}
+	if service, err = generateNewService(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	requests = service.Spec.Template.Spec.Containers[0].Resources.Requests
+	if v := requests[corev1.ResourceCPU]; v.String() != "200m" {
+		t.Fatalf("expected cpu request '200m', got '%v'", v.String())
+	}
+}
+
+// Test_Manifest ensures the deployer can render the service manifest it
+// would apply as YAML without contacting the cluster, including the
+// resolved image digest.
+func Test_Manifest(t *testing.T) {
+	f := fn.Function{
+		Name:        "testing",
+		Runtime:     "go",
+		Image:       "example.com/alice/testing:latest",
+		ImageDigest: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+	}
+
+	d := NewDeployer(WithDeployerNamespace("func"))
+	manifest, err := d.Manifest(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"apiVersion: serving.knative.dev/v1",
+		"kind: Service",
+		"namespace: func",
+		f.ImageWithDigest(),
+	} {
+		if !strings.Contains(string(manifest), expected) {
+			t.Errorf("manifest does not contain '%v':\n%v", expected, string(manifest))
+		}
+	}
+}
+
+// Test_AnnotationTemplating ensures annotation values may reference function
+// metadata via templates, and that invalid references error naming the
+// offending annotation.
+func Test_AnnotationTemplating(t *testing.T) {
+	f := fn.Function{Name: "testing", Runtime: "go"}
+	f.Build.Git.Revision = "deadbeef"
+	f.Deploy.Annotations = map[string]string{
+		"static":                     "value",
+		"app.kubernetes.io/name":     "{{.Function.Name}}",
+		"app.kubernetes.io/revision": "{{.Git.Revision}}",
+	}
+
+	service, err := generateNewService(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations := service.ObjectMeta.Annotations
+	if annotations["static"] != "value" {
+		t.Errorf("expected static annotation to pass through, got '%v'", annotations["static"])
+	}
+	if annotations["app.kubernetes.io/name"] != "testing" {
+		t.Errorf("expected function name 'testing', got '%v'", annotations["app.kubernetes.io/name"])
+	}
+	if annotations["app.kubernetes.io/revision"] != "deadbeef" {
+		t.Errorf("expected git revision 'deadbeef', got '%v'", annotations["app.kubernetes.io/revision"])
+	}
+
+	// An invalid reference errors, naming the annotation
+	f.Deploy.Annotations = map[string]string{"bad": "{{.Function.Nonexistent}}"}
+	if _, err = generateNewService(f, nil); err == nil {
+		t.Fatal("expected error interpolating an invalid template reference, got nil")
+	} else if !strings.Contains(err.Error(), "bad") {
+		t.Errorf("expected error to name the annotation, got: %v", err)
+	}
+}
+
+// Test_ExpandVolumePath ensures that {{ env:NAME }} references embedded in
+// volume mount paths are expanded from the local environment, that an
+// undefined variable fails naming the volume and variable, and that the
+// expanded path must remain absolute.
+func Test_ExpandVolumePath(t *testing.T) {
+	t.Setenv("TENANT", "acme")
+
+	volume := func(path string) fn.Volume {
+		secret := "creds"
+		return fn.Volume{Secret: &secret, Path: &path}
+	}
+
+	// Literal paths pass through unmodified.
+	path, err := expandVolumePath(volume("/etc/secret-volume"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/etc/secret-volume" {
+		t.Fatalf("literal path modified: %v", path)
+	}
+
+	// Embedded references are expanded.
+	if path, err = expandVolumePath(volume("/data/{{ env:TENANT }}/config")); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/data/acme/config" {
+		t.Fatalf("expected expanded path, got %v", path)
+	}
+
+	// Undefined variables fail, naming the volume and the variable.
+	_, err = expandVolumePath(volume("/data/{{ env:UNDEFINED_TENANT }}"))
+	if err == nil {
+		t.Fatal("expected error for undefined variable")
+	}
+	if !strings.Contains(err.Error(), "UNDEFINED_TENANT") || !strings.Contains(err.Error(), "creds") {
+		t.Fatalf("expected the volume and variable to be named, got: %v", err)
+	}
+
+	// The expanded path must remain absolute.
+	t.Setenv("TENANT", "relative")
+	if _, err = expandVolumePath(volume("{{ env:TENANT }}/data")); err == nil {
+		t.Fatal("expected error for non-absolute expanded path")
+	}
+}
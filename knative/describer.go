@@ -2,10 +2,12 @@ package knative
 
 import (
 	"context"
+	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/serving/pkg/apis/autoscaling"
 
 	fn "knative.dev/kn-plugin-func"
 	"knative.dev/kn-plugin-func/k8s"
@@ -23,6 +25,40 @@ func NewDescriber(namespaceOverride string, verbose bool) *Describer {
 	}
 }
 
+// RevisionURL returns the URL of the named revision (or traffic tag) of the
+// function.  Only revisions assigned a dedicated URL via a traffic tag are
+// directly addressable; targeting an untagged revision by name is an error,
+// as is a revision which does not exist in the route.
+func (d *Describer) RevisionURL(ctx context.Context, name, revision string) (string, error) {
+	var err error
+	if d.namespace == "" {
+		if d.namespace, err = k8s.GetNamespace(d.namespace); err != nil {
+			return "", err
+		}
+	}
+
+	servingClient, err := NewServingClient(d.namespace)
+	if err != nil {
+		return "", err
+	}
+
+	route, err := servingClient.GetRoute(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	for _, target := range route.Status.Traffic {
+		if target.Tag != revision && target.RevisionName != revision {
+			continue
+		}
+		if target.URL != nil {
+			return target.URL.String(), nil
+		}
+		return "", fmt.Errorf("revision '%v' of function '%v' has no dedicated URL; assign it a traffic tag to address it directly", revision, name)
+	}
+	return "", fmt.Errorf("no revision or traffic tag '%v' found for function '%v'", revision, name)
+}
+
 // Describe by name. Note that the consuming API uses domain style notation, whereas Kubernetes
 // restricts to label-syntax, which is thus escaped. Therefore as a knative (kube) implementation
 // detal proper full names have to be escaped on the way in and unescaped on the way out. ex:
@@ -69,13 +105,16 @@ func (d *Describer) Describe(ctx context.Context, name string) (description fn.I
 	description.Namespace = d.namespace
 	description.Route = primaryRouteURL
 	description.Routes = routeURLs
+	description.Subscriptions = []fn.Subscription{}
 
 	triggers, err := eventingClient.ListTriggers(ctx)
-	// IsNotFound -- Eventing is probably not installed on the cluster
-	if err != nil && !errors.IsNotFound(err) {
-		err = nil
-		return
-	} else if err != nil {
+	if err != nil {
+		// IsNotFound -- Eventing is probably not installed on the cluster.
+		// Functions without subscriptions are valid; report none rather
+		// than erroring.
+		if errors.IsNotFound(err) {
+			err = nil
+		}
 		return
 	}
 
@@ -101,5 +140,35 @@ func (d *Describer) Describe(ctx context.Context, name string) (description fn.I
 
 	description.Subscriptions = subscriptions
 
+	// Include pertinent portions of the deployed spec such that local
+	// configuration can be compared against it (see Client.Diff).
+	template := service.Spec.Template
+	if len(template.Spec.Containers) > 0 {
+		container := template.Spec.Containers[0]
+		description.Image = container.Image
+		envs := map[string]string{}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				envs[env.Name] = env.Value
+			}
+		}
+		if len(envs) > 0 {
+			description.Envs = envs
+		}
+		for _, mount := range container.VolumeMounts {
+			description.VolumeMounts = append(description.VolumeMounts, mount.MountPath)
+		}
+	}
+	description.Labels = service.Labels
+	scaling := map[string]string{}
+	for _, key := range []string{autoscaling.MinScaleAnnotationKey, autoscaling.MaxScaleAnnotationKey} {
+		if value, ok := template.Annotations[key]; ok {
+			scaling[key] = value
+		}
+	}
+	if len(scaling) > 0 {
+		description.Scaling = scaling
+	}
+
 	return
 }
@@ -24,20 +24,33 @@ func NewLister(namespaceOverride string, verbose bool) *Lister {
 	}
 }
 
-func (l *Lister) List(ctx context.Context) (items []fn.ListItem, err error) {
-	if l.Namespace == "" {
-		l.Namespace, err = k8s.GetNamespace(l.Namespace)
+func (l *Lister) List(ctx context.Context, opts fn.ListOptions) (items []fn.ListItem, err error) {
+	// An explicitly requested namespace takes precedence over the lister's
+	// configured override, which in turn defaults to the active namespace.
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = l.Namespace
+	}
+	if namespace == "" {
+		namespace, err = k8s.GetNamespace(namespace)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	client, err := NewServingClient(l.Namespace)
+	client, err := NewServingClient(namespace)
 	if err != nil {
 		return
 	}
 
-	lst, err := client.ListServices(ctx, clientservingv1.WithLabel(labels.FunctionKey, labels.FunctionValue))
+	// A requested runtime is filtered server-side via label selector.
+	// Errors, including permission errors for inaccessible namespaces, are
+	// returned as-is rather than yielding an empty list.
+	selectors := []clientservingv1.ListConfig{clientservingv1.WithLabel(labels.FunctionKey, labels.FunctionValue)}
+	if opts.Runtime != "" {
+		selectors = append(selectors, clientservingv1.WithLabel(labels.FunctionRuntimeKey, opts.Runtime))
+	}
+	lst, err := client.ListServices(ctx, selectors...)
 	if err != nil {
 		return
 	}
@@ -83,6 +96,12 @@ func (l *Lister) List(ctx context.Context) (items []fn.ListItem, err error) {
 		}
 		// --- end of handling usage of deprecated runtime labels
 
+		// Services matched only via the deprecated function label are
+		// filtered by runtime here, as the selector above does not apply.
+		if opts.Runtime != "" && runtimeLabel != opts.Runtime {
+			continue
+		}
+
 		listItem := fn.ListItem{
 			Name:      f.Name,
 			Namespace: f.Namespace,
@@ -0,0 +1,144 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// Promoter copies deployed functions between namespaces by reading the
+// backing Knative Service from the source and re-applying it to the target,
+// pinned to the exact image digest currently serving traffic such that no
+// rebuild occurs.
+type Promoter struct {
+	verbose bool
+}
+
+// NewPromoter creates a promoter of functions between namespaces.
+func NewPromoter(verbose bool) *Promoter {
+	return &Promoter{verbose: verbose}
+}
+
+// Promote the named function from one namespace to another.  A function of
+// the same name already deployed in the target namespace is updated in
+// place.  Referenced Secrets and ConfigMaps missing in the target namespace
+// are warned about but do not block the promotion: they are commonly
+// created out-of-band by cluster administrators.
+func (p *Promoter) Promote(ctx context.Context, name, fromNamespace, toNamespace string) error {
+	sourceClient, err := NewServingClient(fromNamespace)
+	if err != nil {
+		return err
+	}
+
+	service, err := sourceClient.GetService(ctx, name)
+	if err != nil {
+		return fmt.Errorf("promoter failed to get the Knative Service: %v", err)
+	}
+
+	promoted := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   toNamespace,
+			Labels:      service.Labels,
+			Annotations: service.Annotations,
+		},
+		Spec: *service.Spec.DeepCopy(),
+	}
+
+	// Pin containers to the digests currently serving traffic in the source
+	// such that the target runs the exact same bits, even if the tag has
+	// since been re-pushed.
+	if revisionName := service.Status.LatestReadyRevisionName; revisionName != "" {
+		revision, err := sourceClient.GetRevision(ctx, revisionName)
+		if err != nil {
+			return fmt.Errorf("promoter failed to get revision %q: %v", revisionName, err)
+		}
+		digests := make(map[string]string)
+		for _, status := range revision.Status.ContainerStatuses {
+			if status.ImageDigest != "" {
+				digests[status.Name] = status.ImageDigest
+			}
+		}
+		for i, container := range promoted.Spec.Template.Spec.Containers {
+			if digest, ok := digests[container.Name]; ok {
+				promoted.Spec.Template.Spec.Containers[i].Image = digest
+			}
+		}
+	}
+
+	// Warn when the promoted spec references Secrets or ConfigMaps which do
+	// not (yet) exist in the target namespace.
+	referencedSecrets, referencedConfigMaps := referencedSecretsAndConfigMaps(&promoted.Spec.Template.Spec.PodSpec)
+	if err := checkSecretsConfigMapsArePresent(ctx, toNamespace, &referencedSecrets, &referencedConfigMaps); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: promoting %q:%v", name, err)
+	}
+
+	targetClient, err := NewServingClient(toNamespace)
+	if err != nil {
+		return err
+	}
+
+	existing, err := targetClient.GetService(ctx, name)
+	if err == nil {
+		existing.Spec = promoted.Spec
+		existing.Labels = promoted.Labels
+		existing.Annotations = promoted.Annotations
+		if _, err = targetClient.UpdateService(ctx, existing); err != nil {
+			return fmt.Errorf("promoter failed to update the Knative Service: %v", err)
+		}
+	} else if errors.IsNotFound(err) {
+		if err = targetClient.CreateService(ctx, promoted); err != nil {
+			return fmt.Errorf("promoter failed to create the Knative Service: %v", err)
+		}
+	} else {
+		return fmt.Errorf("promoter failed to get the Knative Service: %v", err)
+	}
+
+	if p.verbose {
+		fmt.Printf("Function %q promoted from namespace %q to %q\n", name, fromNamespace, toNamespace)
+	}
+	return nil
+}
+
+// referencedSecretsAndConfigMaps returns the names of Secrets and ConfigMaps
+// referenced by the pod spec's containers (env, envFrom) and volumes.
+func referencedSecretsAndConfigMaps(spec *corev1.PodSpec) (secrets, configMaps sets.String) {
+	secrets = sets.NewString()
+	configMaps = sets.NewString()
+	for _, container := range spec.Containers {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets.Insert(env.ValueFrom.SecretKeyRef.Name)
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps.Insert(env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				secrets.Insert(envFrom.SecretRef.Name)
+			}
+			if envFrom.ConfigMapRef != nil {
+				configMaps.Insert(envFrom.ConfigMapRef.Name)
+			}
+		}
+	}
+	for _, volume := range spec.Volumes {
+		if volume.Secret != nil {
+			secrets.Insert(volume.Secret.SecretName)
+		}
+		if volume.ConfigMap != nil {
+			configMaps.Insert(volume.ConfigMap.Name)
+		}
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	"knative.dev/kn-plugin-func/k8s"
+)
+
+// Rollbacker reverts functions to a previously deployed revision by moving
+// all traffic of the backing Knative Service.
+type Rollbacker struct {
+	namespace string
+	verbose   bool
+}
+
+// NewRollbacker creates a rollbacker of functions deployed in the given
+// namespace (empty for the currently active namespace).
+func NewRollbacker(namespaceOverride string, verbose bool) *Rollbacker {
+	return &Rollbacker{namespace: namespaceOverride, verbose: verbose}
+}
+
+// Rollback moves 100% of the function's traffic to the given revision, or
+// to the previous Ready revision when none is specified.  A function with
+// no revision prior to the current is an error:  there is nothing to roll
+// back to.
+func (r *Rollbacker) Rollback(ctx context.Context, name, revision string) error {
+	namespace := r.namespace
+	if namespace == "" {
+		var err error
+		if namespace, err = k8s.GetNamespace(namespace); err != nil {
+			return err
+		}
+	}
+
+	client, err := NewServingClient(namespace)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := client.ListRevisions(ctx, clientservingv1.WithService(name))
+	if err != nil {
+		return fmt.Errorf("rollbacker failed to list revisions: %v", err)
+	}
+
+	// Newest first
+	items := revisions.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[j].CreationTimestamp.Before(&items[i].CreationTimestamp)
+	})
+
+	if revision == "" {
+		// Default to the previous Ready revision.
+		if len(items) < 2 {
+			return fmt.Errorf("function %q has no previous revision: nothing to roll back to", name)
+		}
+		for i := range items[1:] {
+			if items[i+1].IsReady() {
+				revision = items[i+1].Name
+				break
+			}
+		}
+		if revision == "" {
+			return fmt.Errorf("function %q has no previous Ready revision to roll back to", name)
+		}
+	} else {
+		// An explicitly requested revision must exist.
+		found := false
+		for i := range items {
+			if items[i].Name == revision {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("revision %q not found for function %q", revision, name)
+		}
+	}
+
+	if r.verbose {
+		fmt.Printf("Rolling back %q to revision %q\n", name, revision)
+	}
+
+	percent := int64(100)
+	_, err = client.UpdateServiceWithRetry(ctx, name, func(service *v1.Service) (*v1.Service, error) {
+		service.Spec.Traffic = []v1.TrafficTarget{{RevisionName: revision, Percent: &percent}}
+		return service, nil
+	}, 3)
+	if err != nil {
+		return fmt.Errorf("rollbacker failed to update traffic: %v", err)
+	}
+	return nil
+}
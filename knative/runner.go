@@ -0,0 +1,210 @@
+package knative
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/k8s"
+)
+
+const (
+	// ephemeralSuffix is appended to the function's name for the short-lived
+	// service created by the cluster runner, such that an existing deployment
+	// of the function is left undisturbed.
+	ephemeralSuffix = "-run"
+
+	// removalTimeout bounds the removal of the ephemeral service on stop.
+	removalTimeout = 120 * time.Second
+)
+
+// ClusterRunner implements the Runner interface by running the function as
+// a short-lived service in the target cluster rather than a local container,
+// providing run semantics (ephemeral, logs to the terminal, torn down on
+// stop) while catching environment differences only visible in-cluster.
+// The service's route is proxied to a local port reported on the Job, and
+// the service is removed on Stop or cancellation of the run context.
+type ClusterRunner struct {
+	namespace string
+	verbose   bool
+	out       io.Writer
+	deployer  fn.Deployer
+	remover   fn.Remover
+}
+
+type ClusterRunnerOpt func(*ClusterRunner)
+
+// WithClusterDeployer overrides the deployer used to create the ephemeral
+// service (primarily for testing).
+func WithClusterDeployer(d fn.Deployer) ClusterRunnerOpt {
+	return func(r *ClusterRunner) {
+		r.deployer = d
+	}
+}
+
+// WithClusterRemover overrides the remover used to tear down the ephemeral
+// service (primarily for testing).
+func WithClusterRemover(rm fn.Remover) ClusterRunnerOpt {
+	return func(r *ClusterRunner) {
+		r.remover = rm
+	}
+}
+
+// WithClusterRunnerOutput sets the writer to which service logs are
+// streamed.  Defaults to stderr.
+func WithClusterRunnerOutput(out io.Writer) ClusterRunnerOpt {
+	return func(r *ClusterRunner) {
+		r.out = out
+	}
+}
+
+// NewClusterRunner creates a runner which deploys the function to the
+// cluster (in the given namespace, or the currently active namespace when
+// empty) for the duration of the run.
+func NewClusterRunner(namespaceOverride string, verbose bool, opts ...ClusterRunnerOpt) *ClusterRunner {
+	r := &ClusterRunner{
+		namespace: namespaceOverride,
+		verbose:   verbose,
+		out:       os.Stderr,
+		deployer: NewDeployer(
+			WithDeployerNamespace(namespaceOverride),
+			WithDeployerVerbose(verbose)),
+		remover: NewRemover(namespaceOverride, verbose),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run the function as an ephemeral service in the cluster.  The returned
+// Job's port is a local port proxied to the service's route.  Cleanup is
+// guaranteed for both explicit stops and cancellation of the given context:
+// the ephemeral service is removed using a fresh background context (bounded
+// by removalTimeout) such that teardown proceeds even after ctx is canceled.
+func (r *ClusterRunner) Run(ctx context.Context, f fn.Function) (*fn.Job, error) {
+	if f.Name == "" {
+		return nil, errors.New("function has no name; unable to run in cluster")
+	}
+
+	// Deploy a uniquely-named ephemeral instance alongside any existing
+	// deployment of the function.
+	ephemeral := f
+	ephemeral.Name = f.Name + ephemeralSuffix
+	result, err := r.deployer.Deploy(ctx, ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := url.Parse(result.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse route of ephemeral service: %w", err)
+	}
+
+	// Proxy the route to a local port for parity with the local runner.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Internal context governing the log stream, canceled on stop (rather
+	// than derived from ctx, whose cancellation itself triggers stop).
+	runCtx, cancel := context.WithCancel(context.Background())
+
+	errs := make(chan error, 10)
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Host = target.Host // route via the service's host header
+	}
+	server := &http.Server{Handler: proxy}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+	}()
+
+	// Stream the service's logs to the configured output.  Best-effort:
+	// failure to stream logs does not fail the run.
+	go func() {
+		if err := streamServiceLogs(runCtx, r.namespace, ephemeral.Name, r.out); err != nil && runCtx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "warning: unable to stream logs of '%v': %v\n", ephemeral.Name, err)
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			_ = server.Close()
+			rmCtx, rmCancel := context.WithTimeout(context.Background(), removalTimeout)
+			defer rmCancel()
+			if err := r.remover.Remove(rmCtx, ephemeral.Name); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: unable to remove ephemeral service '%v': %v\n", ephemeral.Name, err)
+			}
+		})
+	}
+
+	// Guarantee teardown when the run context is canceled (e.g. Ctrl-C).
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return fn.NewJob(f, port, errs, stop)
+}
+
+// streamServiceLogs copies logs of the named service's first pod to out,
+// following until the context is canceled.
+func streamServiceLogs(ctx context.Context, namespace, service string, out io.Writer) error {
+	client, namespace, err := k8s.NewClientAndResolvedNamespace(namespace)
+	if err != nil {
+		return err
+	}
+
+	// Await the appearance of a pod backing the service.
+	var podName string
+	for podName == "" {
+		list, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: "serving.knative.dev/service=" + service})
+		if err != nil {
+			return err
+		}
+		if len(list.Items) > 0 {
+			podName = list.Items[0].Name
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+
+	stream, err := client.CoreV1().Pods(namespace).GetLogs(podName,
+		&corev1.PodLogOptions{Follow: true, Container: "user-container"}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	_, err = io.Copy(out, stream)
+	return err
+}
@@ -0,0 +1,116 @@
+//go:build !integration
+// +build !integration
+
+package knative
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/mock"
+)
+
+// TestClusterRunner ensures that running deploys an ephemeral service
+// (named with the run suffix, leaving any existing deployment untouched),
+// proxies its route to the local port reported on the job, and removes the
+// service on stop.
+func TestClusterRunner(t *testing.T) {
+	// Backend standing in for the deployed service's route.
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "OK")
+	}))
+	defer backend.Close()
+
+	var deployedName string
+	deployer := mock.NewDeployerWithResult(&fn.DeploymentResult{
+		Status: fn.Deployed,
+		URL:    backend.URL,
+	})
+	deployer.DeployFn = func(f fn.Function) error {
+		deployedName = f.Name
+		return nil
+	}
+
+	var removedName string
+	remover := mock.NewRemover()
+	remover.RemoveFn = func(name string) error {
+		removedName = name
+		return nil
+	}
+
+	runner := NewClusterRunner("", false,
+		WithClusterDeployer(deployer),
+		WithClusterRemover(remover),
+		WithClusterRunnerOutput(io.Discard))
+
+	f := fn.Function{Root: t.TempDir(), Name: "testfunc"}
+
+	job, err := runner.Run(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deployedName != "testfunc"+ephemeralSuffix {
+		t.Fatalf("expected ephemeral service name 'testfunc%v', got '%v'", ephemeralSuffix, deployedName)
+	}
+
+	// Requests to the local port should be proxied to the service's route.
+	resp, err := http.Get("http://127.0.0.1:" + job.Port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "OK" {
+		t.Fatalf("unexpected response through proxy: '%v'", string(body))
+	}
+
+	job.Stop()
+
+	if !remover.RemoveInvoked {
+		t.Fatal("ephemeral service was not removed on stop")
+	}
+	if removedName != deployedName {
+		t.Fatalf("expected removal of '%v', got '%v'", deployedName, removedName)
+	}
+}
+
+// TestClusterRunnerCancel ensures the ephemeral service is removed when the
+// run context is canceled, even without an explicit stop.
+func TestClusterRunnerCancel(t *testing.T) {
+	deployer := mock.NewDeployerWithResult(&fn.DeploymentResult{
+		Status: fn.Deployed,
+		URL:    "http://example.com",
+	})
+	remover := mock.NewRemover()
+	remover.RemoveFn = func(string) error { return nil }
+
+	runner := NewClusterRunner("", false,
+		WithClusterDeployer(deployer),
+		WithClusterRemover(remover),
+		WithClusterRunnerOutput(io.Discard))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := runner.Run(ctx, fn.Function{Root: t.TempDir(), Name: "testfunc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	deadline := time.After(5 * time.Second)
+	for !remover.RemoveInvoked {
+		select {
+		case <-deadline:
+			t.Fatal("ephemeral service was not removed on context cancellation")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package knative
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/k8s"
+)
+
+// Subscriber binds functions to event sources by managing Knative Eventing
+// Triggers: one per subscription, directing events from the subscription's
+// broker (filtered on type and, when given, source) to the function's
+// Knative Service.
+type Subscriber struct {
+	namespace string
+	verbose   bool
+}
+
+// NewSubscriber creates a subscriber of functions to events in the given
+// namespace (empty for the currently active namespace).
+func NewSubscriber(namespaceOverride string, verbose bool) *Subscriber {
+	return &Subscriber{namespace: namespaceOverride, verbose: verbose}
+}
+
+// Subscribe the given function to events matching the subscription.  The
+// operation is idempotent: the backing Trigger has a name derived from the
+// function and subscription, and is updated in place when it already
+// exists.
+func (s *Subscriber) Subscribe(ctx context.Context, f fn.Function, sub fn.Subscription) error {
+	namespace := s.namespace
+	if namespace == "" {
+		var err error
+		if namespace, err = k8s.GetNamespace(namespace); err != nil {
+			return err
+		}
+	}
+
+	client, err := NewEventingClient(namespace)
+	if err != nil {
+		return err
+	}
+
+	trigger := triggerFor(f, sub)
+
+	existing, err := client.GetTrigger(ctx, trigger.Name)
+	if err == nil {
+		existing.Spec = trigger.Spec
+		if err = client.UpdateTrigger(ctx, existing); err != nil {
+			return fmt.Errorf("subscriber failed to update the Trigger: %v", err)
+		}
+	} else if errors.IsNotFound(err) {
+		if err = client.CreateTrigger(ctx, trigger); err != nil {
+			return fmt.Errorf("subscriber failed to create the Trigger: %v", err)
+		}
+	} else {
+		return fmt.Errorf("subscriber failed to get the Trigger: %v", err)
+	}
+
+	if s.verbose {
+		fmt.Printf("Function %q subscribed to %q events on broker %q\n", f.Name, sub.Type, sub.Broker)
+	}
+	return nil
+}
+
+// triggerFor returns the Trigger backing the given subscription of the
+// function.  The name is deterministic such that re-subscribing yields an
+// update rather than a duplicate.
+func triggerFor(f fn.Function, sub fn.Subscription) *eventingv1.Trigger {
+	filter := eventingv1.TriggerFilterAttributes{"type": sub.Type}
+	if sub.Source != "" {
+		filter["source"] = sub.Source
+	}
+	sum := sha256.Sum256([]byte(sub.Broker + "\x00" + sub.Type + "\x00" + sub.Source))
+	return &eventingv1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%v-%x", f.Name, sum[:4]),
+		},
+		Spec: eventingv1.TriggerSpec{
+			Broker: sub.Broker,
+			Filter: &eventingv1.TriggerFilter{Attributes: filter},
+			Subscriber: duckv1.Destination{
+				Ref: &duckv1.KReference{
+					APIVersion: "serving.knative.dev/v1",
+					Kind:       "Service",
+					Name:       f.Name,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package function
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LintIssue is a single problem found when linting a function's source,
+// reported with its location so the user can correct it before a build or
+// deploy surfaces the problem at runtime.
+type LintIssue struct {
+	File    string // path of the offending file, relative to the function root
+	Line    int    // line at which the issue was found (0 if not applicable)
+	Message string // human-readable description of the issue
+}
+
+func (i LintIssue) String() string {
+	if i.File == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%v:%v: %v", i.File, i.Line, i.Message)
+}
+
+// Linter checks a function's source for structural problems, such as a
+// handler which no longer matches the signature expected by the runtime.
+// Implementations are runtime-specific; see WithLinter for registration.
+type Linter interface {
+	// Lint the given function, returning any issues found.  An error is
+	// returned only when the check itself could not be performed; problems
+	// with the function's source are reported as issues.
+	Lint(f Function) ([]LintIssue, error)
+}
+
+// Lint the function at the given path, reporting issues such as a missing
+// or incompatibly-defined handler for the function's runtime and invocation
+// format.  Runtimes for which no linter is registered yield no issues.
+func (c *Client) Lint(path string) ([]LintIssue, error) {
+	f, err := NewFunction(path)
+	if err != nil {
+		return nil, err
+	}
+	if !f.Initialized() {
+		return nil, fmt.Errorf("the given path '%v' does not contain an initialized function", path)
+	}
+	linter, ok := c.linters[f.Runtime]
+	if !ok {
+		return nil, nil // no linter available for the runtime; nothing to report
+	}
+	return linter.Lint(f)
+}
+
+// goLinter confirms a Go function exports a Handle function whose signature
+// is compatible with the function's invocation format.
+type goLinter struct{}
+
+func (l goLinter) Lint(f Function) (issues []LintIssue, err error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, f.Root, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return
+	}
+
+	var handle *ast.FuncDecl
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == "Handle" {
+					handle = fd
+				}
+			}
+		}
+	}
+	if handle == nil {
+		return []LintIssue{{Message: "no exported Handle function found"}}, nil
+	}
+
+	issue := func(message string) {
+		pos := fset.Position(handle.Pos())
+		file, _ := filepath.Rel(f.Root, pos.Filename)
+		issues = append(issues, LintIssue{File: file, Line: pos.Line, Message: message})
+	}
+
+	params := handle.Type.Params.List
+	if len(params) == 0 || typeString(params[0].Type) != "context.Context" {
+		issue("Handle's first parameter should be a context.Context")
+	}
+
+	// The remaining parameters should reference the types appropriate for
+	// the function's invocation format.
+	format := f.Invocation.Format
+	if format == "" {
+		format = DefaultInvocationFormat
+	}
+	var rest []string
+	if len(params) > 1 {
+		for _, p := range params[1:] {
+			rest = append(rest, typeString(p.Type))
+		}
+	}
+	switch format {
+	case "http":
+		if !contains(rest, "*http.Request") {
+			issue("Handle for an 'http' function should accept a *http.Request")
+		}
+	case "cloudevent":
+		if !containsSuffix(rest, "event.Event") {
+			issue("Handle for a 'cloudevent' function should accept an event.Event")
+		}
+	}
+	return
+}
+
+// nodeLinter confirms a Node.js function's main file exports its handler.
+type nodeLinter struct{}
+
+func (l nodeLinter) Lint(f Function) (issues []LintIssue, err error) {
+	file := "index.js"
+	bb, err := os.ReadFile(filepath.Join(f.Root, file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []LintIssue{{Message: fmt.Sprintf("main file '%v' not found", file)}}, nil
+		}
+		return
+	}
+	for _, line := range strings.Split(string(bb), "\n") {
+		if strings.Contains(line, "module.exports") || strings.Contains(line, "exports.handle") {
+			return nil, nil
+		}
+	}
+	return []LintIssue{{File: file, Message: "no exported handler found (expected a 'module.exports' declaration)"}}, nil
+}
+
+// typeString renders simple parameter type expressions (identifiers,
+// qualified names and pointers thereto) for comparison.
+func typeString(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return typeString(t.X) + "." + t.Sel.Name
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	}
+	return ""
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsSuffix(ss []string, suffix string) bool {
+	for _, v := range ss {
+		if strings.HasSuffix(v, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+//go:build !integration
+// +build !integration
+
+package function_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	fn "knative.dev/kn-plugin-func"
+	. "knative.dev/kn-plugin-func/testing"
+)
+
+// TestLint_Go ensures that linting a Go function verifies the existence
+// and signature of the exported Handle function, reporting issues with
+// their location rather than erring.
+func TestLint_Go(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: "go", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The unmodified template should lint clean.
+	issues, err := client.Lint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues for the unmodified template, got %v", issues)
+	}
+
+	// A handler with an incompatible signature should be reported with its
+	// file and line.
+	handler := `package function
+
+func Handle(name string) {}
+`
+	if err := os.WriteFile(filepath.Join(root, "handle.go"), []byte(handler), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if issues, err = client.Lint(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) == 0 {
+		t.Fatal("expected issues for an incompatible handler signature")
+	}
+	if issues[0].File != "handle.go" || issues[0].Line == 0 {
+		t.Fatalf("expected issue located in handle.go, got %v", issues[0])
+	}
+
+	// A missing handler should also be reported as an issue.
+	if err := os.WriteFile(filepath.Join(root, "handle.go"), []byte("package function\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if issues, err = client.Lint(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected a single missing-handler issue, got %v", issues)
+	}
+}
+
+// TestLint_Node ensures that linting a Node.js function verifies the main
+// file exports a handler.
+func TestLint_Node(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: "node", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := client.Lint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("expected no issues for the unmodified template, got %v", issues)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "index.js"), []byte("const handle = () => {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if issues, err = client.Lint(root); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("expected a missing-export issue, got %v", issues)
+	}
+}
+
+// TestLint_UnsupportedRuntime ensures that runtimes without a registered
+// linter yield no issues (and no error).
+func TestLint_UnsupportedRuntime(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: "python", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := client.Lint(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issues != nil {
+		t.Fatalf("expected no issues for a runtime without a linter, got %v", issues)
+	}
+}
@@ -0,0 +1,19 @@
+package mock
+
+import "context"
+
+type BrokerResolver struct {
+	BrokerURLInvoked bool
+	BrokerURLFn      func(context.Context, string) (string, error)
+}
+
+func NewBrokerResolver() *BrokerResolver {
+	return &BrokerResolver{
+		BrokerURLFn: func(context.Context, string) (string, error) { return "", nil },
+	}
+}
+
+func (r *BrokerResolver) BrokerURL(ctx context.Context, name string) (string, error) {
+	r.BrokerURLInvoked = true
+	return r.BrokerURLFn(ctx, name)
+}
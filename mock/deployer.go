@@ -7,21 +7,25 @@ import (
 )
 
 type Deployer struct {
-	DeployInvoked bool
-	DeployFn      func(fn.Function) error
-	DeployResult  *fn.DeploymentResult
+	DeployInvoked         bool
+	DeployFn              func(fn.Function) error
+	DeployResult          *fn.DeploymentResult
+	PruneRevisionsInvoked bool
+	PruneRevisionsFn      func(fn.Function, int) error
 }
 
 func NewDeployer() *Deployer {
 	return &Deployer{
-		DeployFn: func(fn.Function) error { return nil },
+		DeployFn:         func(fn.Function) error { return nil },
+		PruneRevisionsFn: func(fn.Function, int) error { return nil },
 	}
 }
 
 func NewDeployerWithResult(result *fn.DeploymentResult) *Deployer {
 	return &Deployer{
-		DeployFn:     func(fn.Function) error { return nil },
-		DeployResult: result,
+		DeployFn:         func(fn.Function) error { return nil },
+		PruneRevisionsFn: func(fn.Function, int) error { return nil },
+		DeployResult:     result,
 	}
 }
 
@@ -32,3 +36,8 @@ func (i *Deployer) Deploy(ctx context.Context, f fn.Function) (fn.DeploymentResu
 	}
 	return fn.DeploymentResult{}, i.DeployFn(f)
 }
+
+func (i *Deployer) PruneRevisions(ctx context.Context, f fn.Function, keep int) error {
+	i.PruneRevisionsInvoked = true
+	return i.PruneRevisionsFn(f, keep)
+}
@@ -0,0 +1,19 @@
+package mock
+
+import "context"
+
+type IngressResolver struct {
+	ExternalAddressInvoked bool
+	ExternalAddressFn      func(context.Context) (string, error)
+}
+
+func NewIngressResolver() *IngressResolver {
+	return &IngressResolver{
+		ExternalAddressFn: func(context.Context) (string, error) { return "", nil },
+	}
+}
+
+func (i *IngressResolver) ExternalAddress(ctx context.Context) (string, error) {
+	i.ExternalAddressInvoked = true
+	return i.ExternalAddressFn(ctx)
+}
@@ -0,0 +1,19 @@
+package mock
+
+import "context"
+
+type Promoter struct {
+	PromoteInvoked bool
+	PromoteFn      func(context.Context, string, string, string) error
+}
+
+func NewPromoter() *Promoter {
+	return &Promoter{
+		PromoteFn: func(context.Context, string, string, string) error { return nil },
+	}
+}
+
+func (p *Promoter) Promote(ctx context.Context, name, fromNamespace, toNamespace string) error {
+	p.PromoteInvoked = true
+	return p.PromoteFn(ctx, name, fromNamespace, toNamespace)
+}
@@ -0,0 +1,19 @@
+package mock
+
+import "context"
+
+type Rollbacker struct {
+	RollbackInvoked bool
+	RollbackFn      func(context.Context, string, string) error
+}
+
+func NewRollbacker() *Rollbacker {
+	return &Rollbacker{
+		RollbackFn: func(context.Context, string, string) error { return nil },
+	}
+}
+
+func (r *Rollbacker) Rollback(ctx context.Context, name, revision string) error {
+	r.RollbackInvoked = true
+	return r.RollbackFn(ctx, name, revision)
+}
@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"context"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+type Subscriber struct {
+	SubscribeInvoked bool
+	SubscribeFn      func(context.Context, fn.Function, fn.Subscription) error
+}
+
+func NewSubscriber() *Subscriber {
+	return &Subscriber{
+		SubscribeFn: func(context.Context, fn.Function, fn.Subscription) error { return nil },
+	}
+}
+
+func (s *Subscriber) Subscribe(ctx context.Context, f fn.Function, sub fn.Subscription) error {
+	s.SubscribeInvoked = true
+	return s.SubscribeFn(ctx, f, sub)
+}
@@ -96,6 +96,12 @@ func (pp *PipelinesProvider) Run(ctx context.Context, f fn.Function) error {
 	}
 	pp.namespace = namespace
 
+	// The referenced git credentials secret (if any) must exist before
+	// attempting the run.
+	if err = validateGitCredentials(ctx, f, pp.namespace); err != nil {
+		return err
+	}
+
 	// let's specify labels that will be applied to every resouce that is created for a Pipeline
 	labels, err := f.LabelsMap()
 	if err != nil {
@@ -71,6 +71,7 @@ func generatePipeline(f fn.Function, labels map[string]string) *pplnv1beta1.Pipe
 	workspaces := []pplnv1beta1.PipelineWorkspaceDeclaration{
 		{Name: "source-workspace", Description: "Directory where function source is located."},
 		{Name: "dockerconfig-workspace", Description: "Directory containing image registry credentials stored in `config.json` file.", Optional: true},
+		{Name: "git-credentials-workspace", Description: "Secret containing credentials for the git repository.", Optional: true},
 	}
 
 	var taskBuild pplnv1beta1.PipelineTask
@@ -95,7 +96,7 @@ func generatePipeline(f fn.Function, labels map[string]string) *pplnv1beta1.Pipe
 
 	// ----- Pipeline definition
 	tasks := pplnv1beta1.PipelineTaskList{
-		taskFetchSources(),
+		taskFetchSources(f),
 		taskBuild,
 		taskDeploy(taskNameBuild, referenceImageFromPreviousTaskResults),
 	}
@@ -183,6 +184,23 @@ func generatePipelineRun(f fn.Function, labels map[string]string) *pplnv1beta1.P
 		},
 	}
 
+	// Private repositories: bind the referenced credentials secret.  SSH
+	// keys are projected to the location expected by the git-clone task.
+	if f.Build.Git.Credentials.SecretName != "" {
+		source := &corev1.SecretVolumeSource{
+			SecretName: f.Build.Git.Credentials.SecretName,
+		}
+		if f.Build.Git.Credentials.SSHPrivateKeyKey != "" {
+			source.Items = []corev1.KeyToPath{
+				{Key: f.Build.Git.Credentials.SSHPrivateKeyKey, Path: "id_rsa"},
+			}
+		}
+		workspaces = append(workspaces, pplnv1beta1.WorkspaceBinding{
+			Name:   "git-credentials-workspace",
+			Secret: source,
+		})
+	}
+
 	if f.Build.Builder == builders.Pack {
 		// ----- Buildpacks related properties
 
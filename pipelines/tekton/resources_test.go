@@ -52,3 +52,57 @@ func Test_generatePipeline(t *testing.T) {
 		})
 	}
 }
+
+func Test_generatePipelineRunGitCredentials(t *testing.T) {
+	tests := []struct {
+		name          string
+		credentials   fn.GitCredentials
+		wantWorkspace bool
+		wantItems     int
+	}{
+		{
+			name:          "no credentials - workspace not bound",
+			credentials:   fn.GitCredentials{},
+			wantWorkspace: false,
+		},
+		{
+			name:          "basic auth - secret bound without item projection",
+			credentials:   fn.GitCredentials{SecretName: "my-git-creds"},
+			wantWorkspace: true,
+			wantItems:     0,
+		},
+		{
+			name:          "ssh - private key projected to id_rsa",
+			credentials:   fn.GitCredentials{SecretName: "my-git-creds", SSHPrivateKeyKey: "ssh-privatekey"},
+			wantWorkspace: true,
+			wantItems:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fn.Function{Build: fn.BuildSpec{
+				Builder: builders.Pack,
+				Git:     fn.Git{URL: "http://git-repo/git.git", Credentials: tt.credentials},
+			}}
+			pr := generatePipelineRun(f, map[string]string{})
+
+			var found bool
+			for _, w := range pr.Spec.Workspaces {
+				if w.Name != "git-credentials-workspace" {
+					continue
+				}
+				found = true
+				if w.Secret == nil || w.Secret.SecretName != tt.credentials.SecretName {
+					t.Errorf("generatePipelineRun(), workspace does not reference secret %q", tt.credentials.SecretName)
+				}
+				if len(w.Secret.Items) != tt.wantItems {
+					t.Errorf("generatePipelineRun(), wanted %v projected items, got %v", tt.wantItems, len(w.Secret.Items))
+				}
+			}
+			if found != tt.wantWorkspace {
+				t.Errorf("generatePipelineRun(), wanted workspace bound = %v, got = %v", tt.wantWorkspace, found)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	pplnv1beta1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+
+	fn "knative.dev/kn-plugin-func"
 )
 
 const (
@@ -12,16 +14,31 @@ const (
 	taskNameDeploy       = "deploy"
 )
 
-func taskFetchSources() pplnv1beta1.PipelineTask {
+func taskFetchSources(f fn.Function) pplnv1beta1.PipelineTask {
+	workspaces := []pplnv1beta1.WorkspacePipelineTaskBinding{{
+		Name:      "output",
+		Workspace: "source-workspace",
+	}}
+
+	// Private repositories: expose the referenced credentials secret to the
+	// git-clone task as its ssh or basic-auth workspace.
+	if f.Build.Git.Credentials.SecretName != "" {
+		name := "basic-auth"
+		if f.Build.Git.Credentials.SSHPrivateKeyKey != "" {
+			name = "ssh-directory"
+		}
+		workspaces = append(workspaces, pplnv1beta1.WorkspacePipelineTaskBinding{
+			Name:      name,
+			Workspace: "git-credentials-workspace",
+		})
+	}
+
 	return pplnv1beta1.PipelineTask{
 		Name: taskNameFetchSources,
 		TaskRef: &pplnv1beta1.TaskRef{
 			Name: "git-clone",
 		},
-		Workspaces: []pplnv1beta1.WorkspacePipelineTaskBinding{{
-			Name:      "output",
-			Workspace: "source-workspace",
-		}},
+		Workspaces: workspaces,
 		Params: []pplnv1beta1.Param{
 			{Name: "url", Value: *pplnv1beta1.NewArrayOrString("$(params.gitRepository)")},
 			{Name: "revision", Value: *pplnv1beta1.NewArrayOrString("$(params.gitRevision)")},
@@ -1,11 +1,13 @@
 package tekton
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
 	fn "knative.dev/kn-plugin-func"
 	"knative.dev/kn-plugin-func/builders"
+	"knative.dev/kn-plugin-func/k8s"
 	"knative.dev/kn-plugin-func/s2i"
 )
 
@@ -46,3 +48,26 @@ func validatePipeline(f fn.Function) error {
 
 	return nil
 }
+
+// validateGitCredentials ensures that, when the function references a
+// credentials secret for its git repository, the secret exists in the
+// target namespace and contains any explicitly configured keys.
+func validateGitCredentials(ctx context.Context, f fn.Function, namespace string) error {
+	creds := f.Build.Git.Credentials
+	if creds.SecretName == "" {
+		return nil
+	}
+	secret, err := k8s.GetSecret(ctx, creds.SecretName, namespace)
+	if err != nil {
+		return fmt.Errorf("git credentials secret %q not found: %v", creds.SecretName, err)
+	}
+	for _, key := range []string{creds.UsernameKey, creds.TokenKey, creds.SSHPrivateKeyKey} {
+		if key == "" {
+			continue
+		}
+		if _, ok := secret.Data[key]; !ok {
+			return fmt.Errorf("git credentials secret %q does not contain key %q", creds.SecretName, key)
+		}
+	}
+	return nil
+}
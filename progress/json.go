@@ -0,0 +1,81 @@
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// JSONListener is a progress listener which emits one JSON object per
+// progress update to the provided writer, suitable for machine consumption
+// such as CI log aggregators.  Each method of the ProgressListener
+// interface emits a distinct event type:
+//
+//	{"event":"setTotal","total":3}
+//	{"event":"increment","step":1,"total":3,"message":"Building"}
+//	{"event":"complete","step":3,"total":3,"message":"Done"}
+//	{"event":"stopping"}
+//	{"event":"done"}
+type JSONListener struct {
+	mu    sync.Mutex
+	out   io.Writer
+	step  int
+	total int
+}
+
+// jsonEvent is the serialized form of a single progress update.
+type jsonEvent struct {
+	Event   string `json:"event"`
+	Step    int    `json:"step,omitempty"`
+	Total   int    `json:"total,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// NewJSONListener creates a progress listener which writes structured JSON
+// events to the given writer.
+func NewJSONListener(w io.Writer) *JSONListener {
+	return &JSONListener{out: w}
+}
+
+func (l *JSONListener) SetTotal(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.total = n
+	l.emit(jsonEvent{Event: "setTotal", Total: n})
+}
+
+func (l *JSONListener) Increment(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.step++
+	l.emit(jsonEvent{Event: "increment", Step: l.step, Total: l.total, Message: message})
+}
+
+func (l *JSONListener) Complete(message string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.emit(jsonEvent{Event: "complete", Step: l.step, Total: l.total, Message: message})
+}
+
+func (l *JSONListener) Stopping() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.emit(jsonEvent{Event: "stopping"})
+}
+
+func (l *JSONListener) Done() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.emit(jsonEvent{Event: "done"})
+}
+
+// emit writes the event as a single line of JSON.  Serialization of the
+// fixed event structure cannot fail; write errors are ignored as progress
+// reporting is advisory.
+func (l *JSONListener) emit(e jsonEvent) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	l.out.Write(append(b, '\n')) //nolint:errcheck
+}
@@ -1,11 +1,16 @@
 package function
 
 import (
+	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
 )
 
 const (
@@ -22,6 +27,16 @@ const (
 	// be moved from the CLI to the core in the near future.  For now use the
 	// current working directory.
 	DefaultRepositoriesPath = ""
+
+	// DefaultRepositoryCacheTTL is the duration for which a cached clone of
+	// the single remote repository (see WithRepository) is reused before
+	// being re-fetched.
+	DefaultRepositoryCacheTTL = time.Hour
+
+	// repositoryCacheDirName is the directory under the repositories path in
+	// which cached clones of remote repositories are kept, hidden such that
+	// it is not listed as an installed repository.
+	repositoryCacheDirName = ".cache"
 )
 
 // Repositories manager
@@ -39,6 +54,16 @@ type Repositories struct {
 	// outputs.
 	remote string
 
+	// Optional path to a local directory to use as the source of default
+	// templates in place of those embedded in the binary.
+	templatesPath string
+
+	// TTL for which a cached clone of the single remote repo is reused
+	// before re-fetching (zero disables caching), and whether to bypass an
+	// unexpired cache entirely.
+	cacheTTL time.Duration
+	refresh  bool
+
 	// backreference to the client enabling this repositorires manager to
 	// have full API access.
 	client *Client
@@ -49,9 +74,12 @@ type Repositories struct {
 // full client API during implementations.
 func newRepositories(client *Client) *Repositories {
 	return &Repositories{
-		client: client,
-		path:   client.repositoriesPath,
-		remote: client.repositoriesURI,
+		client:        client,
+		path:          client.repositoriesPath,
+		remote:        client.repositoriesURI,
+		templatesPath: client.templatesPath,
+		cacheTTL:      client.repositoryTTL,
+		refresh:       client.repositoryRefresh,
 	}
 }
 
@@ -86,7 +114,7 @@ func (r *Repositories) All() (repos []Repository, err error) {
 	// Create a new repository from the remote URI, and set its name to
 	// the default so that it is treated as the default in place of the embedded.
 	if r.remote != "" {
-		if repo, err = NewRepository(DefaultRepositoryName, r.remote); err != nil {
+		if repo, err = r.remoteRepository(context.Background()); err != nil {
 			return
 		}
 		repos = []Repository{repo}
@@ -94,8 +122,9 @@ func (r *Repositories) All() (repos []Repository, err error) {
 	}
 
 	// When not in single-repo mode (above), the default repository is always
-	// first in the list
-	if repo, err = NewRepository("", ""); err != nil {
+	// first in the list.  This is the embedded repository unless a local
+	// templates path was configured (see WithTemplatesPath).
+	if repo, err = r.defaultRepository(); err != nil {
 		return
 	}
 	repos = append(repos, repo)
@@ -127,7 +156,7 @@ func (r *Repositories) All() (repos []Repository, err error) {
 		if err != nil {
 			return
 		}
-		if repo, err = NewRepository("", "file://"+filepath.ToSlash(abspath)+"/"+f.Name()); err != nil {
+		if repo, err = NewRepository(context.Background(), "", "file://"+filepath.ToSlash(abspath)+"/"+f.Name()); err != nil {
 			return
 		}
 		repos = append(repos, repo)
@@ -135,6 +164,130 @@ func (r *Repositories) All() (repos []Repository, err error) {
 	return
 }
 
+// remoteRepository returns the repository for single-repository mode (see
+// WithRepository): a cached local clone of the remote when one exists and
+// is within the configured TTL, the remote itself otherwise (caching the
+// result).  Caching requires a repositories path under which to store the
+// cache and applies only to remote URIs; local paths load directly.
+func (r *Repositories) remoteRepository(ctx context.Context) (Repository, error) {
+	if r.path == "" || r.cacheTTL <= 0 || !isRemoteRepositoryURI(r.remote) {
+		return NewRepository(ctx, DefaultRepositoryName, r.remote)
+	}
+
+	cache := filepath.Join(r.path, repositoryCacheDirName, repositoryCacheKey(r.remote))
+	if err := os.MkdirAll(filepath.Dir(cache), 0755); err != nil {
+		return Repository{}, err
+	}
+
+	// Serialize access such that concurrent creates against the same remote
+	// do not corrupt the cache.
+	unlock, err := lockRepositoryCache(cache)
+	if err != nil {
+		return Repository{}, err
+	}
+	defer unlock()
+
+	// Reuse an unexpired cached clone unless a refresh was requested.
+	if !r.refresh && !repositoryCacheStale(cache, r.cacheTTL) {
+		return NewRepository(ctx, DefaultRepositoryName, "file://"+filepath.ToSlash(cache))
+	}
+
+	// Fetch the remote, writing the clone to a temporary directory renamed
+	// into place on success such that a failed fetch never leaves a partial
+	// cache entry (see also Repositories.add).
+	repo, err := NewRepository(ctx, DefaultRepositoryName, r.remote)
+	if err != nil {
+		return Repository{}, err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(cache), ".tmp")
+	if err != nil {
+		return Repository{}, err
+	}
+	if err = repo.Write(ctx, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return Repository{}, fmt.Errorf("failed to cache repository: %w", err)
+	}
+	if err = os.RemoveAll(cache); err != nil {
+		_ = os.RemoveAll(tmp)
+		return Repository{}, err
+	}
+	if err = os.Rename(tmp, cache); err != nil {
+		_ = os.RemoveAll(tmp)
+		return Repository{}, err
+	}
+	return repo, nil
+}
+
+// isRemoteRepositoryURI returns true for URIs which require a network fetch
+// and thus benefit from caching (http and https schemes).
+func isRemoteRepositoryURI(uri string) bool {
+	return strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://")
+}
+
+// repositoryCacheKey returns the directory name under which the clone of
+// the given URI (including any #ref fragment) is cached.
+func repositoryCacheKey(uri string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(uri)))
+}
+
+// repositoryCacheStale returns true when no cache exists at path or its
+// clone is older than the given TTL.
+func repositoryCacheStale(path string, ttl time.Duration) bool {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return true
+	}
+	return time.Since(fi.ModTime()) > ttl
+}
+
+// lockRepositoryCache takes an exclusive advisory lock on the cache entry
+// at path by creating a lock file, briefly retrying while another process
+// holds it.  A lock abandoned by an interrupted process is stolen after a
+// minute.  Returned is the releasing function.
+func lockRepositoryCache(path string) (unlock func(), err error) {
+	lockfile := path + ".lock"
+	for {
+		f, err := os.OpenFile(lockfile, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { _ = os.Remove(lockfile) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if fi, serr := os.Stat(lockfile); serr == nil && time.Since(fi.ModTime()) > time.Minute {
+			_ = os.Remove(lockfile) // steal the abandoned lock
+			continue
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// defaultRepository returns the default repository:  the embedded templates
+// or, when a templates path override is configured, a repository loaded
+// from that local directory (validated to exist and contain at least one
+// runtime).
+func (r *Repositories) defaultRepository() (Repository, error) {
+	if r.templatesPath == "" {
+		return NewRepository(context.Background(), "", "")
+	}
+	abspath, err := filepath.Abs(r.templatesPath)
+	if err != nil {
+		return Repository{}, err
+	}
+	if _, err = os.Stat(abspath); err != nil {
+		return Repository{}, fmt.Errorf("templates path '%v' does not exist", r.templatesPath)
+	}
+	repo, err := NewRepository(context.Background(), DefaultRepositoryName, "file://"+filepath.ToSlash(abspath))
+	if err != nil {
+		return repo, err
+	}
+	if len(repo.Runtimes) == 0 {
+		return repo, fmt.Errorf("templates path '%v' contains no runtimes", r.templatesPath)
+	}
+	return repo, nil
+}
+
 // Get a repository by name, error if it does not exist.
 func (r *Repositories) Get(name string) (repo Repository, err error) {
 	all, err := r.All()
@@ -165,34 +318,74 @@ func (r *Repositories) Get(name string) (repo Repository, err error) {
 
 // Add a repository of the given name from the URI.  Name, if not provided,
 // defaults to the repo name (sans optional .git suffix). Returns the final
-// name as added.
-func (r *Repositories) Add(name, uri string) (string, error) {
+// name as added.  The context governs the clone; cancellation or deadline
+// expiry aborts the operation.  Errors if a valid repository of the name
+// already exists; the remnant of an interrupted earlier clone is replaced.
+func (r *Repositories) Add(ctx context.Context, name, uri string) (string, error) {
+	return r.add(ctx, name, uri, false)
+}
+
+// Overwrite adds the repository of the given name from the URI as Add,
+// replacing any repository of the name which already exists.
+func (r *Repositories) Overwrite(ctx context.Context, name, uri string) (string, error) {
+	return r.add(ctx, name, uri, true)
+}
+
+func (r *Repositories) add(ctx context.Context, name, uri string, overwrite bool) (string, error) {
 	if r.path == "" {
 		return "", fmt.Errorf("repository %v(%v) not added. "+
 			"No repositories path provided", name, uri)
 	}
 
 	// Create a repo (in-memory FS) from the URI
-	repo, err := NewRepository(name, uri)
+	repo, err := NewRepository(ctx, name, uri)
 	if err != nil {
 		return "", fmt.Errorf("failed to create new repository: %w", err)
 	}
 
-	// Error if the repository already exists on disk
+	// Error if a valid repository of the name already exists on disk.  An
+	// existing directory which does not load as a repository is the remnant
+	// of an interrupted clone and is replaced.
 	dest := filepath.Join(r.path, repo.Name)
 	if _, err := os.Stat(dest); !os.IsNotExist(err) {
-		return "", fmt.Errorf("repository '%v' already exists", repo.Name)
+		if !overwrite && !isIncompleteRepository(dest) {
+			return "", fmt.Errorf("repository '%v' already exists", repo.Name)
+		}
+		if err = os.RemoveAll(dest); err != nil {
+			return "", fmt.Errorf("failed to remove existing repository: %w", err)
+		}
 	}
 
-	// Instruct the repository to write itself to disk at the given path.
-	// Fails if path exists.
-	err = repo.Write(dest)
+	// Instruct the repository to write itself to a temporary directory
+	// alongside the final destination, renaming into place on success, such
+	// that a failed or interrupted clone never leaves a partial repository
+	// under the repositories path.
+	tmp, err := os.MkdirTemp(r.path, "."+repo.Name)
 	if err != nil {
+		return "", err
+	}
+	if err = repo.Write(ctx, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", fmt.Errorf("failed to write repository: %w", err)
+	}
+	if err = os.Rename(tmp, dest); err != nil {
+		_ = os.RemoveAll(tmp)
 		return "", fmt.Errorf("failed to write repository: %w", err)
 	}
 	return repo.Name, nil
 }
 
+// isIncompleteRepository returns true when the directory at the given path
+// does not load as a repository containing at least one runtime, indicating
+// the remnant of an interrupted clone rather than a usable installation.
+func isIncompleteRepository(path string) bool {
+	repo, err := NewRepository(context.Background(), "", "file://"+filepath.ToSlash(path))
+	if err != nil {
+		return true
+	}
+	return len(repo.Runtimes) == 0
+}
+
 // Rename a repository
 func (r *Repositories) Rename(from, to string) error {
 	if r.path == "" {
@@ -217,3 +410,142 @@ func (r *Repositories) Remove(name string) error {
 	path := filepath.Join(r.path, name)
 	return os.RemoveAll(path)
 }
+
+// Update the named installed repository to the latest commit of its tracked
+// remote reference, returning whether new commits were received.  Local
+// uncommitted modifications cause an error unless force, in which case they
+// are discarded.  The context governs the fetch.
+func (r *Repositories) Update(ctx context.Context, name string, force bool) (changed bool, err error) {
+	if r.path == "" {
+		return false, errors.New("no repositories path provided")
+	}
+	if name == "" {
+		return false, errors.New("name is required")
+	}
+	abspath, err := filepath.Abs(filepath.Join(r.path, name))
+	if err != nil {
+		return
+	}
+	if _, err = os.Stat(abspath); os.IsNotExist(err) {
+		return false, fmt.Errorf("repository '%v' is not installed", name)
+	}
+
+	repo, err := git.PlainOpen(abspath)
+	if err != nil {
+		return false, fmt.Errorf("repository '%v' is not a git repository: %w", name, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return
+	}
+
+	// Refuse to silently discard local edits.
+	status, err := wt.Status()
+	if err != nil {
+		return
+	}
+	if !status.IsClean() {
+		if !force {
+			return false, fmt.Errorf("repository '%v' has uncommitted local modifications; commit or use force to discard them", name)
+		}
+		if err = wt.Reset(&git.ResetOptions{Mode: git.HardReset}); err != nil {
+			return false, fmt.Errorf("failed to discard local modifications: %w", err)
+		}
+		if err = wt.Clean(&git.CleanOptions{Dir: true}); err != nil {
+			return false, fmt.Errorf("failed to discard untracked files: %w", err)
+		}
+	}
+
+	// Compare the local HEAD with the remote's advertised reference first:
+	// an already-current repository requires no fetch (and not all servers
+	// tolerate an empty fetch negotiation).
+	head, err := repo.Head()
+	if err != nil {
+		return
+	}
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return false, fmt.Errorf("repository '%v' has no tracked remote: %w", name, err)
+	}
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to query the remote of repository '%v': %w", name, err)
+	}
+	for _, ref := range refs {
+		if ref.Name() == head.Name() && ref.Hash() == head.Hash() {
+			return false, nil
+		}
+	}
+
+	err = wt.PullContext(ctx, &git.PullOptions{})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to update repository '%v': %w", name, err)
+	}
+	return true, nil
+}
+
+// Prune removes installed repositories which no longer load as valid
+// repositories (for example those whose directories have been partially
+// deleted or whose manifests have become unparseable), returning the names
+// of the directories pruned.
+func (r *Repositories) Prune() (pruned []string, err error) {
+	pruned = []string{}
+	if r.path == "" {
+		return pruned, errors.New("no repositories path provided")
+	}
+	if _, err = os.Stat(r.path); os.IsNotExist(err) {
+		return pruned, nil // nothing installed; nothing to prune
+	}
+	ff, err := os.ReadDir(r.path)
+	if err != nil {
+		return
+	}
+	for _, f := range ff {
+		if !f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		if r.Verify(f.Name()) == nil {
+			continue
+		}
+		if err = os.RemoveAll(filepath.Join(r.path, f.Name())); err != nil {
+			return
+		}
+		pruned = append(pruned, f.Name())
+	}
+	return
+}
+
+// Verify that the named installed repository still resolves:  it must load
+// as a repository and contain at least one runtime with at least one
+// template.
+func (r *Repositories) Verify(name string) error {
+	if r.path == "" {
+		return errors.New("no repositories path provided")
+	}
+	if name == "" {
+		return errors.New("name is required")
+	}
+	abspath, err := filepath.Abs(filepath.Join(r.path, name))
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(abspath); os.IsNotExist(err) {
+		return fmt.Errorf("repository '%v' is not installed", name)
+	}
+	repo, err := NewRepository(context.Background(), "", "file://"+filepath.ToSlash(abspath))
+	if err != nil {
+		return fmt.Errorf("repository '%v' failed to load: %w", name, err)
+	}
+	if len(repo.Runtimes) == 0 {
+		return fmt.Errorf("repository '%v' contains no runtimes", name)
+	}
+	for _, runtime := range repo.Runtimes {
+		if len(runtime.Templates) > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("repository '%v' contains no templates", name)
+}
@@ -4,6 +4,7 @@
 package function_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -85,7 +86,7 @@ func TestRepositories_All(t *testing.T) {
 	}
 
 	// Add one
-	_, err = client.Repositories().Add("", uri)
+	_, err = client.Repositories().Add(context.Background(), "", uri)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -116,7 +117,7 @@ func TestRepositories_Add(t *testing.T) {
 
 	// Add the repository, explicitly specifying a name.  See other tests for
 	// defaulting from repository names and manifest-defined name.
-	if _, err := client.Repositories().Add("example", uri); err != nil {
+	if _, err := client.Repositories().Add(context.Background(), "example", uri); err != nil {
 		t.Fatal(err)
 	}
 
@@ -150,7 +151,7 @@ func TestRepositories_AddDeafultName(t *testing.T) {
 
 	client := fn.New(fn.WithRepositoriesPath(root))
 
-	name, err := client.Repositories().Add("", uri)
+	name, err := client.Repositories().Add(context.Background(), "", uri)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -185,7 +186,7 @@ func TestRepositories_AddWithManifest(t *testing.T) {
 
 	client := fn.New(fn.WithRepositoriesPath(root))
 
-	name, err := client.Repositories().Add("", uri)
+	name, err := client.Repositories().Add(context.Background(), "", uri)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -220,10 +221,10 @@ func TestRepositories_AddExistingErrors(t *testing.T) {
 
 	// Add twice.
 	name := "example"
-	if _, err := client.Repositories().Add(name, uri); err != nil {
+	if _, err := client.Repositories().Add(context.Background(), name, uri); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := client.Repositories().Add(name, uri); err == nil {
+	if _, err := client.Repositories().Add(context.Background(), name, uri); err == nil {
 		t.Fatalf("did not receive expected error adding an existing repository")
 	}
 
@@ -253,7 +254,7 @@ func TestRepositories_Rename(t *testing.T) {
 	client := fn.New(fn.WithRepositoriesPath(root))
 
 	// Add and Rename
-	if _, err := client.Repositories().Add("foo", uri); err != nil {
+	if _, err := client.Repositories().Add(context.Background(), "foo", uri); err != nil {
 		t.Fatal(err)
 	}
 	if err := client.Repositories().Rename("foo", "bar"); err != nil {
@@ -288,7 +289,7 @@ func TestRepositories_Remove(t *testing.T) {
 
 	// Add and Remove
 	name := "example"
-	if _, err := client.Repositories().Add(name, uri); err != nil {
+	if _, err := client.Repositories().Add(context.Background(), name, uri); err != nil {
 		t.Fatal(err)
 	}
 	if err := client.Repositories().Remove(name); err != nil {
@@ -320,7 +321,7 @@ func TestRepositories_URL(t *testing.T) {
 	client := fn.New(fn.WithRepositoriesPath(root))
 
 	// Add the test repo
-	_, err := client.Repositories().Add("newrepo", uri)
+	_, err := client.Repositories().Add(context.Background(), "newrepo", uri)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -353,3 +354,240 @@ func TestRepositories_Missing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestRepositories_Verify ensures that installed repositories are verified
+// to still resolve:  a healthy repository passes, a missing or gutted
+// repository fails.
+func TestRepositories_Verify(t *testing.T) {
+	uri := TestRepoURI(RepositoriesTestRepo, t) // ./testdata/repository.git
+	root, rm := Mktemp(t)                       // create and cd to a temp dir
+	defer rm()
+
+	client := fn.New(fn.WithRepositoriesPath(root))
+
+	name := "example"
+	if _, err := client.Repositories().Add(context.Background(), name, uri); err != nil {
+		t.Fatal(err)
+	}
+
+	// A healthy installed repository verifies without error
+	if err := client.Repositories().Verify(name); err != nil {
+		t.Fatal(err)
+	}
+
+	// A repository which is not installed fails verification
+	if err := client.Repositories().Verify("nonexistent"); err == nil {
+		t.Fatal("expected error verifying a repository which is not installed")
+	}
+
+	// A repository whose runtimes have been removed fails verification
+	ff, err := os.ReadDir(filepath.Join(root, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range ff {
+		if f.IsDir() {
+			if err = os.RemoveAll(filepath.Join(root, name, f.Name())); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := client.Repositories().Verify(name); err == nil {
+		t.Fatal("expected error verifying a repository with no runtimes")
+	}
+}
+
+// TestRepositories_Prune ensures that repositories which no longer verify
+// are removed from disk, with their names returned, and that healthy
+// repositories are retained.
+func TestRepositories_Prune(t *testing.T) {
+	uri := TestRepoURI(RepositoriesTestRepo, t) // ./testdata/repository.git
+	root, rm := Mktemp(t)                       // create and cd to a temp dir
+	defer rm()
+
+	client := fn.New(fn.WithRepositoriesPath(root))
+
+	// A healthy repository and a broken (empty directory) repository
+	if _, err := client.Repositories().Add(context.Background(), "healthy", uri); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "broken"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := client.Repositories().Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) != 1 || pruned[0] != "broken" {
+		t.Fatalf("expected only 'broken' to be pruned, got %v", pruned)
+	}
+
+	// The broken repository is gone; the healthy repository remains
+	if _, err := os.Stat(filepath.Join(root, "broken")); !os.IsNotExist(err) {
+		t.Fatal("pruned repository still exists on filesystem")
+	}
+	if err := client.Repositories().Verify("healthy"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRepositories_AddIncompleteRetry ensures that the remnant of an
+// interrupted earlier clone (a directory which does not load as a valid
+// repository) is replaced upon a subsequent add rather than reported as
+// already existing.
+func TestRepositories_AddIncompleteRetry(t *testing.T) {
+	uri := TestRepoURI(RepositoriesTestRepo, t)
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRepositoriesPath(root))
+
+	// Simulate a failed clone: a partial directory of the name a prior,
+	// interrupted add would have left behind.
+	partial := filepath.Join(root, RepositoriesTestRepo)
+	if err := os.MkdirAll(partial, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(partial, "README.md"), []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Retrying the add should replace the partial clone
+	if _, err := client.Repositories().Add(context.Background(), "", uri); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := client.Repositories().Get(RepositoriesTestRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.Runtimes) == 0 {
+		t.Fatal("the repository was not replaced with a complete clone")
+	}
+}
+
+// TestRepositories_Overwrite ensures that adding with overwrite replaces a
+// valid repository of the same name rather than erroring.
+func TestRepositories_Overwrite(t *testing.T) {
+	uri := TestRepoURI(RepositoriesTestRepo, t)
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRepositoriesPath(root))
+
+	name := "example"
+	if _, err := client.Repositories().Add(context.Background(), name, uri); err != nil {
+		t.Fatal(err)
+	}
+
+	// A plain add of the existing, valid repository still errors
+	if _, err := client.Repositories().Add(context.Background(), name, uri); err == nil {
+		t.Fatal("did not receive expected error adding an existing repository")
+	}
+
+	// Overwrite replaces it
+	if _, err := client.Repositories().Overwrite(context.Background(), name, uri); err != nil {
+		t.Fatal(err)
+	}
+	rr, err := client.Repositories().List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr) != 2 || rr[1] != name {
+		t.Fatalf("Expected '[default %v]', got %v", name, rr)
+	}
+}
+
+// TestRepositories_RemoteCache ensures that single-repository mode caches
+// remote clones under the repositories path, that the cache can be bypassed
+// with a refresh, and that a zero TTL disables caching entirely.
+func TestRepositories_RemoteCache(t *testing.T) {
+	uri := TestRepoURI(RepositoriesTestRepo, t) // served over http
+	root, rm := Mktemp(t)
+	defer rm()
+
+	// Loading the remote repo caches the clone under the repositories path
+	client := fn.New(fn.WithRepositoriesPath(root), fn.WithRepository(uri))
+	rr, err := client.Repositories().All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rr) != 1 || rr[0].Name != fn.DefaultRepositoryName {
+		t.Fatalf("expected only the single remote repo as default, got %v", rr)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, ".cache"))
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("expected a cache entry under the repositories path: %v", err)
+	}
+
+	// A subsequent load (fresh cache) and a forced refresh both succeed
+	if _, err = client.Repositories().All(); err != nil {
+		t.Fatal(err)
+	}
+	client = fn.New(fn.WithRepositoriesPath(root), fn.WithRepository(uri),
+		fn.WithRepositoryRefresh(true))
+	if _, err = client.Repositories().All(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A zero TTL disables caching
+	root2 := t.TempDir()
+	client = fn.New(fn.WithRepositoriesPath(root2), fn.WithRepository(uri),
+		fn.WithRepositoryCacheTTL(0))
+	if _, err = client.Repositories().All(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root2, ".cache")); !os.IsNotExist(err) {
+		t.Fatal("expected no cache to be written when the TTL is zero")
+	}
+}
+
+// TestRepositories_Update ensures that an installed repository can be updated
+// in place: an up-to-date repository reports no change, uncommitted local
+// modifications are refused unless forced, and forcing discards them.
+func TestRepositories_Update(t *testing.T) {
+	uri := TestRepoURI(RepositoriesTestRepo, t)
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRepositoriesPath(root))
+
+	if _, err := client.Repositories().Add(context.Background(), "example", uri); err != nil {
+		t.Fatal(err)
+	}
+
+	// A freshly-installed repository is already current.
+	changed, err := client.Repositories().Update(context.Background(), "example", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Fatal("expected a freshly-installed repository to report no change")
+	}
+
+	// Local modifications are refused without force...
+	readme := filepath.Join(root, "example", "README.md")
+	if err := os.WriteFile(readme, []byte("local edit"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Repositories().Update(context.Background(), "example", false); err == nil {
+		t.Fatal("expected an error updating a repository with local modifications")
+	}
+
+	// ...and discarded with.
+	if _, err = client.Repositories().Update(context.Background(), "example", true); err != nil {
+		t.Fatal(err)
+	}
+	bb, err := os.ReadFile(readme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) == "local edit" {
+		t.Fatal("expected forced update to discard local modifications")
+	}
+
+	// A repository which is not installed errors.
+	if _, err = client.Repositories().Update(context.Background(), "nonexistent", false); err == nil {
+		t.Fatal("expected an error updating a repository which is not installed")
+	}
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/go-git/go-billy/v5/memfs"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"gopkg.in/yaml.v2"
 )
@@ -80,6 +81,10 @@ type Template interface {
 	Fullname() string
 	// Write updates fields of function f and writes project files to path pointed by f.Root.
 	Write(ctx context.Context, f *Function) error
+	// PostCreate is an optional command, declared by the template's manifest,
+	// to be run in the new project directory after its files have been
+	// written.  Empty when the template declares none.
+	PostCreate() string
 }
 
 // This structure defines defaults for a function when generating project by a template.Write().
@@ -100,6 +105,12 @@ type funcDefaults struct {
 	// Invocation defines invocation hints for a functions which is created
 	// from this template prior to being materially modified.
 	Invocation Invocation `yaml:"invocation,omitempty"`
+
+	// PostCreate is an optional shell command run in the new project
+	// directory after the template's files have been written; for example
+	// an installation of dependencies.  Execution is gated by the client
+	// option WithRunPostCreate.
+	PostCreate string `yaml:"postCreate,omitempty"`
 }
 
 type repositoryConfig struct {
@@ -122,15 +133,21 @@ type templateConfig = funcDefaults
 // NewRepository creates a repository instance from any of: a path on disk, a
 // remote or local URI, or from the embedded default repo if uri not provided.
 // Name (optional), if provided takes precedence over name derived from repo at
-//   the given URI.
+//
+//	the given URI.
+//
 // URI (optional), the path either locally or remote from which to load
-//    the repository files.  If not provided, the internal default is assumed.
-func NewRepository(name, uri string) (r Repository, err error) {
+//
+//	the repository files.  If not provided, the internal default is assumed.
+//
+// The context governs any remote fetch; cancellation or deadline expiry
+// aborts the clone.
+func NewRepository(ctx context.Context, name, uri string) (r Repository, err error) {
 	r = Repository{
 		uri: uri,
 	}
 
-	fs, err := filesystemFromURI(uri) // Get a Filesystem from the URI
+	fs, err := filesystemFromURI(ctx, uri) // Get a Filesystem from the URI
 	if err != nil {
 		return Repository{}, fmt.Errorf("failed to get repository from URI (%q): %w", uri, err)
 	}
@@ -178,7 +195,7 @@ func NewRepository(name, uri string) (r Repository, err error) {
 // given URI.  If URI is not provided, indicates the embedded repo should
 // be loaded.  URI can be a remote git repository (http:// https:// etc.),
 // or a local file path (file://) which can be a git repo or a plain directory.
-func filesystemFromURI(uri string) (f Filesystem, err error) {
+func filesystemFromURI(ctx context.Context, uri string) (f Filesystem, err error) {
 	// If not provided, indicates embedded.
 	if uri == "" {
 		return EmbeddedTemplatesFS, nil
@@ -189,7 +206,7 @@ func filesystemFromURI(uri string) (f Filesystem, err error) {
 	}
 
 	// Attempt to get a filesystem from the uri as a remote repo.
-	f, err = filesystemFromRepo(uri)
+	f, err = filesystemFromRepo(ctx, uri)
 	if f != nil || err != nil {
 		return // found a filesystem and/or an error
 	}
@@ -215,27 +232,85 @@ func isNonBareGitRepo(uri string) bool {
 }
 
 // filesystemFromRepo attempts to fetch a filesystem from a git repository
-// indicated by the given URI.  Returns nil if there is not a repo at the URI.
-func filesystemFromRepo(uri string) (Filesystem, error) {
-	clone, err := git.Clone(
+// indicated by the given URI.  The URI may include an optional fragment
+// (uri#ref) pinning the repository to a specific branch, tag or commit.
+// Returns nil if there is not a repo at the URI.
+func filesystemFromRepo(ctx context.Context, uri string) (Filesystem, error) {
+	uri, ref := splitRepositoryRef(uri)
+	clone, err := git.CloneContext(
+		ctx,
 		memory.NewStorage(),
 		memfs.New(),
-		&git.CloneOptions{URL: uri, Depth: 1, Tags: git.NoTags,
-			RecurseSubmodules: git.NoRecurseSubmodules,
-		})
+		repositoryCloneOptions(uri, ref))
 	if err != nil {
 		if isRepoNotFoundError(err) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to clone repository: %w", err)
+		return nil, cloneError(ctx, uri, err)
 	}
 	wt, err := clone.Worktree()
 	if err != nil {
 		return nil, err
 	}
+	if ref != "" {
+		if err = checkoutRef(clone, wt, ref); err != nil {
+			return nil, err
+		}
+	}
 	return billyFilesystem{fs: wt.Filesystem}, nil
 }
 
+// cloneError returns the error with which a failed clone should be reported:
+// a clear timeout or cancellation error when the context has expired, and the
+// underlying clone error otherwise.
+func cloneError(ctx context.Context, uri string, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out cloning repository '%v'", uri)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("cloning repository '%v' was canceled", uri)
+	}
+	return fmt.Errorf("failed to clone repository: %w", err)
+}
+
+// splitRepositoryRef splits an optional git ref fragment from a repository
+// URI of the form "uri#ref" (e.g. "https://example.com/repo.git#v2.1.0").
+// Returned is the URI sans fragment and the ref (empty if none).
+func splitRepositoryRef(uri string) (string, string) {
+	if i := strings.LastIndex(uri, "#"); i >= 0 {
+		return uri[:i], uri[i+1:]
+	}
+	return uri, ""
+}
+
+// repositoryCloneOptions returns the clone options to use for the given
+// repository URI.  When no ref is requested a shallow clone of the default
+// branch suffices; a pinned ref requires a full clone such that the ref
+// (branch, tag or commit) is available for checkout.
+func repositoryCloneOptions(uri, ref string) *git.CloneOptions {
+	if ref == "" {
+		return &git.CloneOptions{URL: uri, Depth: 1, Tags: git.NoTags,
+			RecurseSubmodules: git.NoRecurseSubmodules}
+	}
+	return &git.CloneOptions{URL: uri,
+		RecurseSubmodules: git.NoRecurseSubmodules}
+}
+
+// checkoutRef checks out the given ref (branch, tag or commit hash) in the
+// given worktree, erroring if the ref does not exist in the repository.
+func checkoutRef(repo *git.Repository, wt *git.Worktree, ref string) error {
+	h, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		// Branches other than the default exist in the clone only as
+		// remote-tracking refs.
+		h, err = repo.ResolveRevision(plumbing.Revision("refs/remotes/origin/" + ref))
+	}
+	if err != nil {
+		return fmt.Errorf("reference %q not found in repository: %w", ref, err)
+	}
+	return wt.Checkout(&git.CheckoutOptions{Hash: *h})
+}
+
 // isRepoNotFoundError returns true if the error is a
 // "repository not found" error.
 func isRepoNotFoundError(err error) bool {
@@ -468,8 +543,9 @@ func (r *Repository) Runtime(name string) (runtime Runtime, err error) {
 	return Runtime{}, ErrRuntimeNotFound
 }
 
-// Write all files in the repository to the given path.
-func (r *Repository) Write(path string) (err error) {
+// Write all files in the repository to the given path.  The context governs
+// the re-clone performed for in-memory (remote) repositories.
+func (r *Repository) Write(ctx context.Context, path string) (err error) {
 	if r.fs == nil {
 		return errors.New("the write operation is not supported on this repo")
 	}
@@ -495,14 +571,19 @@ func (r *Repository) Write(path string) (err error) {
 		if tempDir, err = ioutil.TempDir("", "func"); err != nil {
 			return
 		}
-		if clone, err = git.PlainClone(tempDir, false, // not bare
-			&git.CloneOptions{URL: r.uri, Depth: 1, Tags: git.NoTags,
-				RecurseSubmodules: git.NoRecurseSubmodules}); err != nil {
-			return fmt.Errorf("failed to plain clone repository: %w", err)
+		uri, ref := splitRepositoryRef(r.uri)
+		if clone, err = git.PlainCloneContext(ctx, tempDir, false, // not bare
+			repositoryCloneOptions(uri, ref)); err != nil {
+			return cloneError(ctx, uri, err)
 		}
 		if wt, err = clone.Worktree(); err != nil {
 			return fmt.Errorf("failed to get worktree: %w", err)
 		}
+		if ref != "" {
+			if err = checkoutRef(clone, wt, ref); err != nil {
+				return
+			}
+		}
 		fs = billyFilesystem{fs: wt.Filesystem}
 	}
 	return copyFromFS(".", path, fs)
@@ -5,11 +5,13 @@ package function_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	fn "knative.dev/kn-plugin-func"
+	. "knative.dev/kn-plugin-func/testing"
 )
 
 // TestRepository_TemplatesPath ensures that repositories can specify
@@ -131,3 +133,26 @@ func TestRepository_Inheritance(t *testing.T) {
 		t.Fatalf("Template-level Buildpack differs (-want, +got): %s", diff)
 	}
 }
+
+// TestRepository_Ref ensures that a repository URI with a "#ref" fragment
+// is pinned to the given branch, tag or commit, and that an invalid ref
+// yields a clear error rather than silently using the default branch HEAD.
+func TestRepository_Ref(t *testing.T) {
+	uri := TestRepoURI(RepositoriesTestRepo, t)
+
+	// A valid ref (here a branch) should clone and load successfully.
+	repo, err := fn.NewRepository(context.Background(), "", uri+"#main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.Runtimes) == 0 {
+		t.Fatal("no runtimes loaded from ref-pinned repository")
+	}
+
+	// An invalid ref should produce an error naming the ref.
+	if _, err = fn.NewRepository(context.Background(), "", uri+"#nonexistent"); err == nil {
+		t.Fatal("expected error for invalid ref")
+	} else if !strings.Contains(err.Error(), "nonexistent") {
+		t.Fatalf("error does not name the invalid ref: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+package route53
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+const (
+	// DefaultEndpoint of the Route 53 API.  Route 53 is a global service;
+	// requests are signed for the us-east-1 region regardless of where the
+	// function itself is deployed.
+	DefaultEndpoint = "https://route53.amazonaws.com"
+
+	// DefaultTTL of records created for functions, in seconds.  Short
+	// enough that a re-provisioned ingress propagates quickly.
+	DefaultTTL = 300
+
+	apiVersion = "2013-04-01"
+)
+
+// DNSProvider creates or updates CNAME records in an AWS Route 53 hosted
+// zone, pointing subdomains allocated to functions at the cluster ingress
+// load balancer.  This automates the final, otherwise manual, step of
+// serving a function on a custom domain (see Client.Route).
+//
+// Credentials are resolved from the standard AWS chain: the environment
+// (AWS_ACCESS_KEY_ID et al.) followed by the shared credentials file
+// (~/.aws/credentials, profile from AWS_PROFILE or 'default').
+type DNSProvider struct {
+	zoneID   string
+	target   string
+	ttl      int64
+	endpoint string
+	verbose  bool
+	client   *http.Client
+	creds    *credentials // overrides the standard chain when set
+}
+
+// Option defines a function which when passed to NewDNSProvider may alter
+// its default settings.
+type Option func(*DNSProvider)
+
+// WithVerbose toggles verbose logging.
+func WithVerbose(verbose bool) Option {
+	return func(p *DNSProvider) {
+		p.verbose = verbose
+	}
+}
+
+// WithTTL sets the TTL, in seconds, of records created or updated.
+func WithTTL(ttl int64) Option {
+	return func(p *DNSProvider) {
+		p.ttl = ttl
+	}
+}
+
+// WithEndpoint overrides the Route 53 API endpoint; used for testing and
+// for API-compatible stand-ins such as localstack.
+func WithEndpoint(endpoint string) Option {
+	return func(p *DNSProvider) {
+		p.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithStaticCredentials bypasses the standard AWS credential chain, using
+// the given access key pair (and optional session token) directly.
+func WithStaticCredentials(accessKeyID, secretAccessKey, sessionToken string) Option {
+	return func(p *DNSProvider) {
+		p.creds = &credentials{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    sessionToken,
+		}
+	}
+}
+
+// NewDNSProvider creates a provider which manages CNAME records for
+// functions in the given hosted zone, directing them at target (the
+// hostname of the cluster ingress load balancer).
+func NewDNSProvider(zoneID, target string, options ...Option) *DNSProvider {
+	p := &DNSProvider{
+		zoneID:   zoneID,
+		target:   target,
+		ttl:      DefaultTTL,
+		endpoint: DefaultEndpoint,
+		client:   http.DefaultClient,
+	}
+	for _, o := range options {
+		o(p)
+	}
+	return p
+}
+
+// Provide a CNAME for the given function in the hosted zone, pointing at
+// the configured target.  The record name is the function's name as a
+// subdomain of the zone.  The operation is idempotent: an existing record
+// is updated in place (UPSERT), and providing twice with the same settings
+// is a no-op as far as DNS is concerned.
+func (p *DNSProvider) Provide(f fn.Function) error {
+	ctx := context.Background()
+
+	zoneName, err := p.zoneName(ctx)
+	if err != nil {
+		return err
+	}
+	record := f.Name + "." + zoneName // already dot-terminated per the API
+
+	// Determine beforehand whether the record exists such that the
+	// operation performed (create vs update) can be reported.
+	exists, err := p.recordExists(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	if err = p.upsert(ctx, record); err != nil {
+		return err
+	}
+
+	if p.verbose {
+		if exists {
+			fmt.Printf("Updated CNAME %v -> %v\n", record, p.target)
+		} else {
+			fmt.Printf("Created CNAME %v -> %v\n", record, p.target)
+		}
+	}
+	return nil
+}
+
+// zoneName returns the domain name of the configured hosted zone,
+// dot-terminated as reported by the API (e.g. "example.com.").
+func (p *DNSProvider) zoneName(ctx context.Context) (string, error) {
+	var response struct {
+		HostedZone struct {
+			Name string `xml:"Name"`
+		} `xml:"HostedZone"`
+	}
+	path := fmt.Sprintf("/%v/hostedzone/%v", apiVersion, p.zoneID)
+	if err := p.do(ctx, http.MethodGet, path, nil, nil, &response); err != nil {
+		return "", fmt.Errorf("dns provider failed to get hosted zone %q: %w", p.zoneID, err)
+	}
+	return response.HostedZone.Name, nil
+}
+
+// recordExists returns whether a CNAME of the given (dot-terminated) name
+// already exists in the zone.
+func (p *DNSProvider) recordExists(ctx context.Context, record string) (bool, error) {
+	var response struct {
+		ResourceRecordSets []struct {
+			Name string `xml:"Name"`
+			Type string `xml:"Type"`
+		} `xml:"ResourceRecordSets>ResourceRecordSet"`
+	}
+	path := fmt.Sprintf("/%v/hostedzone/%v/rrset", apiVersion, p.zoneID)
+	query := url.Values{
+		"name":     {record},
+		"type":     {"CNAME"},
+		"maxitems": {"1"},
+	}
+	if err := p.do(ctx, http.MethodGet, path, query, nil, &response); err != nil {
+		return false, fmt.Errorf("dns provider failed to list records: %w", err)
+	}
+	for _, rrset := range response.ResourceRecordSets {
+		if rrset.Name == record && rrset.Type == "CNAME" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// upsert creates or updates the CNAME of the given name, pointing it at
+// the configured target.
+func (p *DNSProvider) upsert(ctx context.Context, record string) error {
+	type resourceRecord struct {
+		Value string `xml:"Value"`
+	}
+	type change struct {
+		Action            string `xml:"Action"`
+		ResourceRecordSet struct {
+			Name            string           `xml:"Name"`
+			Type            string           `xml:"Type"`
+			TTL             int64            `xml:"TTL"`
+			ResourceRecords []resourceRecord `xml:"ResourceRecords>ResourceRecord"`
+		} `xml:"ResourceRecordSet"`
+	}
+	var request struct {
+		XMLName xml.Name `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+		Changes []change `xml:"ChangeBatch>Changes>Change"`
+	}
+	c := change{Action: "UPSERT"}
+	c.ResourceRecordSet.Name = record
+	c.ResourceRecordSet.Type = "CNAME"
+	c.ResourceRecordSet.TTL = p.ttl
+	c.ResourceRecordSet.ResourceRecords = []resourceRecord{{Value: p.target}}
+	request.Changes = []change{c}
+
+	body, err := xml.Marshal(request)
+	if err != nil {
+		return err
+	}
+	body = append([]byte(xml.Header), body...)
+
+	path := fmt.Sprintf("/%v/hostedzone/%v/rrset", apiVersion, p.zoneID)
+	if err := p.do(ctx, http.MethodPost, path, nil, body, nil); err != nil {
+		return fmt.Errorf("dns provider failed to change record %q: %w", record, err)
+	}
+	return nil
+}
+
+// do performs a signed request against the Route 53 API, unmarshaling the
+// XML response into out when provided.
+func (p *DNSProvider) do(ctx context.Context, method, path string, query url.Values, body []byte, out interface{}) error {
+	uri := p.endpoint + path
+	if len(query) > 0 {
+		uri += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, uri, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	creds := p.creds
+	if creds == nil {
+		if creds, err = resolveCredentials(); err != nil {
+			return err
+		}
+	}
+	if err = sign(req, body, creds, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bb, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode > 299 {
+		return fmt.Errorf("route53 API returned HTTP %v: %v", resp.StatusCode, strings.TrimSpace(string(bb)))
+	}
+	if out != nil {
+		return xml.Unmarshal(bb, out)
+	}
+	return nil
+}
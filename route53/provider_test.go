@@ -0,0 +1,64 @@
+//go:build !integration
+// +build !integration
+
+package route53_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fn "knative.dev/kn-plugin-func"
+	"knative.dev/kn-plugin-func/route53"
+)
+
+// TestProvide ensures that providing DNS for a function upserts a CNAME
+// named for the function as a subdomain of the hosted zone, pointing at
+// the configured target, with requests signed for the standard chain.
+func TestProvide(t *testing.T) {
+	var upserted string
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Fatal("request not signed")
+		}
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/hostedzone/ZONE123"):
+			io.WriteString(w, `<GetHostedZoneResponse><HostedZone><Id>/hostedzone/ZONE123</Id><Name>example.com.</Name></HostedZone></GetHostedZoneResponse>`)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/rrset"):
+			io.WriteString(w, `<ListResourceRecordSetsResponse><ResourceRecordSets></ResourceRecordSets></ListResourceRecordSetsResponse>`)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/rrset"):
+			bb, _ := io.ReadAll(r.Body)
+			upserted = string(bb)
+			io.WriteString(w, `<ChangeResourceRecordSetsResponse></ChangeResourceRecordSetsResponse>`)
+		default:
+			t.Fatalf("unexpected request: %v %v", r.Method, r.URL)
+		}
+	}))
+	defer s.Close()
+
+	provider := route53.NewDNSProvider("ZONE123", "lb.example.org",
+		route53.WithEndpoint(s.URL),
+		route53.WithStaticCredentials("AKIATEST", "secret", ""))
+
+	if err := provider.Provide(fn.Function{Name: "my-func"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(upserted, "<Action>UPSERT</Action>") {
+		t.Fatalf("expected an UPSERT change, got: %v", upserted)
+	}
+	if !strings.Contains(upserted, "<Name>my-func.example.com.</Name>") {
+		t.Fatalf("expected record named for the function, got: %v", upserted)
+	}
+	if !strings.Contains(upserted, "<Value>lb.example.org</Value>") {
+		t.Fatalf("expected record pointing at the target, got: %v", upserted)
+	}
+
+	// Providing again is idempotent: the same UPSERT is simply re-applied.
+	if err := provider.Provide(fn.Function{Name: "my-func"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,6 +3,7 @@ package s2i
 import (
 	"archive/tar"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,8 +12,11 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 
+	"github.com/containerd/containerd/platforms"
 	"github.com/docker/docker/api/types"
 	dockerClient "github.com/docker/docker/client"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -48,11 +52,21 @@ type DockerClient interface {
 
 // Builder of functions using the s2i subsystem.
 type Builder struct {
-	name     string
-	verbose  bool
-	impl     build.Builder // S2I builder implementation (aka "Strategy")
-	cli      DockerClient
-	platform string
+	name         string
+	verbose      bool
+	impl         build.Builder // S2I builder implementation (aka "Strategy")
+	cli          DockerClient
+	platform     string
+	platforms    []string
+	buildSecrets map[string]string
+	imageLabels  map[string]string
+	incremental  bool
+	// excludes, when set, replaces the runtime's default build context
+	// exclude patterns (see WithExcludes).
+	excludes []string
+	// scanThreshold, when set, fails builds whose image contains known
+	// vulnerabilities at or above the given severity (see WithVulnScan).
+	scanThreshold Severity
 }
 
 type Option func(*Builder)
@@ -91,6 +105,63 @@ func WithPlatform(platform string) Option {
 	}
 }
 
+// WithImageLabels provides additional labels to stamp onto the resulting
+// image, applied after (and therefore taking precedence over) those derived
+// from the function itself.
+func WithImageLabels(labels map[string]string) Option {
+	return func(b *Builder) {
+		b.imageLabels = labels
+	}
+}
+
+// WithPlatforms sets the target platforms (e.g. "linux/amd64",
+// "linux/arm64") for a multi-platform build.  One image is built per
+// platform, tagged with a platform suffix (see docker.PlatformTag), which
+// the pusher assembles into a manifest list.  When zero or one platform is
+// provided the builder behaves as a single-platform build (defaulting to
+// the host platform).
+// Multi-platform builds are currently supported by the s2i builder only;
+// the pack builder builds for the host platform regardless of this setting.
+func WithPlatforms(platforms []string) Option {
+	return func(b *Builder) {
+		b.platforms = platforms
+	}
+}
+
+// WithBuildSecrets provides named secret values (e.g. a private module proxy
+// token) which are made available to the build (assemble) step as files at
+// /run/secrets/<name>, and are excluded from the resulting image, mirroring
+// docker BuildKit secret mounts.  Values are held only in memory and in a
+// temporary directory removed when the build completes; they are never
+// written to func.yaml or the function's .func directory.
+func WithBuildSecrets(secrets map[string]string) Option {
+	return func(b *Builder) {
+		b.buildSecrets = secrets
+	}
+}
+
+// WithIncremental enables incremental rebuilds: when the function's
+// dependency manifests (package.json, go.mod, requirements.txt, etc) are
+// unchanged since the last build, s2i is instructed to reuse the previous
+// image's artifacts, skipping the costly dependency-install portion of the
+// assemble step and performing only a source-sync rebuild.  Disabled by
+// default.
+func WithIncremental(enabled bool) Option {
+	return func(b *Builder) {
+		b.incremental = enabled
+	}
+}
+
+// WithExcludes replaces the runtime's default build context exclude patterns
+// (regular expressions) with those given.  The base excludes (.git, .env,
+// .func) and any patterns from the project's .funcignore file still apply.
+// Provide an empty non-nil slice to upload everything but the base excludes.
+func WithExcludes(patterns []string) Option {
+	return func(b *Builder) {
+		b.excludes = patterns
+	}
+}
+
 // NewBuilder creates a new instance of a Builder with static defaults.
 func NewBuilder(options ...Option) *Builder {
 	b := &Builder{name: DefaultName}
@@ -101,6 +172,36 @@ func NewBuilder(options ...Option) *Builder {
 }
 
 func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
+	// Default is a single-platform build for the host (or the single
+	// explicitly-requested platform).
+	if len(b.platforms) <= 1 {
+		platform := b.platform
+		if len(b.platforms) == 1 {
+			platform = b.platforms[0]
+		}
+		if err = b.buildPlatform(ctx, f, platform, f.Image); err != nil {
+			return
+		}
+		return b.scan(ctx, f, f.Image)
+	}
+
+	// Multi-platform: one image is built per requested platform, each tagged
+	// with a platform suffix.  The per-platform images are assembled into a
+	// manifest list (whose digest is recorded on the function) at push time.
+	for _, platform := range b.platforms {
+		if err = b.buildPlatform(ctx, f, platform, docker.PlatformTag(f.Image, platform)); err != nil {
+			return
+		}
+		if err = b.scan(ctx, f, docker.PlatformTag(f.Image, platform)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// buildPlatform builds the function for the given platform (empty for the
+// host platform), tagging the resultant image with the given tag.
+func (b *Builder) buildPlatform(ctx context.Context, f fn.Function, platform, tag string) (err error) {
 	// TODO this function currently doesn't support private s2i builder images since credentials are not set
 
 	// Builder image from the function if defined, default otherwise.
@@ -109,8 +210,8 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 		return
 	}
 
-	if b.platform != "" {
-		builderImage, err = docker.GetPlatformImage(builderImage, b.platform)
+	if platform != "" {
+		builderImage, err = docker.GetPlatformImage(builderImage, platform)
 		if err != nil {
 			return fmt.Errorf("cannot get platform specific image reference: %w", err)
 		}
@@ -119,7 +220,7 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 	// Build Config
 	cfg := &api.Config{}
 	cfg.Quiet = !b.verbose
-	cfg.Tag = f.Image
+	cfg.Tag = tag
 	cfg.Source = &git.URL{URL: url.URL{Path: f.Root}, Type: git.URLTypeLocal}
 	cfg.BuilderImage = builderImage
 	cfg.BuilderPullPolicy = api.DefaultBuilderPullPolicy
@@ -127,6 +228,33 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 	cfg.RuntimeImagePullPolicy = api.DefaultRuntimeImagePullPolicy
 	cfg.DockerConfig = s2idocker.GetDefaultDockerConfig()
 
+	// An explicitly configured run image becomes the base of the resulting
+	// image; the builder image's default is used otherwise.
+	if f.Build.RunImage != "" {
+		cfg.RuntimeImage = f.Build.RunImage
+	}
+
+	// Stamp the image with the function's labels plus standard OCI
+	// provenance labels, overlaid with any extras provided to the builder.
+	if cfg.Labels, err = f.ImageLabels(); err != nil {
+		return
+	}
+	for k, v := range b.imageLabels {
+		cfg.Labels[k] = v
+	}
+
+	// Incremental
+	// When enabled, and the function's dependency manifests are unchanged
+	// since the last recorded build, reuse the previous image's artifacts
+	// rather than reinstalling dependencies.
+	depsChanged := true
+	if b.incremental {
+		if depsChanged, err = dependenciesChanged(f); err != nil {
+			return
+		}
+		cfg.Incremental = !depsChanged
+	}
+
 	tmp, err := os.MkdirTemp("", "s2i-build")
 	if err != nil {
 		return fmt.Errorf("cannot create temporary dir for s2i build: %w", err)
@@ -153,11 +281,35 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 	cfg.ScriptsURL = scriptURL
 
 	// Excludes
-	// Do not include .git, .env, .func or any language-specific cache directories
-	// (node_modules, etc) in the tar file sent to the builder, as this both
-	// bloats the build process and can cause unexpected errors in the resultant
-	// function.
-	cfg.ExcludeRegExp = "(^|/)\\.git|\\.env|\\.func|node_modules(/|$)"
+	// Do not include .git, .env, .func or any language-specific cache and
+	// dependency directories (node_modules, etc) in the tar file sent to the
+	// builder, as this both bloats the build process and can cause unexpected
+	// errors in the resultant function.
+	if cfg.ExcludeRegExp, err = b.excludeRegexp(f); err != nil {
+		return
+	}
+
+	// Build secrets
+	// Written to a temporary directory (removed on completion) and injected
+	// into the assemble step at /run/secrets, excluded from the resulting
+	// image (Keep=false) so credentials are never baked into a layer.
+	if len(b.buildSecrets) > 0 {
+		var secretsDir string
+		if secretsDir, err = os.MkdirTemp("", "s2i-secrets"); err != nil {
+			return fmt.Errorf("cannot create temporary dir for build secrets: %w", err)
+		}
+		defer os.RemoveAll(secretsDir)
+		for name, value := range b.buildSecrets {
+			if err = os.WriteFile(filepath.Join(secretsDir, name), []byte(value), 0600); err != nil {
+				return fmt.Errorf("cannot write build secret %q: %w", name, err)
+			}
+		}
+		cfg.Injections = append(cfg.Injections, api.VolumeSpec{
+			Source:      secretsDir,
+			Destination: "/run/secrets",
+			Keep:        false,
+		})
+	}
 
 	// Environment variables
 	// Build Envs have local env var references interpolated then added to the
@@ -170,6 +322,33 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 		cfg.Environment = append(cfg.Environment, api.EnvironmentSpec{Name: k, Value: v})
 	}
 
+	// Platform-scoped build envs are applied in addition to the above, but
+	// only when building for their platform (the host platform when none was
+	// requested explicitly).
+	target := platform
+	if target == "" {
+		target = "linux/" + runtime.GOARCH
+	}
+	for p, envs := range f.Build.PlatformBuildEnvs {
+		if !samePlatform(p, target) {
+			continue
+		}
+		platformEnvs, err := fn.Interpolate(envs)
+		if err != nil {
+			return err
+		}
+		for k, v := range platformEnvs {
+			cfg.Environment = append(cfg.Environment, api.EnvironmentSpec{Name: k, Value: v})
+		}
+	}
+
+	// Build args are build-tool directives rather than container env.  For
+	// s2i they are conveyed via the build config environment, applied after
+	// BuildEnvs such that explicit args take precedence.
+	for k, v := range f.Build.BuildArgs {
+		cfg.Environment = append(cfg.Environment, api.EnvironmentSpec{Name: k, Value: v})
+	}
+
 	// Validate the config
 	if errs := validation.ValidateConfig(cfg); len(errs) > 0 {
 		for _, e := range errs {
@@ -265,7 +444,7 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 	}()
 
 	opts := types.ImageBuildOptions{
-		Tags: []string{f.Image},
+		Tags: []string{tag},
 	}
 
 	resp, err := client.ImageBuild(ctx, pr, opts)
@@ -287,9 +466,125 @@ func (b *Builder) Build(ctx context.Context, f fn.Function) (err error) {
 		return fmt.Errorf("cannot build the app: %s", errMsg)
 	}
 
+	// Record the dependency manifests of this successful build such that a
+	// subsequent incremental build can detect whether they have changed.
+	if b.incremental && depsChanged {
+		if err = recordDependencies(f); err != nil {
+			return fmt.Errorf("cannot record dependency manifests: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// baseExcludes are regular expression alternatives for paths which are never
+// included in the build context, regardless of runtime.
+var baseExcludes = []string{"(^|/)\\.git", "\\.env", "\\.func"}
+
+// runtimeExcludes are additional per-runtime exclude patterns for directories
+// which are regenerated during the assemble step and therefore only bloat the
+// upload (and can cause unexpected errors in the resultant function).
+var runtimeExcludes = map[string][]string{
+	"node":       {"node_modules(/|$)"},
+	"typescript": {"node_modules(/|$)"},
+	"python":     {"(^|/)__pycache__(/|$)", "(^|/)\\.?venv(/|$)"},
+}
+
+// funcignoreFile is an optional file in the function's root whose non-empty,
+// non-comment lines are additional exclude patterns for the build context.
+const funcignoreFile = ".funcignore"
+
+// excludeRegexp composes the regular expression of paths excluded from the
+// build context: the base excludes, the runtime's defaults (unless overridden
+// via WithExcludes), and any patterns from the project's .funcignore file.
+func (b *Builder) excludeRegexp(f fn.Function) (string, error) {
+	patterns := append([]string{}, baseExcludes...)
+	if b.excludes != nil {
+		patterns = append(patterns, b.excludes...)
+	} else {
+		patterns = append(patterns, runtimeExcludes[f.Runtime]...)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(f.Root, funcignoreFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	for _, line := range strings.Split(string(bb), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err = regexp.Compile(line); err != nil {
+			return "", fmt.Errorf("invalid pattern %q in %v: %w", line, funcignoreFile, err)
+		}
+		patterns = append(patterns, line)
+	}
+
+	return strings.Join(patterns, "|"), nil
+}
+
+// dependencyManifests are files whose modification indicates the function's
+// dependencies may have changed, necessitating a full (non-incremental)
+// rebuild.
+var dependencyManifests = []string{
+	"package.json", "package-lock.json", // node, typescript
+	"go.mod", "go.sum", // go
+	"requirements.txt", "Pipfile.lock", // python
+	"pom.xml", "build.gradle", // quarkus, springboot
+	"Cargo.toml", "Cargo.lock", // rust
+}
+
+// depsStamp is the name of the file within the function's run data
+// directory (.func) recording the dependency manifest fingerprint of the
+// last build.
+const depsStamp = "s2i-dependencies"
+
+// dependencyFingerprint returns a hash of the contents of the function's
+// dependency manifests.  Absent manifests simply do not contribute.
+func dependencyFingerprint(f fn.Function) (string, error) {
+	h := sha256.New()
+	for _, name := range dependencyManifests {
+		bb, err := os.ReadFile(filepath.Join(f.Root, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%v:", name)
+		h.Write(bb)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// dependenciesChanged returns whether the function's dependency manifests
+// differ from those recorded at the last build (or no build was recorded).
+func dependenciesChanged(f fn.Function) (bool, error) {
+	hash, err := dependencyFingerprint(f)
+	if err != nil {
+		return true, err
+	}
+	bb, err := os.ReadFile(filepath.Join(f.Root, fn.RunDataDir, depsStamp))
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	} else if err != nil {
+		return true, err
+	}
+	return string(bb) != hash, nil
+}
+
+// recordDependencies persists the fingerprint of the function's dependency
+// manifests for comparison by subsequent incremental builds.
+func recordDependencies(f fn.Function) error {
+	hash, err := dependencyFingerprint(f)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Join(f.Root, fn.RunDataDir), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(f.Root, fn.RunDataDir, depsStamp), []byte(hash), os.ModePerm)
+}
+
 func parseBuildResponse(r io.Reader, w io.Writer) (errorMessage string, err error) {
 	obj := struct {
 		ErrorDetail struct {
@@ -372,3 +667,34 @@ func BuilderImage(f fn.Function, builderName string) (string, error) {
 	// delegate as the logic is shared amongst builders
 	return builders.Image(f, builderName, DefaultBuilderImages)
 }
+
+// Validate that this builder is an appropriate choice for the function:
+// the function must not declare a different builder, and a builder image
+// must be resolvable for its runtime.
+func (b *Builder) Validate(f fn.Function) error {
+	if f.Build.Builder != "" && f.Build.Builder != builders.S2I {
+		return fmt.Errorf("function is configured to build with the %q builder, but the %q builder was selected", f.Build.Builder, builders.S2I)
+	}
+	if _, err := BuilderImage(f, builders.S2I); err != nil {
+		var errNoDefault builders.ErrNoDefaultImage
+		if errors.As(err, &errNoDefault) {
+			return fmt.Errorf("%v. Supported runtimes are: %v", err, strings.Join(builders.Runtimes(DefaultBuilderImages), ", "))
+		}
+		return err
+	}
+	return nil
+}
+
+// samePlatform returns whether two platform specifiers (e.g. "amd64",
+// "linux/amd64") denote the same normalized platform.
+func samePlatform(a, b string) bool {
+	pa, err := platforms.Parse(a)
+	if err != nil {
+		return false
+	}
+	pb, err := platforms.Parse(b)
+	if err != nil {
+		return false
+	}
+	return platforms.Format(platforms.Normalize(pa)) == platforms.Format(platforms.Normalize(pb))
+}
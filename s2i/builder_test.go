@@ -13,8 +13,11 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/registry"
@@ -204,6 +207,49 @@ func Test_BuildEnvs(t *testing.T) {
 	}
 }
 
+// Test_PlatformBuildEnvs ensures that platform-scoped build envs are applied
+// only when building for the matching platform.
+func Test_PlatformBuildEnvs(t *testing.T) {
+	var (
+		matchName    = "MATCHED"
+		matchValue   = "yes"
+		otherName    = "UNMATCHED"
+		otherValue   = "no"
+		hostPlatform = "linux/" + runtime.GOARCH
+		other        = "linux/s390x"
+		f            = fn.Function{
+			Runtime: "node",
+			Build: fn.BuildSpec{
+				PlatformBuildEnvs: map[string][]fn.Env{
+					hostPlatform: {{Name: &matchName, Value: &matchValue}},
+					other:        {{Name: &otherName, Value: &otherValue}},
+				},
+			},
+		}
+		i = &mockImpl{}
+		c = mockDocker{}
+		b = s2i.NewBuilder(s2i.WithImpl(i), s2i.WithDockerClient(c))
+	)
+	i.BuildFn = func(cfg *api.Config) (r *api.Result, err error) {
+		var matched bool
+		for _, v := range cfg.Environment {
+			if v.Name == matchName {
+				matched = true
+			}
+			if v.Name == otherName {
+				t.Fatalf("env scoped to platform %q applied during %q build", other, hostPlatform)
+			}
+		}
+		if !matched {
+			t.Fatalf("env scoped to platform %q was not applied", hostPlatform)
+		}
+		return
+	}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestS2IScriptURL(t *testing.T) {
 	testRegistry := startRegistry(t)
 
@@ -369,6 +415,109 @@ func TestBuildContextUpload(t *testing.T) {
 	}
 }
 
+// TestBuildContextExcludes ensures the runtime's default exclude patterns and
+// those from the project's .funcignore are honored, keeping dependency
+// directories and ignored files out of the uploaded build context.
+func TestBuildContextExcludes(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"index.js":                "handler",
+		"node_modules/dep/dep.js": "dep",
+		"secret.txt":              "s3cr3t",
+		".funcignore":             "# local-only files\nsecret\\.txt\n",
+	}
+	for p, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(root, p)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, p), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cli := mockDocker{
+		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+			var names []string
+			tr := tar.NewReader(context)
+			for {
+				hdr, err := tr.Next()
+				if err != nil {
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					return types.ImageBuildResponse{}, err
+				}
+				names = append(names, hdr.Name)
+			}
+			for _, n := range names {
+				if strings.Contains(n, "node_modules") || strings.Contains(n, "secret.txt") {
+					return types.ImageBuildResponse{}, fmt.Errorf("excluded file %q was uploaded", n)
+				}
+			}
+			for _, want := range []string{"Dockerfile", "index.js"} {
+				found := false
+				for _, n := range names {
+					if n == want {
+						found = true
+					}
+				}
+				if !found {
+					return types.ImageBuildResponse{}, fmt.Errorf("expected %q in build context", want)
+				}
+			}
+			return types.ImageBuildResponse{
+				Body:   io.NopCloser(strings.NewReader(`{"stream": "OK!"}`)),
+				OSType: "linux",
+			}, nil
+		},
+	}
+
+	impl := &mockImpl{
+		BuildFn: func(config *api.Config) (*api.Result, error) {
+			// Emulate the library's copy of the source into the Dockerfile
+			// context, which honors ExcludeRegExp.
+			exclude, err := regexp.Compile(config.ExcludeRegExp)
+			if err != nil {
+				return nil, err
+			}
+			dst := filepath.Dir(config.AsDockerfile)
+			err = filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(root, path)
+				if err != nil || rel == "." {
+					return err
+				}
+				if exclude.MatchString(filepath.ToSlash(rel)) {
+					if fi.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if fi.IsDir() {
+					return os.MkdirAll(filepath.Join(dst, rel), 0755)
+				}
+				bb, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				return os.WriteFile(filepath.Join(dst, rel), bb, 0644)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return nil, os.WriteFile(config.AsDockerfile, []byte("FROM scratch"), 0644)
+		},
+	}
+
+	f := fn.Function{Runtime: "node", Root: root}
+	b := s2i.NewBuilder(s2i.WithImpl(impl), s2i.WithDockerClient(cli))
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestBuildFail(t *testing.T) {
 	cli := mockDocker{
 		build: func(ctx context.Context, context io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
@@ -434,3 +583,249 @@ func (n notFoundErr) Error() string {
 func (n notFoundErr) NotFound() bool {
 	return true
 }
+
+// Test_BuilderValidate ensures that the builder reports a mismatched
+// builder selection and unsupported runtimes (including the list of
+// supported runtimes) before a build commences.
+func Test_BuilderValidate(t *testing.T) {
+	b := s2i.NewBuilder()
+
+	// A function declaring a different builder is rejected
+	f := fn.Function{Runtime: "node", Build: fn.BuildSpec{Builder: builders.Pack}}
+	if err := b.Validate(f); err == nil {
+		t.Fatal("expected error validating a function declaring a different builder")
+	}
+
+	// A supported runtime passes
+	f = fn.Function{Runtime: "node", Build: fn.BuildSpec{Builder: builders.S2I}}
+	if err := b.Validate(f); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unsupported runtime errors, listing those supported
+	f = fn.Function{Runtime: "go", Build: fn.BuildSpec{Builder: builders.S2I}}
+	err := b.Validate(f)
+	if err == nil {
+		t.Fatal("expected error validating an unsupported runtime")
+	}
+	if !strings.Contains(err.Error(), "node") {
+		t.Fatalf("expected error to list supported runtimes, got: %v", err)
+	}
+
+	// An overridden builder image bypasses the default image requirement
+	f = fn.Function{Runtime: "go", Build: fn.BuildSpec{
+		Builder:       builders.S2I,
+		BuilderImages: map[string]string{builders.S2I: "example.com/custom/builder:latest"},
+	}}
+	if err := b.Validate(f); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test_ImageLabels ensures that images are stamped with the function's
+// labels plus standard OCI provenance labels, overlaid with any extras
+// provided via WithImageLabels.
+func Test_ImageLabels(t *testing.T) {
+	var (
+		f = fn.Function{
+			Name:    "test-labels",
+			Runtime: "node",
+			Created: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+			Build: fn.BuildSpec{
+				Git: fn.Git{URL: "https://example.com/repo.git"},
+			},
+		}
+		i = &mockImpl{}
+		c = mockDocker{}
+		b = s2i.NewBuilder(
+			s2i.WithImpl(i),
+			s2i.WithDockerClient(c),
+			s2i.WithImageLabels(map[string]string{"team": "platform"}))
+	)
+	i.BuildFn = func(cfg *api.Config) (r *api.Result, err error) {
+		if cfg.Labels["org.opencontainers.image.created"] != "2022-01-02T03:04:05Z" {
+			t.Errorf("missing or incorrect created label: %q", cfg.Labels["org.opencontainers.image.created"])
+		}
+		if cfg.Labels["org.opencontainers.image.source"] != "https://example.com/repo.git" {
+			t.Errorf("missing or incorrect source label: %q", cfg.Labels["org.opencontainers.image.source"])
+		}
+		if cfg.Labels["team"] != "platform" {
+			t.Errorf("missing extra label provided via WithImageLabels")
+		}
+		return
+	}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Test_BuilderIncremental ensures that when incremental builds are enabled
+// the s2i config requests an incremental build only when the function's
+// dependency manifests are unchanged since the last successful build.
+func Test_BuilderIncremental(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "package.json"), []byte(`{"dependencies":{}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := fn.Function{Root: root, Runtime: "node"}
+
+	var incremental bool
+	i := &mockImpl{
+		BuildFn: func(cfg *api.Config) (*api.Result, error) {
+			incremental = cfg.Incremental
+			return &api.Result{}, nil
+		},
+	}
+	b := s2i.NewBuilder(s2i.WithIncremental(true), s2i.WithImpl(i), s2i.WithDockerClient(mockDocker{}))
+
+	// First build: no record of previous dependencies, so full build
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if incremental {
+		t.Fatal("first build should not be incremental")
+	}
+
+	// Second build with unchanged manifests: incremental
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if !incremental {
+		t.Fatal("second build with unchanged dependencies should be incremental")
+	}
+
+	// Changing a manifest forces a full build again
+	if err := os.WriteFile(filepath.Join(root, "package.json"), []byte(`{"dependencies":{"a":"1"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if incremental {
+		t.Fatal("build after manifest change should not be incremental")
+	}
+
+	// Disabled by default
+	b = s2i.NewBuilder(s2i.WithImpl(i), s2i.WithDockerClient(mockDocker{}))
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if incremental {
+		t.Fatal("incremental should be disabled by default")
+	}
+}
+
+// Test_BuilderRunImage ensures that a run image configured on the function
+// becomes the runtime (final stage base) image of the s2i build, with the
+// builder image's default used otherwise.
+func Test_BuilderRunImage(t *testing.T) {
+	var runtimeImage string
+	i := &mockImpl{
+		BuildFn: func(cfg *api.Config) (*api.Result, error) {
+			runtimeImage = cfg.RuntimeImage
+			return &api.Result{}, nil
+		},
+	}
+	b := s2i.NewBuilder(s2i.WithImpl(i), s2i.WithDockerClient(mockDocker{}))
+
+	f := fn.Function{Runtime: "node"}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if runtimeImage != "" {
+		t.Fatalf("expected no runtime image by default, got '%v'", runtimeImage)
+	}
+
+	f.Build.RunImage = "example.com/user/approved-base"
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	if runtimeImage != "example.com/user/approved-base" {
+		t.Fatalf("expected the configured run image, got '%v'", runtimeImage)
+	}
+}
+
+// Test_BuildArgs ensures that build args configured on the function are
+// conveyed to the s2i build config environment.
+func Test_BuildArgs(t *testing.T) {
+	var environment []api.EnvironmentSpec
+	i := &mockImpl{
+		BuildFn: func(cfg *api.Config) (*api.Result, error) {
+			environment = cfg.Environment
+			return &api.Result{}, nil
+		},
+	}
+	b := s2i.NewBuilder(s2i.WithImpl(i), s2i.WithDockerClient(mockDocker{}))
+
+	f := fn.Function{Runtime: "node"}
+	f.Build.BuildArgs = map[string]string{"SCRIPT": "build:prod"}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range environment {
+		if e.Name == "SCRIPT" && e.Value == "build:prod" {
+			return // success
+		}
+	}
+	t.Fatalf("build arg not found in s2i build config environment: %v", environment)
+}
+
+// Test_BuilderVulnScan ensures that, when enabled, a successful build is
+// followed by a vulnerability scan whose results are persisted, and that
+// the build fails when vulnerabilities at or above the threshold are found.
+func Test_BuilderVulnScan(t *testing.T) {
+	root := t.TempDir()
+
+	// A stand-in scanner which emits the JSON in $FAKE_SCAN_OUTPUT.
+	bin := filepath.Join(root, "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(bin, "grype")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat \"$FAKE_SCAN_OUTPUT\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	results := filepath.Join(root, "results.json")
+	if err := os.WriteFile(results, []byte(`{"matches":[
+		{"vulnerability":{"id":"CVE-2023-0001","severity":"Critical"},"artifact":{"name":"libfoo","version":"1.0"}},
+		{"vulnerability":{"id":"CVE-2023-0002","severity":"Low"},"artifact":{"name":"libbar","version":"2.0"}}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FAKE_SCAN_OUTPUT", results)
+
+	var (
+		i = &mockImpl{BuildFn: func(cfg *api.Config) (*api.Result, error) { return nil, nil }}
+		c = mockDocker{}
+		f = fn.Function{Runtime: "node", Root: root}
+		b = s2i.NewBuilder(s2i.WithImpl(i), s2i.WithDockerClient(c),
+			s2i.WithVulnScan(s2i.SeverityHigh))
+	)
+
+	// A critical vulnerability at or above the High threshold fails the build.
+	err := b.Build(context.Background(), f)
+	if err == nil {
+		t.Fatal("expected the build to fail on the critical vulnerability")
+	}
+	if !strings.Contains(err.Error(), "CVE-2023-0001") {
+		t.Fatalf("expected the failing CVE to be listed, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "CVE-2023-0002") {
+		t.Fatalf("low severity CVE should not fail the build, got: %v", err)
+	}
+
+	// The raw results are persisted for inspection regardless of outcome.
+	if _, err := os.Stat(filepath.Join(root, ".func", "scan.json")); err != nil {
+		t.Fatalf("expected scan results to be written: %v", err)
+	}
+
+	// Only low-severity findings: the build succeeds.
+	if err := os.WriteFile(results, []byte(`{"matches":[
+		{"vulnerability":{"id":"CVE-2023-0002","severity":"Low"},"artifact":{"name":"libbar","version":"2.0"}}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Build(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+}
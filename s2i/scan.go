@@ -0,0 +1,127 @@
+package s2i
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	fn "knative.dev/kn-plugin-func"
+)
+
+// Severity of a known vulnerability, used as the threshold at or above
+// which a build is failed when vulnerability scanning is enabled.
+type Severity string
+
+const (
+	SeverityLow      Severity = "Low"
+	SeverityMedium   Severity = "Medium"
+	SeverityHigh     Severity = "High"
+	SeverityCritical Severity = "Critical"
+)
+
+// scanFile is the name of the file within the function's run data
+// directory to which the raw scan results of the most recent build are
+// written for inspection.
+const scanFile = "scan.json"
+
+// scannerBin is the vulnerability scanner executed against built images.
+var scannerBin = "grype"
+
+// severityRank orders severities such that thresholds can be compared.
+// Unrecognized severities rank lowest and thus never fail a build.
+var severityRank = map[string]int{
+	"negligible": 1,
+	"low":        2,
+	"medium":     3,
+	"high":       4,
+	"critical":   5,
+}
+
+// WithVulnScan instructs the builder to scan the built image for known
+// vulnerabilities, failing the build when any are found at or above the
+// given severity threshold.  Disabled by default (empty threshold).
+// Requires the 'grype' scanner on the PATH.
+func WithVulnScan(threshold Severity) Option {
+	return func(b *Builder) {
+		b.scanThreshold = threshold
+	}
+}
+
+// scan the given image for known vulnerabilities, writing the raw results
+// to .func/scan.json and returning an error listing any found at or above
+// the configured threshold.  A no-op when scanning is not enabled.
+func (b *Builder) scan(ctx context.Context, f fn.Function, image string) error {
+	if b.scanThreshold == "" {
+		return nil
+	}
+	threshold, ok := severityRank[strings.ToLower(string(b.scanThreshold))]
+	if !ok {
+		return fmt.Errorf("unrecognized vulnerability severity threshold %q", b.scanThreshold)
+	}
+
+	bin, err := exec.LookPath(scannerBin)
+	if err != nil {
+		return fmt.Errorf("vulnerability scanning requires the %q scanner on the PATH: %w", scannerBin, err)
+	}
+
+	if b.verbose {
+		fmt.Printf("Scanning %v for vulnerabilities\n", image)
+	}
+	cmd := exec.CommandContext(ctx, bin, image, "-o", "json")
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err() // canceled or timed out; not a scan failure
+		}
+		return fmt.Errorf("vulnerability scan of %v failed: %w", image, err)
+	}
+
+	// Persist the raw results for later inspection regardless of outcome.
+	if err = os.MkdirAll(filepath.Join(f.Root, fn.RunDataDir), os.ModePerm); err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(f.Root, fn.RunDataDir, scanFile), output, os.ModePerm); err != nil {
+		return err
+	}
+
+	// The scanner's JSON output lists one match per vulnerable artifact.
+	var results struct {
+		Matches []struct {
+			Vulnerability struct {
+				ID       string `json:"id"`
+				Severity string `json:"severity"`
+			} `json:"vulnerability"`
+			Artifact struct {
+				Name    string `json:"name"`
+				Version string `json:"version"`
+			} `json:"artifact"`
+		} `json:"matches"`
+	}
+	if err = json.Unmarshal(output, &results); err != nil {
+		return fmt.Errorf("cannot parse scan results: %w", err)
+	}
+
+	var failing []string
+	for _, match := range results.Matches {
+		if severityRank[strings.ToLower(match.Vulnerability.Severity)] >= threshold {
+			failing = append(failing, fmt.Sprintf("  %v (%v) in %v %v",
+				match.Vulnerability.ID, match.Vulnerability.Severity,
+				match.Artifact.Name, match.Artifact.Version))
+		}
+	}
+	if len(failing) > 0 {
+		return fmt.Errorf("image %v contains %d vulnerabilities at or above severity %q:\n%v\nfull results in %v",
+			image, len(failing), b.scanThreshold, strings.Join(failing, "\n"),
+			filepath.Join(fn.RunDataDir, scanFile))
+	}
+
+	if b.verbose {
+		fmt.Printf("No vulnerabilities at or above severity %q found in %v\n", b.scanThreshold, image)
+	}
+	return nil
+}
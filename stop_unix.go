@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package function
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// stopRun signals the process owning the given run record to shut down
+// gracefully (the Job.Stop path).  A record whose process no longer exists
+// is stale: it is removed and ErrNotRunning returned.
+func stopRun(f Function, record runRecord) error {
+	proc, err := os.FindProcess(record.PID)
+	if err != nil || proc.Signal(syscall.Signal(0)) != nil {
+		// The recorded process is gone; remove the stale record.
+		removeRunRecord(f)
+		return ErrNotRunning
+	}
+
+	// Signal the owning process to shut down gracefully; it stops the job
+	// (and removes the run record) on termination.
+	if err = proc.Signal(syscall.SIGTERM); err != nil {
+		return fmt.Errorf("failed to stop the function: %w", err)
+	}
+	return nil
+}
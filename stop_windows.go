@@ -0,0 +1,14 @@
+//go:build windows
+// +build windows
+
+package function
+
+import "errors"
+
+// stopRun is unsupported on Windows, which offers neither the signal-based
+// liveness check nor graceful termination (SIGTERM) used on other
+// platforms.  The run record is left in place, as a genuinely running
+// function cannot be distinguished from a stale record.
+func stopRun(Function, runRecord) error {
+	return errors.New("stopping a local run from a separate process is not supported on this platform")
+}
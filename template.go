@@ -30,6 +30,10 @@ func (t template) Fullname() string {
 	return t.repository + "/" + t.name
 }
 
+func (t template) PostCreate() string {
+	return t.config.PostCreate
+}
+
 func (t template) Write(ctx context.Context, f *Function) error {
 
 	// Apply fields from the template onto the function itself (Denormalize).
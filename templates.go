@@ -3,6 +3,9 @@ package function
 import (
 	"context"
 	"errors"
+	"io/fs"
+	"path"
+	"sort"
 	"strings"
 )
 
@@ -89,6 +92,65 @@ func (t *Templates) Get(runtime, fullname string) (Template, error) {
 	return repo.Template(runtime, tplName)
 }
 
+// Preview returns the contents of the files the named template would write,
+// keyed by path relative to the function root, without touching disk.
+// As with Write, the template's manifest.yaml is elided.
+func (t *Templates) Preview(runtime, name string) (map[string][]byte, error) {
+	tpl, err := t.Get(runtime, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The concrete template type carries the filesystem from which it would
+	// be written.  Only valid lookups return it, so failing the assertion is
+	// equivalent to the template not existing.
+	impl, ok := tpl.(template)
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+
+	isManifest := func(p string) bool {
+		_, f := path.Split(p)
+		return f == templateManifest
+	}
+
+	files := map[string][]byte{}
+	accessor := maskingFS{fs: impl.fs, masked: isManifest}
+	err = fs.WalkDir(accessor, ".", func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if de.IsDir() {
+			return nil
+		}
+		bb, err := fs.ReadFile(accessor, p)
+		if err != nil {
+			return err
+		}
+		files[p] = bb
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Files lists the paths, relative to the function root, of the files the
+// named template would write, sorted for display.
+func (t *Templates) Files(runtime, name string) ([]string, error) {
+	files, err := t.Preview(runtime, name)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 // Write a function's template to disk.
 // Returns a function which may have been modified dependent on the content
 // of the template (which can define default function fields, builders,
@@ -601,3 +601,95 @@ func TestTemplates_InvocationDefault(t *testing.T) {
 		t.Fatalf("expected '%v' invocation format.  Got '%v'", fn.DefaultInvocationFormat, f.Invocation.Format)
 	}
 }
+
+// TestTemplates_LocalPath ensures that a local templates path can be used
+// as the source of default templates in place of those embedded in the
+// binary, with Write and List consulting the path, and that an invalid or
+// empty path yields an error.
+func TestTemplates_LocalPath(t *testing.T) {
+	// Create test directory
+	root := "testdata/testTemplatesLocalPath"
+	defer Using(t, root)()
+
+	// Client whose default templates are read from a local directory
+	// following the same runtime/template layout as the embedded templates.
+	client := fn.New(
+		fn.WithRegistry(TestRegistry),
+		fn.WithTemplatesPath("testdata/repositories/customTemplateRepo"))
+
+	// Listing templates for a runtime defined in the local path consults
+	// the path rather than the embedded templates.
+	templates, err := client.Templates().List("customRuntime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"customTemplate"}, templates); diff != "" {
+		t.Error("Unexpected templates (-want, +got):", diff)
+	}
+
+	// Creating writes the template from the local path.
+	err = client.Create(fn.Function{
+		Root:     root,
+		Runtime:  "customRuntime",
+		Template: "customTemplate",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "custom.impl")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A nonexistent path errors clearly.
+	client = fn.New(fn.WithTemplatesPath("testdata/doesNotExist"))
+	if _, err = client.Templates().List("go"); err == nil {
+		t.Fatal("expected error for nonexistent templates path")
+	}
+}
+
+// TestTemplates_Preview ensures that the files a template would write can be
+// previewed in-memory, with contents, without touching disk, and that the
+// manifest is elided as it is on write.
+func TestTemplates_Preview(t *testing.T) {
+	client := fn.New(fn.WithRepositoriesPath("testdata/repositories"))
+
+	files, err := client.Templates().Preview("customRuntime", "customTemplateRepo/customTemplate")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := files["custom.impl"]; !ok {
+		t.Errorf("preview missing expected file 'custom.impl', got %v", files)
+	}
+	for name := range files {
+		if filepath.Base(name) == "manifest.yaml" {
+			t.Error("preview should not include the template manifest")
+		}
+	}
+}
+
+// TestTemplates_Files ensures that the sorted list of files a template would
+// write is returned, and that invalid combinations error with the same
+// sentinels used elsewhere.
+func TestTemplates_Files(t *testing.T) {
+	client := fn.New(fn.WithRepositoriesPath("testdata/repositories"))
+
+	names, err := client.Templates().Files("customRuntime", "customTemplateRepo/customTemplate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"custom.impl"}
+	if diff := cmp.Diff(expected, names); diff != "" {
+		t.Error("Unexpected template files (-want, +got):", diff)
+	}
+
+	// Nonexistent template
+	if _, err = client.Templates().Files("go", "customTemplateRepo/doesNotExist"); !errors.Is(err, fn.ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+
+	// Nonexistent runtime
+	if _, err = client.Templates().Files("doesNotExist", "customTemplateRepo/customTemplate"); !errors.Is(err, fn.ErrRuntimeNotFound) {
+		t.Errorf("expected ErrRuntimeNotFound, got %v", err)
+	}
+}
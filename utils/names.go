@@ -23,6 +23,9 @@ type ErrInvalidSecretKey error
 // ErrInvalidLabel indicates the name did not pass label key validation, or the value did not pass label value validation.
 type ErrInvalidLabel error
 
+// ErrInvalidServiceAccountName indicates the name did not pass service account name validation.
+type ErrInvalidServiceAccountName error
+
 // ValidateFunctionName validates that the input name is a valid function name, ie. valid DNS-1035 label.
 // It must consist of lower case alphanumeric characters or '-' and start with an alphabetic character and end with an alphanumeric character.
 // (e.g. 'my-name',  or 'abc-1', regex used for validation is '[a-z]([-a-z0-9]*[a-z0-9])?')
@@ -40,6 +43,22 @@ func ValidateFunctionName(name string) error {
 	return nil
 }
 
+// ValidateServiceAccountName validates that the input name is a valid Kubernetes
+// service account name, ie. a valid DNS-1123 subdomain.
+// It must consist of lower case alphanumeric characters, '-' or '.', and must
+// start and end with an alphanumeric character
+// (e.g. 'my-sa', or 'workload.identity', regex used for validation is
+// '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')
+func ValidateServiceAccountName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		// As with ValidateFunctionName, reuse the upstream message with the
+		// generic subject replaced by the offending service account name.
+		return ErrInvalidServiceAccountName(errors.New(strings.Replace(strings.Join(errs, ""), "a lowercase RFC 1123 subdomain", fmt.Sprintf("Service account name '%v'", name), 1)))
+	}
+
+	return nil
+}
+
 // ValidateEnvVarName validatest that the input name is a valid Kubernetes Environmet Variable name.
 // It must  must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit
 // (e.g. 'my.env-name',  or 'MY_ENV.NAME',  or 'MyEnvName1', regex used for validation is '[-._a-zA-Z][-._a-zA-Z0-9]*'
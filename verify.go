@@ -0,0 +1,80 @@
+package function
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Verifier compiles or typechecks a function's source without producing a
+// container image, providing a fast "does it compile" gate for editors and
+// CI ahead of a full build.  Implementations are runtime-specific; see
+// WithVerifier for registration.
+type Verifier interface {
+	// Verify the given function, returning an error which includes the
+	// compiler's output when the source does not compile.
+	Verify(ctx context.Context, f Function) error
+}
+
+// Verify that the function at the given path compiles, without producing a
+// container image.  A much faster gate than a full build for runtimes whose
+// source is compiled or typechecked (Go, TypeScript, etc).  Runtimes for
+// which no verifier is registered trivially verify.  Separate from the
+// Builder path; no artifacts are retained.
+func (c *Client) Verify(ctx context.Context, path string) error {
+	f, err := NewFunction(path)
+	if err != nil {
+		return err
+	}
+	if !f.Initialized() {
+		return fmt.Errorf("the given path '%v' does not contain an initialized function", path)
+	}
+	verifier, ok := c.verifiers[f.Runtime]
+	if !ok {
+		return nil // no verifier available for the runtime; nothing to check
+	}
+	return verifier.Verify(ctx, f)
+}
+
+// goVerifier confirms a Go function compiles by building it into a
+// throwaway directory.
+type goVerifier struct{}
+
+func (v goVerifier) Verify(ctx context.Context, f Function) error {
+	tmp, err := os.MkdirTemp("", "func-verify")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	// Note -o names a file within the throwaway directory rather than the
+	// directory itself: a directory output errors when the function is,
+	// as is typical, a non-main package.
+	cmd := exec.CommandContext(ctx, "go", "build", "-o", filepath.Join(tmp, "out"), "./...")
+	cmd.Dir = f.Root
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("function does not compile:\n%s", out)
+	}
+	return nil
+}
+
+// tsVerifier confirms a TypeScript function typechecks without emitting
+// compiled output.
+type tsVerifier struct{}
+
+func (v tsVerifier) Verify(ctx context.Context, f Function) error {
+	tsc, err := exec.LookPath("tsc")
+	if err != nil {
+		return fmt.Errorf("cannot verify: the TypeScript compiler 'tsc' was not found in PATH")
+	}
+	cmd := exec.CommandContext(ctx, tsc, "--noEmit")
+	cmd.Dir = f.Root
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("function does not typecheck:\n%s", out)
+	}
+	return nil
+}
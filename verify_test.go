@@ -0,0 +1,64 @@
+//go:build !integration
+// +build !integration
+
+package function_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fn "knative.dev/kn-plugin-func"
+	. "knative.dev/kn-plugin-func/testing"
+)
+
+// TestVerify_Go ensures that verifying a Go function compiles it without
+// producing an image, with compiler output returned on failure.
+func TestVerify_Go(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: "go", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The unmodified template should compile.
+	if err := client.Verify(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+
+	// A source file which does not compile should error, including the
+	// compiler's output.
+	broken := `package function
+
+func Broken() { undefinedSymbol() }
+`
+	if err := os.WriteFile(filepath.Join(root, "broken.go"), []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := client.Verify(context.Background(), root)
+	if err == nil {
+		t.Fatal("expected an error verifying a function which does not compile")
+	}
+	if !strings.Contains(err.Error(), "undefinedSymbol") {
+		t.Fatalf("expected the compiler output in the error, got: %v", err)
+	}
+}
+
+// TestVerify_UnsupportedRuntime ensures runtimes without a registered
+// verifier trivially verify.
+func TestVerify_UnsupportedRuntime(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	client := fn.New(fn.WithRegistry(TestRegistry))
+	if err := client.Create(fn.Function{Runtime: "quarkus", Root: root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Verify(context.Background(), root); err != nil {
+		t.Fatal(err)
+	}
+}